@@ -3,12 +3,16 @@ package middleware
 import (
 	"context"
 	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
 	"github.com/knands42/lorecrafter/internal/usecases"
 	"net/http"
 	"strings"
 )
 
-const UserIDContextKey = "user_id"
+const (
+	UserIDContextKey       = "user_id"
+	TokenPayloadContextKey = "token_payload"
+)
 
 func AuthMiddleware(authUseCase *usecases.AuthUseCase) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -31,9 +35,31 @@ func AuthMiddleware(authUseCase *usecases.AuthUseCase) func(next http.Handler) h
 				return
 			}
 
-			// Add user ID to context
+			// Add user ID and the token payload to context
 			ctx := context.WithValue(r.Context(), UserIDContextKey, token.UserID)
+			ctx = context.WithValue(ctx, TokenPayloadContextKey, token)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// RequireScopes rejects requests whose token or API key wasn't granted all the given scopes.
+// It must run after AuthMiddleware.
+func RequireScopes(scopes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := r.Context().Value(TokenPayloadContextKey).(*domain.TokenPayload)
+			if !ok {
+				utils.WriteJSONError(w, http.StatusUnauthorized, "missing token")
+				return
+			}
+
+			if missing := token.MissingScopes(scopes...); len(missing) > 0 {
+				utils.WriteJSONError(w, http.StatusForbidden, "missing scope: "+strings.Join(missing, ", "))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
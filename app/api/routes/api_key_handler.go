@@ -0,0 +1,148 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+)
+
+// APIKeyHandler handles personal API key HTTP requests
+type APIKeyHandler struct {
+	apiKeyUseCase *usecases.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase *usecases.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// RegisterRoutes registers the API key routes
+func (h *APIKeyHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/me/api-keys", func(r chi.Router) {
+		r.Use(middleware.RequireScopes(domain.ScopeAccount))
+		r.Post("/", middleware.ErrorHandlerMiddleware(h.CreateAPIKey))
+		r.Get("/", middleware.ErrorHandlerMiddleware(h.ListAPIKeys))
+		r.Delete("/{apiKeyID}", middleware.ErrorHandlerMiddleware(h.RevokeAPIKey))
+	})
+}
+
+// CreateAPIKey handles creating an API key
+// @Summary Create an API key
+// @Description Create a personal API key to use as a bearer token in scripts and bots. The key is only returned once.
+// @Description Scopes: read, campaigns:write and characters:write
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.APIKeyInput true "API key details"
+// @Success 201 {object} domain.CreatedAPIKey "API key created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) error {
+	var input domain.APIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	apiKey, err := h.apiKeyUseCase.CreateAPIKey(userID, input)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(apiKey)
+}
+
+// ListAPIKeys handles listing the API keys of the logged user
+// @Summary List my API keys
+// @Description List the API keys of the logged user that weren't revoked
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.APIKey "API keys"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	apiKeys, err := h.apiKeyUseCase.ListAPIKeys(userID)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(apiKeys)
+}
+
+// RevokeAPIKey handles revoking an API key
+// @Summary Revoke an API key
+// @Description Revoke an API key of the logged user, requests made with it are rejected right away
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param apiKeyID path string true "API key ID"
+// @Success 204 "API key revoked successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid API key ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "API key not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/api-keys/{apiKeyID} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) error {
+	apiKeyID, err := uuid.Parse(chi.URLParam(r, "apiKeyID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid API key ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.apiKeyUseCase.RevokeAPIKey(userID, apiKeyID); err != nil {
+		if errors.Is(err, usecases.ErrAPIKeyNotFound) {
+			return utils.WriteJSONError(w, http.StatusNotFound, "API key not found")
+		}
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
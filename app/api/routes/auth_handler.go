@@ -3,13 +3,17 @@ package routes
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/knands42/lorecrafter/app/api/middleware"
 	"github.com/knands42/lorecrafter/app/api/utils"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/knands42/lorecrafter/internal/domain"
 	"github.com/knands42/lorecrafter/internal/usecases"
 )
@@ -30,6 +34,13 @@ func NewAuthHandler(authUseCase *usecases.AuthUseCase) *AuthHandler {
 func (h *AuthHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/register", middleware.ErrorHandlerMiddleware(h.Register))
 	r.Post("/login", middleware.ErrorHandlerMiddleware(h.Login))
+	r.Post("/refresh", middleware.ErrorHandlerMiddleware(h.Refresh))
+	r.With(middleware.AuthMiddleware(h.authUseCase)).Post("/logout", middleware.ErrorHandlerMiddleware(h.Logout))
+	r.Post("/verify", middleware.ErrorHandlerMiddleware(h.VerifyEmail))
+	r.With(middleware.AuthMiddleware(h.authUseCase), middleware.RequireScopes(domain.ScopeAccount)).Post("/verify/resend", middleware.ErrorHandlerMiddleware(h.ResendVerification))
+	r.Post("/forgot-password", middleware.ErrorHandlerMiddleware(h.ForgotPassword))
+	r.Post("/reset-password", middleware.ErrorHandlerMiddleware(h.ResetPassword))
+	r.Post("/mfa", middleware.ErrorHandlerMiddleware(h.LoginMFA))
 }
 
 // Register handles user registration
@@ -50,7 +61,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) error {
 		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
 	}
 
-	response, err := h.authUseCase.Register(input)
+	response, err := h.authUseCase.Register(input, utils.SessionClient(r))
 	if err != nil {
 		switch {
 		case errors.Is(err, usecases.ErrUserAlreadyExists):
@@ -71,14 +82,17 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) error {
 
 // Login handles user login
 // @Summary Login a user
-// @Description Login a user with the provided credentials
+// @Description Login a user with the provided credentials.
+// @Description Users with two-factor authentication enabled get an MFA challenge to complete with /api/auth/mfa instead
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body domain.LoginInput true "User login details"
 // @Success 200 {object} domain.AuthOutput "User logged in successfully"
+// @Success 202 {object} domain.MFAChallenge "Two-factor authentication code required"
 // @Failure 400 {object} utils.ErrorResponse "Invalid request body"
 // @Failure 401 {object} utils.ErrorResponse "Invalid credentials"
+// @Failure 429 {object} utils.ErrorResponse "Too many failed login attempts"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) error {
@@ -87,13 +101,93 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) error {
 		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
 	}
 
-	response, err := h.authUseCase.Login(input)
+	response, challenge, err := h.authUseCase.Login(input, utils.SessionClient(r))
 	if err != nil {
 		switch {
 		case errors.Is(err, usecases.ErrInvalidCredentials):
 			return utils.WriteJSONError(w, http.StatusUnauthorized, "Invalid credentials")
-		case errors.Is(err, usecases.ErrUserNotFound):
-			return utils.WriteJSONError(w, http.StatusUnauthorized, "Invalid credentials")
+		case errors.Is(err, usecases.ErrLoginLocked):
+			return writeLoginLockedError(w, err)
+		}
+
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if challenge != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return json.NewEncoder(w).Encode(challenge)
+	}
+	return json.NewEncoder(w).Encode(response)
+}
+
+// LoginMFA handles the second step of a two-factor login
+// @Summary Complete a two-factor login
+// @Description Exchange the MFA token returned by the login and a code from the authenticator app, or an unused recovery code,
+// @Description for an access token and a refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body domain.MFALoginInput true "MFA token and code"
+// @Success 200 {object} domain.AuthOutput "User logged in successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Invalid or expired MFA token or invalid code"
+// @Failure 429 {object} utils.ErrorResponse "Too many failed login attempts"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Failure 503 {object} utils.ErrorResponse "Two-factor authentication not configured"
+// @Router /api/auth/mfa [post]
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) error {
+	var input domain.MFALoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authUseCase.LoginMFA(input, utils.SessionClient(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrLoginLocked):
+			return writeLoginLockedError(w, err)
+		case errors.Is(err, usecases.ErrMFANotConfigured):
+			return utils.WriteJSONError(w, http.StatusServiceUnavailable, "Two-factor authentication is not configured on this server")
+		case errors.Is(err, usecases.ErrInvalidMFAToken):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "Invalid or expired MFA token")
+		case errors.Is(err, usecases.ErrInvalidMFACode):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "Invalid code")
+		}
+
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// Refresh handles exchanging a refresh token for new tokens
+// @Summary Refresh the access token
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token can only be used once,
+// @Description reusing one revokes every token of its session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body domain.RefreshTokenInput true "Refresh token"
+// @Success 200 {object} domain.AuthOutput "Tokens refreshed successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Invalid, expired or reused refresh token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) error {
+	var input domain.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authUseCase.Refresh(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvalidRefreshToken):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "Invalid refresh token")
+		case errors.Is(err, usecases.ErrRefreshTokenReused):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "Refresh token was already used")
 		}
 
 		return err
@@ -103,6 +197,372 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) error {
 	return json.NewEncoder(w).Encode(response)
 }
 
+// Logout handles logging a user out
+// @Summary Logout a user
+// @Description Revoke the access token or API key of the request and, when provided, every token of the refresh token session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.RefreshTokenInput false "Refresh token of the session to end"
+// @Success 204 "User logged out successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) error {
+	// The body is optional, logging out without it only revokes the access token
+	var input domain.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	payload, ok := r.Context().Value(middleware.TokenPayloadContextKey).(*domain.TokenPayload)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "Token not found in context")
+	}
+
+	if err := h.authUseCase.Logout(payload, input); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// VerifyEmail handles verifying the email address of an account
+// @Summary Verify an email address
+// @Description Activate the account a verification link was sent for
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body domain.VerifyEmailInput true "Verification token from the email link"
+// @Success 204 "Email verified successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or verification token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/verify [post]
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) error {
+	var input domain.VerifyEmailInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.authUseCase.VerifyEmail(input); err != nil {
+		if errors.Is(err, usecases.ErrInvalidVerificationToken) {
+			return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid or expired verification token")
+		}
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ResendVerification handles sending a new verification email
+// @Summary Resend the verification email
+// @Description Send a new verification link to the email address of the logged user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "Verification email sent successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Email already verified"
+// @Failure 429 {object} utils.ErrorResponse "A verification email was sent recently"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/verify/resend [post]
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.authUseCase.ResendVerification(userID); err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrEmailAlreadyVerified):
+			return utils.WriteJSONError(w, http.StatusConflict, "Email is already verified")
+		case errors.Is(err, usecases.ErrVerificationThrottled):
+			retryAfter := int(h.authUseCase.VerificationResendInterval().Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			return utils.WriteJSONError(w, http.StatusTooManyRequests, "A verification email was sent recently, try again later")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, usecases.ErrSendingVerification):
+			return utils.WriteJSONError(w, http.StatusInternalServerError, "Failed to send verification email")
+		}
+
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ForgotPassword handles requesting a password reset link
+// @Summary Request a password reset
+// @Description Email a single-use password reset link if the address belongs to an account.
+// @Description The response is the same whether the account exists or not, and an account gets at most one email per resend interval
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body domain.ForgotPasswordInput true "Account email"
+// @Success 202 "Request accepted"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) error {
+	var input domain.ForgotPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.authUseCase.ForgotPassword(input); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+// ResetPassword handles setting a new password with a reset token
+// @Summary Reset a password
+// @Description Set a new password with the token of a reset link. Every session and API key of the account is revoked
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body domain.ResetPasswordInput true "Reset token and new password"
+// @Success 204 "Password reset successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or reset token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) error {
+	var input domain.ResetPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.authUseCase.ResetPassword(input); err != nil {
+		if errors.Is(err, usecases.ErrInvalidPasswordResetToken) {
+			return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid or expired reset token")
+		}
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ChangePassword handles changing the password of the logged user
+// @Summary Change my password
+// @Description Replace the password of the logged user. Every other session of the account is ended, and its API keys are revoked too when revoke_api_keys is set
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.ChangePasswordInput true "Current and new password"
+// @Success 204 "Password changed successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Current password is incorrect"
+// @Failure 429 {object} utils.ErrorResponse "Too many wrong passwords"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/password [put]
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) error {
+	var input domain.ChangePasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	payload, ok := r.Context().Value(middleware.TokenPayloadContextKey).(*domain.TokenPayload)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "Token not found in context")
+	}
+
+	if err := h.authUseCase.ChangePassword(payload, input); err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrIncorrectPassword):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Current password is incorrect")
+		case errors.Is(err, usecases.ErrLoginLocked):
+			return writeLoginLockedError(w, err)
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// EnrollMFA handles starting the enrollment of two-factor authentication
+// @Summary Start enabling two-factor authentication
+// @Description Generate a new TOTP secret for the logged user. Two-factor authentication is only enabled once a code is confirmed
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.MFAEnrollment "Secret and provisioning URI for the authenticator app"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Two-factor authentication already enabled"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Failure 503 {object} utils.ErrorResponse "Two-factor authentication not configured"
+// @Router /api/me/mfa [post]
+func (h *AuthHandler) EnrollMFA(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	enrollment, err := h.authUseCase.EnrollMFA(userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrMFANotConfigured):
+			return utils.WriteJSONError(w, http.StatusServiceUnavailable, "Two-factor authentication is not configured on this server")
+		case errors.Is(err, usecases.ErrMFAAlreadyEnabled):
+			return utils.WriteJSONError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(enrollment)
+}
+
+// ConfirmMFA handles enabling two-factor authentication
+// @Summary Enable two-factor authentication
+// @Description Confirm a code from the authenticator app to enable two-factor authentication.
+// @Description The recovery codes are only returned once
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.MFACodeInput true "Code from the authenticator app"
+// @Success 200 {object} domain.MFARecoveryCodes "Two-factor authentication enabled"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or code"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Two-factor authentication already enabled or enrollment not started"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Failure 503 {object} utils.ErrorResponse "Two-factor authentication not configured"
+// @Router /api/me/mfa/confirm [post]
+func (h *AuthHandler) ConfirmMFA(w http.ResponseWriter, r *http.Request) error {
+	var input domain.MFACodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	recoveryCodes, err := h.authUseCase.ConfirmMFA(userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvalidMFACode):
+			return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid code")
+		case errors.Is(err, usecases.ErrMFAAlreadyEnabled):
+			return utils.WriteJSONError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		case errors.Is(err, usecases.ErrMFANotConfigured):
+			return utils.WriteJSONError(w, http.StatusServiceUnavailable, "Two-factor authentication is not configured on this server")
+		case errors.Is(err, usecases.ErrMFANotEnrolled):
+			return utils.WriteJSONError(w, http.StatusConflict, "Two-factor authentication enrollment was not started")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(recoveryCodes)
+}
+
+// DisableMFA handles turning two-factor authentication off
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off for the logged user, which requires the password and a code or recovery code
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.DisableMFAInput true "Password and code"
+// @Success 204 "Two-factor authentication disabled"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Incorrect password or code"
+// @Failure 409 {object} utils.ErrorResponse "Two-factor authentication not enabled"
+// @Failure 429 {object} utils.ErrorResponse "Too many wrong passwords"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Failure 503 {object} utils.ErrorResponse "Two-factor authentication not configured"
+// @Router /api/me/mfa [delete]
+func (h *AuthHandler) DisableMFA(w http.ResponseWriter, r *http.Request) error {
+	var input domain.DisableMFAInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.authUseCase.DisableMFA(userID, input); err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrIncorrectPassword):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Password is incorrect")
+		case errors.Is(err, usecases.ErrLoginLocked):
+			return writeLoginLockedError(w, err)
+		case errors.Is(err, usecases.ErrInvalidMFACode):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Invalid code")
+		case errors.Is(err, usecases.ErrMFANotConfigured):
+			return utils.WriteJSONError(w, http.StatusServiceUnavailable, "Two-factor authentication is not configured on this server")
+		case errors.Is(err, usecases.ErrMFANotEnabled):
+			return utils.WriteJSONError(w, http.StatusConflict, "Two-factor authentication is not enabled")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// writeLoginLockedError tells the client when it can try to log in again
+func writeLoginLockedError(w http.ResponseWriter, err error) error {
+	var lockedErr *usecases.LoginLockedError
+	if errors.As(err, &lockedErr) {
+		retryAfter := int(lockedErr.RetryAfter.Round(time.Second).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	}
+
+	return utils.WriteJSONError(w, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
+}
+
 // GetAuthorizationPayload extracts the token payload from the Authorization header
 func (h *AuthHandler) GetAuthorizationPayload(r *http.Request) (*domain.TokenPayload, error) {
 	authHeader := r.Header.Get("Authorization")
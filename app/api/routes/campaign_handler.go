@@ -3,7 +3,11 @@ package routes
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -26,25 +30,37 @@ func NewCampaignHandler(campaignUseCase *usecases.CampaignUseCase) *CampaignHand
 	}
 }
 
-// RegisterRoutes registers the campaign routes
+// RegisterRoutes registers the campaign routes along with the scopes each one requires
 func (h *CampaignHandler) RegisterRoutes(r chi.Router) {
+	read := middleware.RequireScopes(domain.ScopeRead)
+	write := middleware.RequireScopes(domain.ScopeCampaignsWrite)
+
 	r.Route("/campaigns", func(r chi.Router) {
-		r.Post("/", middleware.ErrorHandlerMiddleware(h.CreateCampaign))
-		r.Get("/", middleware.ErrorHandlerMiddleware(h.ListUserCampaigns))
+		r.With(write).Post("/", middleware.ErrorHandlerMiddleware(h.CreateCampaign))
+		r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.ListUserCampaigns))
+		r.With(read).Get("/public", middleware.ErrorHandlerMiddleware(h.ListPublicCampaigns))
 
 		r.Route("/{campaignID}", func(r chi.Router) {
-			r.Get("/", middleware.ErrorHandlerMiddleware(h.GetCampaign))
-			r.Put("/", middleware.ErrorHandlerMiddleware(h.UpdateCampaign))
-			r.Delete("/", middleware.ErrorHandlerMiddleware(h.DeleteCampaign))
+			r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.GetCampaign))
+			r.With(write).Put("/", middleware.ErrorHandlerMiddleware(h.UpdateCampaign))
+			r.With(write).Patch("/", middleware.ErrorHandlerMiddleware(h.PatchCampaign))
+			r.With(write).Delete("/", middleware.ErrorHandlerMiddleware(h.DeleteCampaign))
+			r.With(write).Post("/clone", middleware.ErrorHandlerMiddleware(h.CloneCampaign))
+			r.With(write).Post("/archive", middleware.ErrorHandlerMiddleware(h.ArchiveCampaign))
+			r.With(write).Delete("/archive", middleware.ErrorHandlerMiddleware(h.UnarchiveCampaign))
 
 			r.Route("/members", func(r chi.Router) {
-				r.Get("/", middleware.ErrorHandlerMiddleware(h.GetCampaignMembers))
-				r.Post("/", middleware.ErrorHandlerMiddleware(h.AddCampaignMember))
-				r.Delete("/{userID}", middleware.ErrorHandlerMiddleware(h.RemoveCampaignMember))
+				r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.GetCampaignMembers))
+				r.With(write).Post("/", middleware.ErrorHandlerMiddleware(h.AddCampaignMember))
+				r.With(write).Delete("/{userID}", middleware.ErrorHandlerMiddleware(h.RemoveCampaignMember))
 			})
+
+			r.With(write).Post("/invite-code", middleware.ErrorHandlerMiddleware(h.GenerateInviteCode))
+			r.With(write).Delete("/invite-code", middleware.ErrorHandlerMiddleware(h.RevokeInviteCode))
 		})
 
-		r.Delete("/leave/{campaignID}", middleware.ErrorHandlerMiddleware(h.LeaveCampaign))
+		r.With(write).Delete("/leave/{campaignID}", middleware.ErrorHandlerMiddleware(h.LeaveCampaign))
+		r.With(write).Post("/join/{inviteCode}", middleware.ErrorHandlerMiddleware(h.JoinCampaign))
 	})
 }
 
@@ -59,6 +75,7 @@ func (h *CampaignHandler) RegisterRoutes(r chi.Router) {
 // @Success 201 {object} sqlc.Campaign "Campaign created successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid request body"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Email address not verified"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/campaigns [post]
 func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) error {
@@ -80,7 +97,14 @@ func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request)
 
 	campaign, err := h.campaignUseCase.CreateCampaign(input, userID)
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, usecases.ErrEmailNotVerified):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Email address must be verified to create campaigns")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		default:
+			return err
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -97,6 +121,7 @@ func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request)
 // @Security BearerAuth
 // @Param campaignID path string true "Campaign ID"
 // @Success 200 {object} sqlc.Campaign "Campaign retrieved successfully"
+// @Header 200 {string} ETag "Version of the campaign, to send back in If-Match when patching it"
 // @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
@@ -137,6 +162,7 @@ func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) er
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", domain.CampaignETag(campaign))
 	return json.NewEncoder(w).Encode(campaign)
 }
 
@@ -148,8 +174,9 @@ func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) er
 // @Produce json
 // @Security BearerAuth
 // @Param campaignID path string true "Campaign ID"
-// @Param input body sqlc.Campaign true "Campaign update details"
+// @Param input body domain.UpdateCampaignInput true "Campaign update details"
 // @Success 200 {object} sqlc.Campaign "Campaign updated successfully"
+// @Header 200 {string} ETag "New version of the campaign"
 // @Failure 400 {object} utils.ErrorResponse "Invalid request body or campaign ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
@@ -179,7 +206,7 @@ func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request)
 	}
 	campaign.UserId = userID
 
-	err = h.campaignUseCase.UpdateCampaign(campaign)
+	updatedCampaign, err := h.campaignUseCase.UpdateCampaign(campaign)
 	if err != nil {
 		switch {
 		case errors.Is(err, usecases.ErrCampaignNotFound):
@@ -192,6 +219,73 @@ func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", domain.CampaignETag(updatedCampaign))
+	return json.NewEncoder(w).Encode(updatedCampaign)
+}
+
+// PatchCampaign handles partially updating a campaign
+// @Summary Patch a campaign
+// @Description Apply a JSON Merge Patch to a campaign if the user has GM permissions: fields left out are kept and fields set to null are cleared.
+// @Description Sending the ETag of the campaign in If-Match makes the patch fail when someone else updated the campaign in the meantime.
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param If-Match header string false "ETag of the campaign the patch was computed from"
+// @Param input body object true "Campaign fields to change: title, setting_summary, setting, image_url, is_public, tags, game_system"
+// @Success 200 {object} sqlc.Campaign "Campaign patched successfully"
+// @Header 200 {string} ETag "New version of the campaign"
+// @Failure 400 {object} utils.ErrorResponse "Invalid patch or campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 412 {object} utils.ErrorResponse "Campaign was modified since it was fetched"
+// @Failure 415 {object} utils.ErrorResponse "Unsupported content type"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID} [patch]
+func (h *CampaignHandler) PatchCampaign(w http.ResponseWriter, r *http.Request) error {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "application/merge-patch+json" && contentType != "application/json" {
+		return utils.WriteJSONError(w, http.StatusUnsupportedMediaType, "Content type must be application/merge-patch+json")
+	}
+
+	var patch domain.CampaignPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	campaign, err := h.campaignUseCase.PatchCampaign(campaignID, userID, patch, r.Header.Get("If-Match"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, usecases.ErrCampaignVersionMismatch):
+			return utils.WriteJSONError(w, http.StatusPreconditionFailed, "Campaign was modified since it was fetched")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", domain.CampaignETag(campaign))
 	return json.NewEncoder(w).Encode(campaign)
 }
 
@@ -245,14 +339,70 @@ func (h *CampaignHandler) DeleteCampaign(w http.ResponseWriter, r *http.Request)
 	return nil
 }
 
-// ListUserCampaigns handles listing all campaigns a user is a member of
+// CloneCampaign handles copying a public campaign
+// @Summary Clone a public campaign
+// @Description Copy a public campaign, its NPCs and public timeline events into a new private campaign owned by the user as GM
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Success 201 {object} sqlc.Campaign "Campaign cloned successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Email address not verified"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/clone [post]
+func (h *CampaignHandler) CloneCampaign(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	campaign, err := h.campaignUseCase.CloneCampaign(campaignID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrEmailNotVerified):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Email address must be verified to create campaigns")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(campaign)
+}
+
+// ListUserCampaigns handles listing the campaigns a user is a member of
 // @Summary List user campaigns
-// @Description List all campaigns a user is a member of
+// @Description List the campaigns the user is a member of along with their role in each, filtered by role, archived state
+// @Description or text over the title and setting summary. Pages are fetched by passing the next_cursor of a page as the cursor of the next request.
 // @Tags campaigns
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} sqlc.Campaign "Campaigns retrieved successfully"
+// @Param q query string false "Text the title or setting summary must contain"
+// @Param role query string false "gm or player"
+// @Param archived query bool false "List the archived campaigns instead of the active ones"
+// @Param sort query string false "updated_at (default) or last_accessed"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} domain.UserCampaignPage "Campaigns retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid filter, sort, cursor or limit"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/campaigns [get]
@@ -267,13 +417,155 @@ func (h *CampaignHandler) ListUserCampaigns(w http.ResponseWriter, r *http.Reque
 		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
 	}
 
-	campaigns, err := h.campaignUseCase.ListUserCampaigns(userID)
+	query := r.URL.Query()
+	filter := domain.UserCampaignFilter{
+		UserID: userID,
+		Query:  query.Get("q"),
+		Role:   query.Get("role"),
+		Sort:   query.Get("sort"),
+		Cursor: query.Get("cursor"),
+	}
+	if archived := query.Get("archived"); archived != "" {
+		parsed, err := strconv.ParseBool(archived)
+		if err != nil {
+			return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid archived flag")
+		}
+		filter.Archived = parsed
+	}
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid limit")
+		}
+		filter.Limit = parsed
+	}
+
+	page, err := h.campaignUseCase.ListUserCampaigns(filter)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(page)
+}
+
+// ArchiveCampaign handles archiving a campaign
+// @Summary Archive a campaign
+// @Description Archive a campaign if the user has GM permissions, it leaves the campaign list of its members unless archived campaigns are asked for
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Success 200 {object} sqlc.Campaign "Campaign archived successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/archive [post]
+func (h *CampaignHandler) ArchiveCampaign(w http.ResponseWriter, r *http.Request) error {
+	return h.setCampaignArchived(w, r, true)
+}
+
+// UnarchiveCampaign handles bringing an archived campaign back
+// @Summary Unarchive a campaign
+// @Description Bring an archived campaign back to the campaign list of its members if the user has GM permissions
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Success 200 {object} sqlc.Campaign "Campaign unarchived successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/archive [delete]
+func (h *CampaignHandler) UnarchiveCampaign(w http.ResponseWriter, r *http.Request) error {
+	return h.setCampaignArchived(w, r, false)
+}
+
+func (h *CampaignHandler) setCampaignArchived(w http.ResponseWriter, r *http.Request, archived bool) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	campaign, err := h.campaignUseCase.ArchiveCampaign(campaignID, userID, archived)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(campaign)
+}
+
+// ListPublicCampaigns handles searching the public campaigns
+// @Summary Explore public campaigns
+// @Description Search the public campaigns by text over their title, setting summary and setting, and filter them by tags,
+// @Description game system or creator. Pages are fetched by passing the next_cursor of a page as the cursor of the next request.
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Full-text search, quotes, OR and -word are supported"
+// @Param tags query string false "Comma separated tags the campaigns must all have"
+// @Param system query string false "Game system"
+// @Param creator query string false "Username of the creator"
+// @Param sort query string false "newest (default), most_cloned or most_members"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} domain.PublicCampaignPage "Public campaigns"
+// @Failure 400 {object} utils.ErrorResponse "Invalid filter, sort, cursor or limit"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/public [get]
+func (h *CampaignHandler) ListPublicCampaigns(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	filter := domain.PublicCampaignFilter{
+		Query:      query.Get("q"),
+		GameSystem: query.Get("system"),
+		Creator:    query.Get("creator"),
+		Sort:       query.Get("sort"),
+		Cursor:     query.Get("cursor"),
+	}
+	for _, tags := range query["tags"] {
+		filter.Tags = append(filter.Tags, strings.Split(tags, ",")...)
+	}
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid limit")
+		}
+		filter.Limit = parsed
+	}
+
+	page, err := h.campaignUseCase.SearchPublicCampaigns(filter)
 	if err != nil {
 		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(campaigns)
+	return json.NewEncoder(w).Encode(page)
 }
 
 // AddCampaignMember handles adding a user to a campaign
@@ -284,12 +576,13 @@ func (h *CampaignHandler) ListUserCampaigns(w http.ResponseWriter, r *http.Reque
 // @Produce json
 // @Security BearerAuth
 // @Param campaignID path string true "Campaign ID"
-// @Param input body map[string]string true "User ID and role"
+// @Param input body map[string]string true "User ID and role (gm or player, defaults to player)"
 // @Success 204 "User added to campaign successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid request body or campaign ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
-// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or user not found"
+// @Failure 409 {object} utils.ErrorResponse "User is already a member"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/campaigns/{campaignID}/members [post]
 func (h *CampaignHandler) AddCampaignMember(w http.ResponseWriter, r *http.Request) error {
@@ -326,8 +619,12 @@ func (h *CampaignHandler) AddCampaignMember(w http.ResponseWriter, r *http.Reque
 		switch {
 		case errors.Is(err, usecases.ErrCampaignNotFound):
 			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "User not found")
 		case errors.Is(err, usecases.ErrInsufficientPermissions):
 			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, usecases.ErrCampaignMemberAlreadyExists):
+			return utils.WriteJSONError(w, http.StatusConflict, "User is already a member of this campaign")
 		default:
 			return err
 		}
@@ -350,7 +647,8 @@ func (h *CampaignHandler) AddCampaignMember(w http.ResponseWriter, r *http.Reque
 // @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or user ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
-// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or member not found"
+// @Failure 409 {object} utils.ErrorResponse "Cannot remove the last GM"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/campaigns/{campaignID}/members/{userID} [delete]
 func (h *CampaignHandler) RemoveCampaignMember(w http.ResponseWriter, r *http.Request) error {
@@ -379,8 +677,12 @@ func (h *CampaignHandler) RemoveCampaignMember(w http.ResponseWriter, r *http.Re
 		switch {
 		case errors.Is(err, usecases.ErrCampaignNotFound):
 			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrCampaignMemberNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign member not found")
 		case errors.Is(err, usecases.ErrInsufficientPermissions):
 			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, usecases.ErrLastGameMaster):
+			return utils.WriteJSONError(w, http.StatusConflict, "Campaign must keep at least one GM")
 		default:
 			return err
 		}
@@ -401,7 +703,8 @@ func (h *CampaignHandler) RemoveCampaignMember(w http.ResponseWriter, r *http.Re
 // @Success 204 "Left campaign successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
-// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found or user is not a member"
+// @Failure 409 {object} utils.ErrorResponse "The last GM cannot leave"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/campaigns/leave/{campaignID} [delete]
 func (h *CampaignHandler) LeaveCampaign(w http.ResponseWriter, r *http.Request) error {
@@ -422,10 +725,16 @@ func (h *CampaignHandler) LeaveCampaign(w http.ResponseWriter, r *http.Request)
 
 	err = h.campaignUseCase.LeaveCampaign(campaignID, userID)
 	if err != nil {
-		if errors.Is(err, usecases.ErrCampaignNotFound) {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
 			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrCampaignMemberNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "User is not a member of this campaign")
+		case errors.Is(err, usecases.ErrLastGameMaster):
+			return utils.WriteJSONError(w, http.StatusConflict, "The last GM cannot leave the campaign")
+		default:
+			return err
 		}
-		return err
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -440,10 +749,11 @@ func (h *CampaignHandler) LeaveCampaign(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Security BearerAuth
 // @Param campaignID path string true "Campaign ID"
-// @Success 200 {array} sqlc.CampaignMember "Campaign members retrieved successfully"
+// @Success 200 {array} sqlc.ListCampaignMembersRow "Campaign members retrieved successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/campaigns/{campaignID}/members [get]
 func (h *CampaignHandler) GetCampaignMembers(w http.ResponseWriter, r *http.Request) error {
@@ -464,12 +774,170 @@ func (h *CampaignHandler) GetCampaignMembers(w http.ResponseWriter, r *http.Requ
 
 	members, err := h.campaignUseCase.GetCampaignMembers(campaignID, userID)
 	if err != nil {
-		if errors.Is(err, usecases.ErrInsufficientPermissions) {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
 			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			return err
 		}
-		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(members)
 }
+
+// GenerateInviteCode handles generating or rotating a campaign invite code
+// @Summary Generate a campaign invite code
+// @Description Generate a new invite code for a campaign, replacing the previous one, if the user has GM permissions
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param input body domain.InviteCodeInput false "Optional expiry and usage limits"
+// @Success 201 {object} domain.InviteCodeOutput "Invite code generated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/invite-code [post]
+func (h *CampaignHandler) GenerateInviteCode(w http.ResponseWriter, r *http.Request) error {
+	// The body is optional, an empty one generates a code without limits
+	var input domain.InviteCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	inviteCode, err := h.campaignUseCase.GenerateInviteCode(campaignID, userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(inviteCode)
+}
+
+// RevokeInviteCode handles revoking a campaign invite code
+// @Summary Revoke a campaign invite code
+// @Description Remove the invite code of a campaign if the user has GM permissions
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Success 204 "Invite code revoked successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/invite-code [delete]
+func (h *CampaignHandler) RevokeInviteCode(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = h.campaignUseCase.RevokeInviteCode(campaignID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			return err
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// JoinCampaign handles joining a campaign with an invite code
+// @Summary Join a campaign
+// @Description Join a campaign as a player using its invite code
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param inviteCode path string true "Invite code"
+// @Success 201 {object} sqlc.Campaign "Joined campaign successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Email address not verified"
+// @Failure 404 {object} utils.ErrorResponse "Invite code not found"
+// @Failure 409 {object} utils.ErrorResponse "User is already a member"
+// @Failure 410 {object} utils.ErrorResponse "Invite code expired or exhausted"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/join/{inviteCode} [post]
+func (h *CampaignHandler) JoinCampaign(w http.ResponseWriter, r *http.Request) error {
+	inviteCode := chi.URLParam(r, "inviteCode")
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	campaign, err := h.campaignUseCase.JoinCampaignByInviteCode(inviteCode, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInviteCodeNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Invite code not found")
+		case errors.Is(err, usecases.ErrInviteCodeExpired):
+			return utils.WriteJSONError(w, http.StatusGone, "Invite code has expired or reached its usage limit")
+		case errors.Is(err, usecases.ErrCampaignMemberAlreadyExists):
+			return utils.WriteJSONError(w, http.StatusConflict, "User is already a member of this campaign")
+		case errors.Is(err, usecases.ErrEmailNotVerified):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Email address must be verified to join campaigns")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(campaign)
+}
@@ -0,0 +1,284 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+)
+
+// CharacterHandler handles character-related HTTP requests
+type CharacterHandler struct {
+	characterUseCase *usecases.CharacterUseCase
+}
+
+// NewCharacterHandler creates a new CharacterHandler
+func NewCharacterHandler(characterUseCase *usecases.CharacterUseCase) *CharacterHandler {
+	return &CharacterHandler{
+		characterUseCase: characterUseCase,
+	}
+}
+
+// RegisterRoutes registers the character routes
+func (h *CharacterHandler) RegisterRoutes(r chi.Router) {
+	read := middleware.RequireScopes(domain.ScopeRead)
+	write := middleware.RequireScopes(domain.ScopeCharactersWrite)
+
+	r.Route("/campaigns/{campaignID}/characters", func(r chi.Router) {
+		r.With(write).Post("/", middleware.ErrorHandlerMiddleware(h.CreateCharacter))
+		r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.ListCharacters))
+		r.With(read).Get("/{characterID}", middleware.ErrorHandlerMiddleware(h.GetCharacter))
+		r.With(write).Put("/{characterID}", middleware.ErrorHandlerMiddleware(h.UpdateCharacter))
+		r.With(write).Delete("/{characterID}", middleware.ErrorHandlerMiddleware(h.DeleteCharacter))
+	})
+}
+
+// CreateCharacter handles character creation
+// @Summary Create a character
+// @Description Create a character in a campaign. Players create their own characters and GMs can also create NPCs
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param input body domain.CharacterInput true "Character details"
+// @Success 201 {object} sqlc.Character "Character created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/characters [post]
+func (h *CharacterHandler) CreateCharacter(w http.ResponseWriter, r *http.Request) error {
+	var input domain.CharacterInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	character, err := h.characterUseCase.CreateCharacter(campaignID, userID, input)
+	if err != nil {
+		return writeCharacterError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(character)
+}
+
+// ListCharacters handles listing the characters of a campaign
+// @Summary List campaign characters
+// @Description List the player characters and NPCs of a campaign if the user is a member
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Success 200 {array} sqlc.Character "Characters retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/characters [get]
+func (h *CharacterHandler) ListCharacters(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	characters, err := h.characterUseCase.ListCharacters(campaignID, userID)
+	if err != nil {
+		return writeCharacterError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(characters)
+}
+
+// GetCharacter handles retrieving a character
+// @Summary Get a character
+// @Description Get a character of a campaign if the user is a member
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param characterID path string true "Character ID"
+// @Success 200 {object} sqlc.Character "Character retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or character ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or character not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/characters/{characterID} [get]
+func (h *CharacterHandler) GetCharacter(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	characterID, err := uuid.Parse(chi.URLParam(r, "characterID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid character ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	character, err := h.characterUseCase.GetCharacter(campaignID, characterID, userID)
+	if err != nil {
+		return writeCharacterError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(character)
+}
+
+// UpdateCharacter handles updating a character
+// @Summary Update a character
+// @Description Replace the details of a character. GMs can edit every character, players only their own
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param characterID path string true "Character ID"
+// @Param input body domain.CharacterInput true "Character details"
+// @Success 200 {object} sqlc.Character "Character updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body, campaign ID or character ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or character not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/characters/{characterID} [put]
+func (h *CharacterHandler) UpdateCharacter(w http.ResponseWriter, r *http.Request) error {
+	var input domain.CharacterInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	characterID, err := uuid.Parse(chi.URLParam(r, "characterID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid character ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	character, err := h.characterUseCase.UpdateCharacter(campaignID, characterID, userID, input)
+	if err != nil {
+		return writeCharacterError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(character)
+}
+
+// DeleteCharacter handles deleting a character
+// @Summary Delete a character
+// @Description Delete a character. GMs can delete every character, players only their own
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param characterID path string true "Character ID"
+// @Success 204 "Character deleted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or character ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or character not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/characters/{characterID} [delete]
+func (h *CharacterHandler) DeleteCharacter(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	characterID, err := uuid.Parse(chi.URLParam(r, "characterID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid character ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.characterUseCase.DeleteCharacter(campaignID, characterID, userID); err != nil {
+		return writeCharacterError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// writeCharacterError maps the character use case errors to HTTP responses
+func writeCharacterError(w http.ResponseWriter, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrCampaignNotFound):
+		return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+	case errors.Is(err, usecases.ErrCharacterNotFound):
+		return utils.WriteJSONError(w, http.StatusNotFound, "Character not found")
+	case errors.Is(err, usecases.ErrInsufficientPermissions):
+		return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+	default:
+		return err
+	}
+}
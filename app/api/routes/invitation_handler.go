@@ -0,0 +1,329 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// InvitationHandler handles campaign invitation HTTP requests
+type InvitationHandler struct {
+	invitationUseCase *usecases.InvitationUseCase
+}
+
+// NewInvitationHandler creates a new InvitationHandler
+func NewInvitationHandler(invitationUseCase *usecases.InvitationUseCase) *InvitationHandler {
+	return &InvitationHandler{
+		invitationUseCase: invitationUseCase,
+	}
+}
+
+// RegisterRoutes registers the invitation routes
+func (h *InvitationHandler) RegisterRoutes(r chi.Router) {
+	read := middleware.RequireScopes(domain.ScopeRead)
+	write := middleware.RequireScopes(domain.ScopeCampaignsWrite)
+
+	r.Route("/campaigns/{campaignID}/invitations", func(r chi.Router) {
+		r.With(write).Post("/", middleware.ErrorHandlerMiddleware(h.InviteByEmail))
+		r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.ListCampaignInvitations))
+		r.With(write).Delete("/{invitationID}", middleware.ErrorHandlerMiddleware(h.CancelInvitation))
+	})
+
+	r.Route("/invitations", func(r chi.Router) {
+		r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.ListUserInvitations))
+		r.With(write).Post("/{token}/accept", middleware.ErrorHandlerMiddleware(h.AcceptInvitation))
+		r.With(write).Post("/{token}/reject", middleware.ErrorHandlerMiddleware(h.RejectInvitation))
+	})
+}
+
+// InviteByEmail handles inviting someone to a campaign by email
+// @Summary Invite someone to a campaign
+// @Description Send an email invitation to join a campaign if the user has GM permissions
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param input body domain.InvitationCreationInput true "Invitee email"
+// @Success 201 {object} sqlc.Invitation "Invitation sent successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 409 {object} utils.ErrorResponse "Already a member or already invited"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/invitations [post]
+func (h *InvitationHandler) InviteByEmail(w http.ResponseWriter, r *http.Request) error {
+	var input domain.InvitationCreationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	invitation, err := h.invitationUseCase.InviteByEmail(campaignID, userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, usecases.ErrCampaignMemberAlreadyExists):
+			return utils.WriteJSONError(w, http.StatusConflict, "User is already a member of this campaign")
+		case errors.Is(err, usecases.ErrInvitationAlreadyPending):
+			return utils.WriteJSONError(w, http.StatusConflict, "A pending invitation already exists for this email")
+		case errors.Is(err, usecases.ErrSendingInvitation):
+			return utils.WriteJSONError(w, http.StatusInternalServerError, "Failed to send invitation email")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(invitation)
+}
+
+// ListCampaignInvitations handles listing the invitations of a campaign
+// @Summary List campaign invitations
+// @Description List every invitation sent for a campaign if the user has GM permissions
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Success 200 {array} sqlc.ListInvitationsByCampaignRow "Invitations retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/invitations [get]
+func (h *InvitationHandler) ListCampaignInvitations(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	invitations, err := h.invitationUseCase.ListCampaignInvitations(campaignID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(invitations)
+}
+
+// CancelInvitation handles cancelling a campaign invitation
+// @Summary Cancel an invitation
+// @Description Delete an invitation of a campaign if the user has GM permissions
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param invitationID path string true "Invitation ID"
+// @Success 204 "Invitation cancelled successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or invitation ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or invitation not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/invitations/{invitationID} [delete]
+func (h *InvitationHandler) CancelInvitation(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	invitationID, err := uuid.Parse(chi.URLParam(r, "invitationID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid invitation ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = h.invitationUseCase.CancelInvitation(campaignID, invitationID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrCampaignNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, usecases.ErrInvitationNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Invitation not found")
+		case errors.Is(err, usecases.ErrInsufficientPermissions):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			return err
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListUserInvitations handles listing the pending invitations of the logged user
+// @Summary List my invitations
+// @Description List the pending campaign invitations sent to the verified email of the logged user
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} sqlc.ListPendingInvitationsByEmailRow "Invitations retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Email address not verified"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/invitations [get]
+func (h *InvitationHandler) ListUserInvitations(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	invitations, err := h.invitationUseCase.ListUserInvitations(userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, usecases.ErrEmailNotVerified):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Email address must be verified to see invitations")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(invitations)
+}
+
+// AcceptInvitation handles accepting a campaign invitation
+// @Summary Accept an invitation
+// @Description Accept an invitation sent to the email of the logged user and join the campaign as a player
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invitation token"
+// @Success 200 {object} sqlc.Invitation "Invitation accepted successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Email address not verified"
+// @Failure 404 {object} utils.ErrorResponse "Invitation not found"
+// @Failure 409 {object} utils.ErrorResponse "Invitation already answered or user already a member"
+// @Failure 410 {object} utils.ErrorResponse "Invitation expired"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/invitations/{token}/accept [post]
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) error {
+	return h.answerInvitation(w, r, h.invitationUseCase.AcceptInvitation)
+}
+
+// RejectInvitation handles rejecting a campaign invitation
+// @Summary Reject an invitation
+// @Description Decline an invitation sent to the email of the logged user
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invitation token"
+// @Success 200 {object} sqlc.Invitation "Invitation rejected successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Email address not verified"
+// @Failure 404 {object} utils.ErrorResponse "Invitation not found"
+// @Failure 409 {object} utils.ErrorResponse "Invitation already answered"
+// @Failure 410 {object} utils.ErrorResponse "Invitation expired"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/invitations/{token}/reject [post]
+func (h *InvitationHandler) RejectInvitation(w http.ResponseWriter, r *http.Request) error {
+	return h.answerInvitation(w, r, h.invitationUseCase.RejectInvitation)
+}
+
+// answerInvitation runs an accept or reject action and maps its errors to HTTP responses
+func (h *InvitationHandler) answerInvitation(
+	w http.ResponseWriter,
+	r *http.Request,
+	answer func(token string, userID uuid.UUID) (sqlc.Invitation, error),
+) error {
+	token := chi.URLParam(r, "token")
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	invitation, err := answer(token, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvitationNotFound):
+			return utils.WriteJSONError(w, http.StatusNotFound, "Invitation not found")
+		case errors.Is(err, usecases.ErrInvitationExpired):
+			return utils.WriteJSONError(w, http.StatusGone, "Invitation has expired")
+		case errors.Is(err, usecases.ErrInvitationNotPending):
+			return utils.WriteJSONError(w, http.StatusConflict, "Invitation was already answered")
+		case errors.Is(err, usecases.ErrCampaignMemberAlreadyExists):
+			return utils.WriteJSONError(w, http.StatusConflict, "User is already a member of this campaign")
+		case errors.Is(err, usecases.ErrEmailNotVerified):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Email address must be verified to answer invitations")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(invitation)
+}
@@ -0,0 +1,247 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+)
+
+// OIDCHandler handles login and account linking through OpenID Connect providers
+type OIDCHandler struct {
+	oidcUseCase *usecases.OIDCUseCase
+}
+
+// NewOIDCHandler creates a new OIDCHandler
+func NewOIDCHandler(oidcUseCase *usecases.OIDCUseCase) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUseCase: oidcUseCase,
+	}
+}
+
+// RegisterRoutes registers the public login routes, under the auth routes
+func (h *OIDCHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/oidc", middleware.ErrorHandlerMiddleware(h.ListProviders))
+	r.Get("/oidc/{provider}", middleware.ErrorHandlerMiddleware(h.StartLogin))
+	r.Get("/oidc/{provider}/callback", middleware.ErrorHandlerMiddleware(h.Callback))
+}
+
+// RegisterAccountRoutes registers the routes managing the linked accounts of the logged user
+func (h *OIDCHandler) RegisterAccountRoutes(r chi.Router) {
+	r.Route("/me/identities", func(r chi.Router) {
+		r.Use(middleware.RequireScopes(domain.ScopeAccount))
+		r.Get("/", middleware.ErrorHandlerMiddleware(h.ListIdentities))
+		r.Post("/{provider}", middleware.ErrorHandlerMiddleware(h.StartLink))
+		r.Delete("/{identityID}", middleware.ErrorHandlerMiddleware(h.UnlinkIdentity))
+	})
+}
+
+// ListProviders handles listing the login providers
+// @Summary List login providers
+// @Description List the OpenID Connect providers users can log in with
+// @Tags auth
+// @Produce json
+// @Success 200 {object} domain.OIDCProviders "Login providers"
+// @Router /api/auth/oidc [get]
+func (h *OIDCHandler) ListProviders(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(h.oidcUseCase.Providers())
+}
+
+// StartLogin handles starting a login with a provider
+// @Summary Log in with a provider
+// @Description Redirect to the login page of an OpenID Connect provider, which redirects back to the callback
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} utils.ErrorResponse "Provider not found"
+// @Failure 502 {object} utils.ErrorResponse "Provider unavailable"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/oidc/{provider} [get]
+func (h *OIDCHandler) StartLogin(w http.ResponseWriter, r *http.Request) error {
+	authorization, err := h.oidcUseCase.StartLogin(chi.URLParam(r, "provider"))
+	if err != nil {
+		return writeOIDCError(w, err)
+	}
+
+	http.Redirect(w, r, authorization.AuthorizationURL, http.StatusFound)
+	return nil
+}
+
+// Callback handles the redirect back from a provider
+// @Summary Complete a login with a provider
+// @Description Exchange the authorization code the provider redirected back with.
+// @Description Logins return an access token and a refresh token, the account is created on the first login.
+// @Description Users with two-factor authentication enabled get an MFA challenge to complete with /api/auth/mfa instead.
+// @Description Links started from /api/me/identities return the linked account.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State"
+// @Success 200 {object} domain.AuthOutput "User logged in successfully"
+// @Success 201 {object} domain.UserIdentity "Provider account linked successfully"
+// @Success 202 {object} domain.MFAChallenge "Two-factor authentication code required"
+// @Failure 400 {object} utils.ErrorResponse "Invalid, expired or already used state"
+// @Failure 401 {object} utils.ErrorResponse "Login with the provider failed"
+// @Failure 404 {object} utils.ErrorResponse "Provider not found"
+// @Failure 409 {object} utils.ErrorResponse "Email or provider account already in use"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	input := domain.OIDCCallbackInput{
+		Code:             query.Get("code"),
+		State:            query.Get("state"),
+		Error:            query.Get("error"),
+		ErrorDescription: query.Get("error_description"),
+	}
+
+	result, err := h.oidcUseCase.Callback(chi.URLParam(r, "provider"), input, utils.SessionClient(r))
+	if err != nil {
+		return writeOIDCError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case result.Challenge != nil:
+		w.WriteHeader(http.StatusAccepted)
+		return json.NewEncoder(w).Encode(result.Challenge)
+	case result.Identity != nil:
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(result.Identity)
+	}
+	return json.NewEncoder(w).Encode(result.Auth)
+}
+
+// StartLink handles starting to link a provider account
+// @Summary Link a provider account
+// @Description Start linking an OpenID Connect provider account to the logged user so it can be used to log in.
+// @Description Open the returned URL, the provider redirects back to the callback which completes the link.
+// @Tags identities
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} domain.OIDCAuthorization "Provider login page"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Provider not found"
+// @Failure 502 {object} utils.ErrorResponse "Provider unavailable"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/identities/{provider} [post]
+func (h *OIDCHandler) StartLink(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	authorization, err := h.oidcUseCase.StartLink(userID, chi.URLParam(r, "provider"))
+	if err != nil {
+		return writeOIDCError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(authorization)
+}
+
+// ListIdentities handles listing the provider accounts of the logged user
+// @Summary List my linked provider accounts
+// @Description List the OpenID Connect provider accounts the logged user can log in with
+// @Tags identities
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.UserIdentity "Linked provider accounts"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/identities [get]
+func (h *OIDCHandler) ListIdentities(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	identities, err := h.oidcUseCase.ListIdentities(userID)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(identities)
+}
+
+// UnlinkIdentity handles unlinking a provider account
+// @Summary Unlink a provider account
+// @Description Unlink an OpenID Connect provider account from the logged user, it can no longer be used to log in
+// @Tags identities
+// @Produce json
+// @Security BearerAuth
+// @Param identityID path string true "Linked provider account ID"
+// @Success 204 "Provider account unlinked successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid identity ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Linked provider account not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/identities/{identityID} [delete]
+func (h *OIDCHandler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) error {
+	identityID, err := uuid.Parse(chi.URLParam(r, "identityID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid identity ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.oidcUseCase.UnlinkIdentity(userID, identityID); err != nil {
+		if errors.Is(err, usecases.ErrIdentityNotFound) {
+			return utils.WriteJSONError(w, http.StatusNotFound, "Linked provider account not found")
+		}
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// writeOIDCError maps the errors of the OIDC flow to HTTP responses
+func writeOIDCError(w http.ResponseWriter, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrOIDCProviderNotFound):
+		return utils.WriteJSONError(w, http.StatusNotFound, "Login provider not found")
+	case errors.Is(err, usecases.ErrInvalidOIDCState):
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Login state is invalid, expired or already used")
+	case errors.Is(err, usecases.ErrOIDCUnavailable):
+		return utils.WriteJSONError(w, http.StatusBadGateway, "Login provider is unavailable")
+	case errors.Is(err, usecases.ErrOIDCLoginFailed):
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "Login with the provider failed")
+	case errors.Is(err, usecases.ErrOIDCEmailMissing):
+		return utils.WriteJSONError(w, http.StatusBadRequest, "The provider account has no email address")
+	case errors.Is(err, usecases.ErrOIDCEmailTaken):
+		return utils.WriteJSONError(w, http.StatusConflict, "An account already uses this email, log in and link the provider instead")
+	case errors.Is(err, usecases.ErrIdentityAlreadyLinked):
+		return utils.WriteJSONError(w, http.StatusConflict, "The provider account is already linked to another user")
+	}
+
+	return err
+}
@@ -0,0 +1,251 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+)
+
+// PasskeyHandler handles passkey registration and passwordless login
+type PasskeyHandler struct {
+	passkeyUseCase *usecases.PasskeyUseCase
+}
+
+// NewPasskeyHandler creates a new PasskeyHandler
+func NewPasskeyHandler(passkeyUseCase *usecases.PasskeyUseCase) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyUseCase: passkeyUseCase,
+	}
+}
+
+// RegisterRoutes registers the public login routes, under the auth routes
+func (h *PasskeyHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/passkey/options", middleware.ErrorHandlerMiddleware(h.BeginLogin))
+	r.Post("/passkey", middleware.ErrorHandlerMiddleware(h.FinishLogin))
+}
+
+// RegisterAccountRoutes registers the routes managing the passkeys of the logged user
+func (h *PasskeyHandler) RegisterAccountRoutes(r chi.Router) {
+	r.Route("/me/passkeys", func(r chi.Router) {
+		r.Use(middleware.RequireScopes(domain.ScopeAccount))
+		r.Get("/", middleware.ErrorHandlerMiddleware(h.ListPasskeys))
+		r.Post("/options", middleware.ErrorHandlerMiddleware(h.BeginRegistration))
+		r.Post("/", middleware.ErrorHandlerMiddleware(h.FinishRegistration))
+		r.Delete("/{passkeyID}", middleware.ErrorHandlerMiddleware(h.DeletePasskey))
+	})
+}
+
+// BeginLogin handles starting a passwordless login
+// @Summary Start a passkey login
+// @Description Get the options to pass to navigator.credentials.get(), then send the result to /api/auth/passkey
+// @Tags auth
+// @Produce json
+// @Success 200 {object} domain.PasskeyOptions "Login options"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/passkey/options [post]
+func (h *PasskeyHandler) BeginLogin(w http.ResponseWriter, r *http.Request) error {
+	options, err := h.passkeyUseCase.BeginLogin()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(options)
+}
+
+// FinishLogin handles completing a passwordless login
+// @Summary Log in with a passkey
+// @Description Exchange the assertion signed by the authenticator for an access token and a refresh token.
+// @Description The authenticator verifies the user, so no two-factor authentication code is asked for.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body domain.PasskeyLoginInput true "Session ID and assertion"
+// @Success 200 {object} domain.AuthOutput "User logged in successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or invalid, expired or already used session"
+// @Failure 401 {object} utils.ErrorResponse "Passkey login failed"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/auth/passkey [post]
+func (h *PasskeyHandler) FinishLogin(w http.ResponseWriter, r *http.Request) error {
+	var input domain.PasskeyLoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.passkeyUseCase.FinishLogin(input, utils.SessionClient(r))
+	if err != nil {
+		return writePasskeyError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// BeginRegistration handles starting to register a passkey
+// @Summary Start registering a passkey
+// @Description Get the options to pass to navigator.credentials.create(), then send the result to /api/me/passkeys
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.PasskeyOptions "Registration options"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/passkeys/options [post]
+func (h *PasskeyHandler) BeginRegistration(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	options, err := h.passkeyUseCase.BeginRegistration(userID)
+	if err != nil {
+		if errors.Is(err, usecases.ErrUserNotFound) {
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(options)
+}
+
+// FinishRegistration handles saving the passkey created by the authenticator
+// @Summary Register a passkey
+// @Description Save the credential created by the authenticator, it can be used to log in right away.
+// @Description Each authenticator of the user is registered as its own passkey.
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.PasskeyRegistrationInput true "Session ID, name and credential"
+// @Success 201 {object} domain.Passkey "Passkey registered successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body, invalid credential or invalid, expired or already used session"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Passkey already registered"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/passkeys [post]
+func (h *PasskeyHandler) FinishRegistration(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	var input domain.PasskeyRegistrationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	passkey, err := h.passkeyUseCase.FinishRegistration(userID, input)
+	if err != nil {
+		return writePasskeyError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(passkey)
+}
+
+// ListPasskeys handles listing the passkeys of the logged user
+// @Summary List my passkeys
+// @Description List the passkeys the logged user can log in with
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Passkey "Passkeys"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/passkeys [get]
+func (h *PasskeyHandler) ListPasskeys(w http.ResponseWriter, r *http.Request) error {
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	passkeys, err := h.passkeyUseCase.ListPasskeys(userID)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(passkeys)
+}
+
+// DeletePasskey handles removing a passkey
+// @Summary Remove a passkey
+// @Description Remove a passkey of the logged user, it can no longer be used to log in
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Param passkeyID path string true "Passkey ID"
+// @Success 204 "Passkey removed successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid passkey ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Passkey not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/passkeys/{passkeyID} [delete]
+func (h *PasskeyHandler) DeletePasskey(w http.ResponseWriter, r *http.Request) error {
+	passkeyID, err := uuid.Parse(chi.URLParam(r, "passkeyID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid passkey ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.passkeyUseCase.DeletePasskey(userID, passkeyID); err != nil {
+		if errors.Is(err, usecases.ErrPasskeyNotFound) {
+			return utils.WriteJSONError(w, http.StatusNotFound, "Passkey not found")
+		}
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// writePasskeyError maps the errors of the passkey ceremonies to HTTP responses
+func writePasskeyError(w http.ResponseWriter, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidPasskeySession):
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Passkey session is invalid, expired or already used")
+	case errors.Is(err, usecases.ErrPasskeyRegistration):
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Passkey credential is invalid")
+	case errors.Is(err, usecases.ErrPasskeyAlreadyRegistered):
+		return utils.WriteJSONError(w, http.StatusConflict, "Passkey is already registered")
+	case errors.Is(err, usecases.ErrPasskeyLoginFailed):
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "Passkey login failed")
+	case errors.Is(err, usecases.ErrUserNotFound):
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+	}
+
+	return err
+}
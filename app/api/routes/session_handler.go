@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+)
+
+// SessionHandler handles listing and ending the sessions of the logged user
+type SessionHandler struct {
+	sessionUseCase *usecases.SessionUseCase
+}
+
+// NewSessionHandler creates a new SessionHandler
+func NewSessionHandler(sessionUseCase *usecases.SessionUseCase) *SessionHandler {
+	return &SessionHandler{
+		sessionUseCase: sessionUseCase,
+	}
+}
+
+// RegisterRoutes registers the session routes
+func (h *SessionHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/me/sessions", func(r chi.Router) {
+		r.Use(middleware.RequireScopes(domain.ScopeAccount))
+		r.Get("/", middleware.ErrorHandlerMiddleware(h.ListSessions))
+		r.Delete("/", middleware.ErrorHandlerMiddleware(h.RevokeOtherSessions))
+		r.Delete("/{sessionID}", middleware.ErrorHandlerMiddleware(h.RevokeSession))
+	})
+}
+
+// ListSessions handles listing the sessions of the logged user
+// @Summary List my sessions
+// @Description List the devices the logged user is logged in on, the session of the request is flagged as current
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Session "Active sessions"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/sessions [get]
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) error {
+	payload, ok := r.Context().Value(middleware.TokenPayloadContextKey).(*domain.TokenPayload)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "Token not found in context")
+	}
+
+	sessions, err := h.sessionUseCase.ListSessions(payload)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sessions)
+}
+
+// RevokeOtherSessions handles logging out everywhere else
+// @Summary Log out everywhere else
+// @Description End every session of the logged user except the one of the request, their tokens stop working right away
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "Other sessions ended successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/sessions [delete]
+func (h *SessionHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) error {
+	payload, ok := r.Context().Value(middleware.TokenPayloadContextKey).(*domain.TokenPayload)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "Token not found in context")
+	}
+
+	if err := h.sessionUseCase.RevokeOtherSessions(payload); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// RevokeSession handles ending a session
+// @Summary End a session
+// @Description Log the user out of a device, the tokens of the session stop working right away
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param sessionID path string true "Session ID"
+// @Success 204 "Session ended successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid session ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Session not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/sessions/{sessionID} [delete]
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) error {
+	sessionID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid session ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.sessionUseCase.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, usecases.ErrSessionNotFound) {
+			return utils.WriteJSONError(w, http.StatusNotFound, "Session not found")
+		}
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,318 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
+)
+
+// TimelineHandler handles campaign timeline HTTP requests
+type TimelineHandler struct {
+	timelineUseCase *usecases.TimelineUseCase
+}
+
+// NewTimelineHandler creates a new TimelineHandler
+func NewTimelineHandler(timelineUseCase *usecases.TimelineUseCase) *TimelineHandler {
+	return &TimelineHandler{
+		timelineUseCase: timelineUseCase,
+	}
+}
+
+// RegisterRoutes registers the timeline routes
+func (h *TimelineHandler) RegisterRoutes(r chi.Router) {
+	read := middleware.RequireScopes(domain.ScopeRead)
+	write := middleware.RequireScopes(domain.ScopeCampaignsWrite)
+
+	r.Route("/campaigns/{campaignID}/timeline", func(r chi.Router) {
+		r.With(write).Post("/", middleware.ErrorHandlerMiddleware(h.CreateTimelineEvent))
+		r.With(read).Get("/", middleware.ErrorHandlerMiddleware(h.ListTimelineEvents))
+		r.With(read).Get("/{eventID}", middleware.ErrorHandlerMiddleware(h.GetTimelineEvent))
+		r.With(write).Put("/{eventID}", middleware.ErrorHandlerMiddleware(h.UpdateTimelineEvent))
+		r.With(write).Delete("/{eventID}", middleware.ErrorHandlerMiddleware(h.DeleteTimelineEvent))
+	})
+}
+
+// CreateTimelineEvent handles timeline event creation
+// @Summary Create a timeline event
+// @Description Add an event to the campaign timeline if the user has GM permissions
+// @Tags timeline
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param input body domain.TimelineEventInput true "Timeline event details"
+// @Success 201 {object} sqlc.TimelineEvent "Timeline event created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body or campaign ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/timeline [post]
+func (h *TimelineHandler) CreateTimelineEvent(w http.ResponseWriter, r *http.Request) error {
+	var input domain.TimelineEventInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	event, err := h.timelineUseCase.CreateTimelineEvent(campaignID, userID, input)
+	if err != nil {
+		return writeTimelineError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(event)
+}
+
+// ListTimelineEvents handles listing the timeline of a campaign
+// @Summary List timeline events
+// @Description List the campaign timeline in chronological order. Players only see public events
+// @Tags timeline
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param from query string false "Only events on or after this date (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only events on or before this date (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {array} sqlc.TimelineEvent "Timeline events retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or date range"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/timeline [get]
+func (h *TimelineHandler) ListTimelineEvents(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	var filter domain.TimelineEventFilter
+	if filter.From, err = parseDateQueryParam(r, "from", false); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid from date")
+	}
+	if filter.To, err = parseDateQueryParam(r, "to", true); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid to date")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	events, err := h.timelineUseCase.ListTimelineEvents(campaignID, userID, filter)
+	if err != nil {
+		return writeTimelineError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(events)
+}
+
+// GetTimelineEvent handles retrieving a timeline event
+// @Summary Get a timeline event
+// @Description Get an event of the campaign timeline. Secret events are only visible to GMs
+// @Tags timeline
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param eventID path string true "Timeline event ID"
+// @Success 200 {object} sqlc.TimelineEvent "Timeline event retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or event ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or timeline event not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/timeline/{eventID} [get]
+func (h *TimelineHandler) GetTimelineEvent(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	eventID, err := uuid.Parse(chi.URLParam(r, "eventID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid event ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	event, err := h.timelineUseCase.GetTimelineEvent(campaignID, eventID, userID)
+	if err != nil {
+		return writeTimelineError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(event)
+}
+
+// UpdateTimelineEvent handles updating a timeline event
+// @Summary Update a timeline event
+// @Description Replace the details of a timeline event if the user has GM permissions
+// @Tags timeline
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param eventID path string true "Timeline event ID"
+// @Param input body domain.TimelineEventInput true "Timeline event details"
+// @Success 200 {object} sqlc.TimelineEvent "Timeline event updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body, campaign ID or event ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign or timeline event not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/timeline/{eventID} [put]
+func (h *TimelineHandler) UpdateTimelineEvent(w http.ResponseWriter, r *http.Request) error {
+	var input domain.TimelineEventInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	eventID, err := uuid.Parse(chi.URLParam(r, "eventID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid event ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	event, err := h.timelineUseCase.UpdateTimelineEvent(campaignID, eventID, userID, input)
+	if err != nil {
+		return writeTimelineError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(event)
+}
+
+// DeleteTimelineEvent handles deleting a timeline event
+// @Summary Delete a timeline event
+// @Description Delete an event of the campaign timeline if the user has GM permissions
+// @Tags timeline
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaignID path string true "Campaign ID"
+// @Param eventID path string true "Timeline event ID"
+// @Success 204 "Timeline event deleted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid campaign ID or event ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} utils.ErrorResponse "Campaign not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/campaigns/{campaignID}/timeline/{eventID} [delete]
+func (h *TimelineHandler) DeleteTimelineEvent(w http.ResponseWriter, r *http.Request) error {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "campaignID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid campaign ID")
+	}
+
+	eventID, err := uuid.Parse(chi.URLParam(r, "eventID"))
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid event ID")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.timelineUseCase.DeleteTimelineEvent(campaignID, eventID, userID); err != nil {
+		return writeTimelineError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// writeTimelineError maps the timeline use case errors to HTTP responses
+func writeTimelineError(w http.ResponseWriter, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrCampaignNotFound):
+		return utils.WriteJSONError(w, http.StatusNotFound, "Campaign not found")
+	case errors.Is(err, usecases.ErrTimelineEventNotFound):
+		return utils.WriteJSONError(w, http.StatusNotFound, "Timeline event not found")
+	case errors.Is(err, usecases.ErrInsufficientPermissions):
+		return utils.WriteJSONError(w, http.StatusForbidden, "Insufficient permissions")
+	default:
+		return err
+	}
+}
+
+// parseDateQueryParam reads an optional RFC 3339 timestamp or YYYY-MM-DD date from the query string.
+// Plain dates used as an upper bound cover the whole day.
+func parseDateQueryParam(r *http.Request, name string, endOfDay bool) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return &parsed, nil
+}
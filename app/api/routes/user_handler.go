@@ -1,29 +1,264 @@
 package routes
 
 import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
 	"fmt"
-	middleware2 "github.com/knands42/lorecrafter/app/api/middleware"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	middleware2 "github.com/knands42/lorecrafter/app/api/middleware"
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/usecases"
 )
 
-type UserHandler struct{}
+// UserHandler handles user profile HTTP requests
+type UserHandler struct {
+	userUseCase *usecases.UserUseCase
+}
+
+// NewUserHandler creates a new UserHandler
+func NewUserHandler(userUseCase *usecases.UserUseCase) *UserHandler {
+	return &UserHandler{
+		userUseCase: userUseCase,
+	}
+}
+
+// RegisterRoutes registers the user profile routes
+func (h *UserHandler) RegisterRoutes(r chi.Router) {
+	read := middleware2.RequireScopes(domain.ScopeRead)
+	account := middleware2.RequireScopes(domain.ScopeAccount)
 
-func NewUserHandler() *UserHandler {
-	return &UserHandler{}
+	r.With(read).Get("/me", middleware2.ErrorHandlerMiddleware(h.Me))
+	r.With(account).Patch("/me", middleware2.ErrorHandlerMiddleware(h.UpdateMe))
+	r.With(account).Delete("/me", middleware2.ErrorHandlerMiddleware(h.DeleteMe))
+	r.With(account).Get("/me/export", middleware2.ErrorHandlerMiddleware(h.ExportMe))
+	r.With(read).Get("/users/{username}", middleware2.ErrorHandlerMiddleware(h.GetUserProfile))
 }
 
 // Me describe user info
 // @Summary Get info about the logged user
-// @Description Based on the logged user, get information from the token
+// @Description Get the profile of the logged user
 // @Tags user
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} string "User information"
+// @Success 200 {object} domain.UserProfile "User information"
 // @Failure 401 {object} utils.ErrorResponse "Missing or invalid authorization header"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /api/me [get]
 func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) error {
-	userID := r.Context().Value(middleware2.UserIDContextKey)
-	_, err := w.Write([]byte(fmt.Sprintf("Authenticated! User ID: %v", userID)))
-	return err
+	userIDStr, ok := r.Context().Value(middleware2.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	profile, err := h.userUseCase.GetProfile(userID)
+	if err != nil {
+		if errors.Is(err, usecases.ErrUserNotFound) {
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(profile)
+}
+
+// UpdateMe handles updating the profile of the logged user
+// @Summary Update my profile
+// @Description Change the username, email or avatar of the logged user. Fields left out are kept as they are.
+// @Description A new email address has to be verified again
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.UpdateProfileInput true "Profile changes"
+// @Success 200 {object} domain.UserProfile "Profile updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Username or email already taken"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me [patch]
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) error {
+	var input domain.UpdateProfileInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware2.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	profile, err := h.userUseCase.UpdateProfile(userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrUsernameTaken):
+			return utils.WriteJSONError(w, http.StatusConflict, "Username already taken")
+		case errors.Is(err, usecases.ErrEmailTaken):
+			return utils.WriteJSONError(w, http.StatusConflict, "Email already taken")
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(profile)
+}
+
+// DeleteMe handles deleting the account of the logged user
+// @Summary Delete my account
+// @Description Schedule the deletion of the logged user's account, confirmed with their password. Every session and API key
+// @Description is revoked right away and the account is deleted once the grace period is over, logging in before then cancels it.
+// @Description Campaigns other members play in are handed over to one of them rather than deleted.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.DeleteAccountInput true "Password"
+// @Success 202 {object} domain.AccountDeletion "Account deletion scheduled"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Password is incorrect"
+// @Failure 429 {object} utils.ErrorResponse "Too many wrong passwords"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me [delete]
+func (h *UserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) error {
+	var input domain.DeleteAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid request body")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware2.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	deletion, err := h.userUseCase.DeleteAccount(userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrIncorrectPassword):
+			return utils.WriteJSONError(w, http.StatusForbidden, "Password is incorrect")
+		case errors.Is(err, usecases.ErrLoginLocked):
+			return writeLoginLockedError(w, err)
+		case errors.Is(err, usecases.ErrUserNotFound):
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		default:
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	return json.NewEncoder(w).Encode(deletion)
+}
+
+// ExportMe handles exporting the data of the logged user
+// @Summary Export my data
+// @Description Download everything the logged user owns: profile, campaigns they created, characters, timeline events and invitations.
+// @Description The export is a single JSON document, or a ZIP archive with one JSON file per section when format is zip.
+// @Tags user
+// @Produce json
+// @Produce application/zip
+// @Security BearerAuth
+// @Param format query string false "json (default) or zip"
+// @Success 200 {object} domain.AccountExport "Account data"
+// @Failure 400 {object} utils.ErrorResponse "Invalid format"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/me/export [get]
+func (h *UserHandler) ExportMe(w http.ResponseWriter, r *http.Request) error {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "zip" {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Format must be json or zip")
+	}
+
+	userIDStr, ok := r.Context().Value(middleware2.UserIDContextKey).(string)
+	if !ok {
+		return utils.WriteJSONError(w, http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.WriteJSONError(w, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	export, err := h.userUseCase.ExportAccount(userID)
+	if err != nil {
+		if errors.Is(err, usecases.ErrUserNotFound) {
+			return utils.WriteJSONError(w, http.StatusUnauthorized, "User not found")
+		}
+		return err
+	}
+
+	filename := fmt.Sprintf("lorecrafter-%s.%s", export.Profile.Username, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(export)
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	archive := zip.NewWriter(w)
+	for _, file := range export.Files() {
+		entry, err := archive.Create(file.Name)
+		if err != nil {
+			return err
+		}
+		if err := json.NewEncoder(entry).Encode(file.Content); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// GetUserProfile handles retrieving the public profile of a user
+// @Summary Get a user profile
+// @Description Get the public profile of a user and the public campaigns they are a member of
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "Username"
+// @Success 200 {object} domain.PublicUserProfile "User profile"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "User not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /api/users/{username} [get]
+func (h *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) error {
+	profile, err := h.userUseCase.GetPublicProfile(chi.URLParam(r, "username"))
+	if err != nil {
+		if errors.Is(err, usecases.ErrUserNotFound) {
+			return utils.WriteJSONError(w, http.StatusNotFound, "User not found")
+		}
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(profile)
 }
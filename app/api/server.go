@@ -6,8 +6,11 @@ import (
 	_ "github.com/knands42/lorecrafter/app/api/docs" // Import the docs package
 	middleware2 "github.com/knands42/lorecrafter/app/api/middleware"
 	"github.com/knands42/lorecrafter/app/api/routes"
+	"github.com/knands42/lorecrafter/internal/adapter/mail"
 	"github.com/knands42/lorecrafter/internal/adapter/security"
 	"github.com/knands42/lorecrafter/internal/config"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/interfaces"
 	"github.com/knands42/lorecrafter/internal/usecases"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
 	"log"
@@ -30,11 +33,19 @@ type Server struct {
 
 	cfg config.Config
 
-	authUseCase     *usecases.AuthUseCase
-	authHandler     *routes.AuthHandler
-	userHandler     *routes.UserHandler
-	campaignHandler *routes.CampaignHandler
-	repo            sqlc.Querier
+	authUseCase       *usecases.AuthUseCase
+	userUseCase       *usecases.UserUseCase
+	authHandler       *routes.AuthHandler
+	userHandler       *routes.UserHandler
+	campaignHandler   *routes.CampaignHandler
+	invitationHandler *routes.InvitationHandler
+	characterHandler  *routes.CharacterHandler
+	timelineHandler   *routes.TimelineHandler
+	apiKeyHandler     *routes.APIKeyHandler
+	oidcHandler       *routes.OIDCHandler
+	passkeyHandler    *routes.PasskeyHandler
+	sessionHandler    *routes.SessionHandler
+	repo              sqlc.Querier
 }
 
 // NewServer creates a new HTTP server
@@ -42,6 +53,10 @@ func NewServer(cfg config.Config, repo sqlc.Querier) *Server {
 	router := chi.NewRouter()
 
 	// Set up middleware
+	if cfg.TrustProxyHeaders {
+		// Behind a reverse proxy the client address comes from X-Forwarded-For or X-Real-IP
+		router.Use(middleware.RealIP)
+	}
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Timeout(10 * time.Second))
@@ -57,22 +72,99 @@ func NewServer(cfg config.Config, repo sqlc.Querier) *Server {
 	}
 
 	// Set up adapters
-	tokenMakerAdapter, err := security.NewTokenMakerAdapter(cfg.PrivateKey, cfg.PublicKey)
+	tokenMakerAdapter, err := security.NewTokenMakerAdapter(
+		cfg.PrivateKey,
+		cfg.PublicKey,
+		cfg.VerificationKeys,
+		cfg.TokenVersion,
+		cfg.TokenAudience,
+	)
 	if err != nil {
 		log.Fatalf("Failed to create token maker: %v", err)
 	}
-	argon2Adapter := security.NewArgon2Adapter()
+	argon2Adapter := security.NewArgon2Adapter(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+	totpAdapter := security.NewTOTPAdapter(cfg.MFAIssuer)
+	// Two-factor authentication stays off until a key to encrypt the TOTP secrets is configured
+	var secretCipherAdapter interfaces.SecretCipher
+	if cfg.MFAEncryptionKey != "" {
+		secretCipherAdapter, err = security.NewAESCipherAdapter(cfg.MFAEncryptionKey)
+		if err != nil {
+			log.Fatalf("Failed to create secret cipher: %v", err)
+		}
+	} else {
+		log.Println("MFA_ENCRYPTION_KEY is not set, two-factor authentication can't be enabled")
+	}
+	oidcProviders := make(map[string]interfaces.OIDCProvider, len(cfg.OIDCProviders))
+	for _, provider := range cfg.OIDCProviders {
+		oidcProviders[provider.Name] = security.NewOIDCAdapter(
+			provider.Issuer,
+			provider.ClientID,
+			provider.ClientSecret,
+			provider.CallbackURL(cfg.OIDCCallbackBaseURL),
+			provider.Scopes,
+		)
+	}
+	webAuthnAdapter, err := security.NewWebAuthnAdapter(cfg.WebAuthnRPID, cfg.WebAuthnRPDisplayName, cfg.WebAuthnRPOrigins)
+	if err != nil {
+		log.Fatalf("Failed to create WebAuthn relying party: %v", err)
+	}
+	mailerAdapter, err := mail.NewMailerAdapter(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create mailer: %v", err)
+	}
 
 	// Set up use cases
 	ctx := context.Background()
-	authUseCase := usecases.NewAuthUseCase(ctx, repo, tokenMakerAdapter, argon2Adapter, cfg.TokenExpiry)
-	campaignUseCase := usecases.NewCampaignUseCase(ctx, repo)
+	authUseCase, err := usecases.NewAuthUseCase(
+		ctx,
+		repo,
+		tokenMakerAdapter,
+		argon2Adapter,
+		mailerAdapter,
+		totpAdapter,
+		secretCipherAdapter,
+		cfg.AppBaseURL,
+		cfg.TokenExpiry,
+		cfg.RefreshTokenExpiry,
+		cfg.VerificationTokenExpiry,
+		cfg.VerificationResendInterval,
+		cfg.PasswordResetExpiry,
+		cfg.PasswordResetResendInterval,
+		cfg.MFAPendingTokenExpiry,
+		usecases.LoginThrottleSettings{
+			MaxAccountAttempts: cfg.LoginMaxAccountAttempts,
+			MaxIPAttempts:      cfg.LoginMaxIPAttempts,
+			BaseLockout:        cfg.LoginBaseLockout,
+			MaxLockout:         cfg.LoginMaxLockout,
+			FailureWindow:      cfg.LoginFailureWindow,
+		},
+	)
+	if err != nil {
+		log.Fatalf("Failed to create auth use case: %v", err)
+	}
+	campaignUseCase := usecases.NewCampaignUseCase(ctx, repo, cfg.EmailVerificationRequired)
+	invitationUseCase := usecases.NewInvitationUseCase(ctx, repo, mailerAdapter, cfg.AppBaseURL, cfg.InvitationExpiry)
+	characterUseCase := usecases.NewCharacterUseCase(ctx, repo)
+	timelineUseCase := usecases.NewTimelineUseCase(ctx, repo)
+	userUseCase := usecases.NewUserUseCase(ctx, repo, authUseCase, cfg.AccountDeletionGracePeriod)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(ctx, repo)
+	oidcUseCase := usecases.NewOIDCUseCase(ctx, repo, authUseCase, oidcProviders, cfg.OIDCStateExpiry)
+	passkeyUseCase := usecases.NewPasskeyUseCase(ctx, repo, authUseCase, webAuthnAdapter, cfg.WebAuthnSessionExpiry)
+	sessionUseCase := usecases.NewSessionUseCase(ctx, repo, authUseCase)
 
 	// Set up HTTP handlers
 	server.authUseCase = authUseCase
+	server.userUseCase = userUseCase
 	server.authHandler = routes.NewAuthHandler(authUseCase)
-	server.userHandler = routes.NewUserHandler()
+	server.userHandler = routes.NewUserHandler(userUseCase)
 	server.campaignHandler = routes.NewCampaignHandler(campaignUseCase)
+	server.invitationHandler = routes.NewInvitationHandler(invitationUseCase)
+	server.characterHandler = routes.NewCharacterHandler(characterUseCase)
+	server.timelineHandler = routes.NewTimelineHandler(timelineUseCase)
+	server.apiKeyHandler = routes.NewAPIKeyHandler(apiKeyUseCase)
+	server.oidcHandler = routes.NewOIDCHandler(oidcUseCase)
+	server.passkeyHandler = routes.NewPasskeyHandler(passkeyUseCase)
+	server.sessionHandler = routes.NewSessionHandler(sessionUseCase)
 	server.repo = repo
 	server.cfg = cfg
 
@@ -89,6 +181,7 @@ func (s *Server) Start() {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
+	go s.purgeDeletedAccountsPeriodically()
 
 	s.gracefulShutdown()
 }
@@ -98,6 +191,27 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
+// PurgeDeletedAccounts deletes the accounts whose grace period is over and returns how many were deleted
+func (s *Server) PurgeDeletedAccounts() (int, error) {
+	return s.userUseCase.PurgeDeletedAccounts()
+}
+
+// purgeDeletedAccountsPeriodically purges the deleted accounts every purge interval while the server runs
+func (s *Server) purgeDeletedAccountsPeriodically() {
+	ticker := time.NewTicker(s.cfg.AccountPurgeInterval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		purged, err := s.PurgeDeletedAccounts()
+		if err != nil {
+			log.Printf("Error purging deleted accounts: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("Purged %d deleted accounts", purged)
+		}
+	}
+}
+
 // SetupRoutes sets up the routes for the server
 func (s *Server) setupRoutes() {
 	// Swagger UI
@@ -132,15 +246,33 @@ func (s *Server) setupRoutes() {
 		// Auth routes
 		r.Route("/auth", func(r chi.Router) {
 			s.authHandler.RegisterRoutes(r)
+			s.oidcHandler.RegisterRoutes(r)
+			s.passkeyHandler.RegisterRoutes(r)
 		})
 
 		// Protected routes (require authentication)
 		r.Group(func(r chi.Router) {
 			r.Use(middleware2.AuthMiddleware(s.authUseCase))
-			r.Get("/me", middleware2.ErrorHandlerMiddleware(s.userHandler.Me))
+			s.userHandler.RegisterRoutes(r)
+			s.apiKeyHandler.RegisterRoutes(r)
+			s.oidcHandler.RegisterAccountRoutes(r)
+			s.passkeyHandler.RegisterAccountRoutes(r)
+			s.sessionHandler.RegisterRoutes(r)
+
+			// Account routes
+			r.Group(func(r chi.Router) {
+				r.Use(middleware2.RequireScopes(domain.ScopeAccount))
+				r.Put("/me/password", middleware2.ErrorHandlerMiddleware(s.authHandler.ChangePassword))
+				r.Post("/me/mfa", middleware2.ErrorHandlerMiddleware(s.authHandler.EnrollMFA))
+				r.Post("/me/mfa/confirm", middleware2.ErrorHandlerMiddleware(s.authHandler.ConfirmMFA))
+				r.Delete("/me/mfa", middleware2.ErrorHandlerMiddleware(s.authHandler.DisableMFA))
+			})
 
 			// Campaign routes
 			s.campaignHandler.RegisterRoutes(r)
+			s.invitationHandler.RegisterRoutes(r)
+			s.characterHandler.RegisterRoutes(r)
+			s.timelineHandler.RegisterRoutes(r)
 		})
 	})
 }
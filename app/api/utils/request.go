@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+)
+
+// Longer user agents are cut, they are only shown to tell sessions apart
+const maxUserAgentLength = 512
+
+// ClientIP returns the IP address of the client of a request, without the port
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// Addresses set from proxy headers have no port
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// SessionClient describes the device a request comes from, for the session it may start
+func SessionClient(r *http.Request) domain.SessionClient {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return domain.SessionClient{
+		UserAgent: userAgent,
+		IPAddress: ClientIP(r),
+	}
+}
@@ -0,0 +1,234 @@
+// Command keyring rotates the Ed25519 keys signing the PASETO tokens without logging anyone out.
+//
+// A rotation takes two deploys:
+//
+//	keyring generate   creates the next key and adds its public key to PASETO_VERIFICATION_KEYS,
+//	                   deploy so every instance accepts tokens signed with it
+//	keyring promote    signs with the next key and keeps the previous public key in
+//	                   PASETO_VERIFICATION_KEYS, deploy so tokens signed before keep working
+//	keyring retire ID  drops a verification key once the tokens it signed have expired
+//	keyring list       shows the key IDs in use
+//
+// The keys are read from and written to an env file, .env unless -env is given.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/knands42/lorecrafter/internal/adapter/security"
+)
+
+const (
+	privateKeyVar       = "PASETO_PRIVATE_KEY"
+	publicKeyVar        = "PASETO_PUBLIC_KEY"
+	verificationKeysVar = "PASETO_VERIFICATION_KEYS"
+	// nextPrivateKeyVar holds the generated key until it is promoted, the application doesn't read it
+	nextPrivateKeyVar = "PASETO_NEXT_PRIVATE_KEY"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	flags := flag.NewFlagSet("keyring", flag.ExitOnError)
+	envPath := flags.String("env", ".env", "env file holding the keys")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: keyring [-env file] generate|promote|retire KEY_ID|list")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	env, err := readEnvFile(*envPath)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *envPath, err)
+	}
+
+	switch flags.Arg(0) {
+	case "generate":
+		err = generate(env)
+	case "promote":
+		err = promote(env)
+	case "retire":
+		if flags.NArg() < 2 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		err = retire(env, flags.Arg(1))
+	case "list":
+		err = list(env)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if flags.Arg(0) != "list" {
+		if err := env.write(*envPath); err != nil {
+			log.Fatalf("Failed to write %s: %v", *envPath, err)
+		}
+	}
+}
+
+// generate creates the next signing key and starts accepting the tokens it will sign
+func generate(env *envFile) error {
+	if env.get(nextPrivateKeyVar) != "" {
+		return errors.New("a key is already waiting to be promoted, run promote first")
+	}
+
+	key, err := security.GenerateSigningKey()
+	if err != nil {
+		return err
+	}
+
+	env.set(nextPrivateKeyVar, key.PrivateKey)
+	env.set(verificationKeysVar, strings.Join(append(env.list(verificationKeysVar), key.PublicKey), ","))
+
+	log.Printf("Generated key %s, deploy so every instance accepts it then run promote", key.KeyID)
+	return nil
+}
+
+// promote signs with the next key, the current public key stays accepted until it is retired
+func promote(env *envFile) error {
+	next := env.get(nextPrivateKeyVar)
+	if next == "" {
+		return errors.New("no key to promote, run generate first")
+	}
+
+	key, err := security.LoadSigningKey(next)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", nextPrivateKeyVar, err)
+	}
+
+	verificationKeys := slices.DeleteFunc(env.list(verificationKeysVar), func(publicKey string) bool {
+		return publicKey == key.PublicKey
+	})
+	if current := env.get(publicKeyVar); current != "" {
+		verificationKeys = append(verificationKeys, current)
+	}
+
+	env.set(privateKeyVar, key.PrivateKey)
+	env.set(publicKeyVar, key.PublicKey)
+	env.set(verificationKeysVar, strings.Join(verificationKeys, ","))
+	env.set(nextPrivateKeyVar, "")
+
+	log.Printf("Promoted key %s, retire the previous key once the tokens it signed have expired", key.KeyID)
+	return nil
+}
+
+// retire stops accepting the tokens signed by a key
+func retire(env *envFile, keyID string) error {
+	verificationKeys := env.list(verificationKeysVar)
+	remaining := slices.DeleteFunc(slices.Clone(verificationKeys), func(publicKey string) bool {
+		id, err := security.PublicKeyID(publicKey)
+		return err == nil && id == keyID
+	})
+	if len(remaining) == len(verificationKeys) {
+		return fmt.Errorf("key %s is not a verification key", keyID)
+	}
+
+	env.set(verificationKeysVar, strings.Join(remaining, ","))
+
+	log.Printf("Retired key %s", keyID)
+	return nil
+}
+
+// list prints the key IDs of the signing, next and verification keys
+func list(env *envFile) error {
+	if publicKey := env.get(publicKeyVar); publicKey != "" {
+		id, err := security.PublicKeyID(publicKey)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", publicKeyVar, err)
+		}
+		fmt.Printf("signing       %s\n", id)
+	}
+
+	if next := env.get(nextPrivateKeyVar); next != "" {
+		key, err := security.LoadSigningKey(next)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", nextPrivateKeyVar, err)
+		}
+		fmt.Printf("next          %s\n", key.KeyID)
+	}
+
+	for _, publicKey := range env.list(verificationKeysVar) {
+		id, err := security.PublicKeyID(publicKey)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", verificationKeysVar, err)
+		}
+		fmt.Printf("verification  %s\n", id)
+	}
+
+	return nil
+}
+
+// envFile keeps the lines of an env file so everything but the keys is written back untouched
+type envFile struct {
+	lines []string
+}
+
+func readEnvFile(path string) (*envFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	env := &envFile{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		env.lines = append(env.lines, scanner.Text())
+	}
+
+	return env, scanner.Err()
+}
+
+func (env *envFile) get(name string) string {
+	for _, line := range env.lines {
+		if value, ok := strings.CutPrefix(line, name+"="); ok {
+			return strings.Trim(value, `"`)
+		}
+	}
+
+	return ""
+}
+
+func (env *envFile) list(name string) []string {
+	var values []string
+	for _, value := range strings.Split(env.get(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func (env *envFile) set(name, value string) {
+	for i, line := range env.lines {
+		if strings.HasPrefix(line, name+"=") {
+			env.lines[i] = name + "=" + value
+			return
+		}
+	}
+
+	env.lines = append(env.lines, name+"="+value)
+}
+
+func (env *envFile) write(path string) error {
+	return os.WriteFile(path, []byte(strings.Join(env.lines, "\n")+"\n"), 0o600)
+}
@@ -0,0 +1,4 @@
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS invite_code_uses,
+    DROP COLUMN IF EXISTS invite_code_max_uses,
+    DROP COLUMN IF EXISTS invite_code_expires_at;
\ No newline at end of file
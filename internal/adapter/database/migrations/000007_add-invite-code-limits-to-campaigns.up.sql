@@ -0,0 +1,4 @@
+ALTER TABLE campaigns
+    ADD COLUMN invite_code_expires_at TIMESTAMPTZ,
+    ADD COLUMN invite_code_max_uses INTEGER,
+    ADD COLUMN invite_code_uses INTEGER NOT NULL DEFAULT 0;
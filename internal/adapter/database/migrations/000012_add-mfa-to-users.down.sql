@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS mfa_recovery_codes;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS mfa_last_used_step,
+    DROP COLUMN IF EXISTS mfa_enabled,
+    DROP COLUMN IF EXISTS mfa_secret;
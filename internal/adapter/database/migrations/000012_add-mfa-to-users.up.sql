@@ -0,0 +1,15 @@
+-- mfa_secret is encrypted by the application, mfa_last_used_step keeps TOTP codes from being replayed
+ALTER TABLE users
+    ADD COLUMN mfa_secret TEXT,
+    ADD COLUMN mfa_enabled BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN mfa_last_used_step BIGINT;
+
+CREATE TABLE mfa_recovery_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
@@ -0,0 +1,7 @@
+-- Failed login attempts per account ("account:<username>") and per IP address ("ip:<address>")
+CREATE TABLE login_throttles (
+    key TEXT PRIMARY KEY,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
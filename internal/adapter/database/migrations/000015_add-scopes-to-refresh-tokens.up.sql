@@ -0,0 +1,3 @@
+-- Sessions started before scopes existed had full access
+ALTER TABLE refresh_tokens ADD COLUMN scopes TEXT[] NOT NULL DEFAULT ARRAY['account', 'campaigns:write', 'characters:write', 'read'];
+ALTER TABLE refresh_tokens ALTER COLUMN scopes DROP DEFAULT;
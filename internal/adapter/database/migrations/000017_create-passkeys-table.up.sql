@@ -0,0 +1,31 @@
+-- WebAuthn credentials registered by users, a user can register several authenticators
+CREATE TABLE passkeys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    attestation_type VARCHAR(50) NOT NULL,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    aaguid BYTEA NOT NULL,
+    -- Signature counter of the authenticator, a counter that doesn't increase hints at a cloned authenticator
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_passkeys_user_id ON passkeys(user_id);
+
+-- Pending registration and login ceremonies, the session ID is only stored hashed and user_id is set for registrations
+CREATE TABLE webauthn_sessions (
+    session_hash VARCHAR(64) PRIMARY KEY,
+    ceremony VARCHAR(20) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    data JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webauthn_sessions_expires_at ON webauthn_sessions(expires_at);
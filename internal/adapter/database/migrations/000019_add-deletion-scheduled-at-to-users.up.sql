@@ -0,0 +1,6 @@
+-- An account asked to be deleted keeps working until deletion_scheduled_at, logging in again cancels the deletion
+ALTER TABLE users
+    ADD COLUMN deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
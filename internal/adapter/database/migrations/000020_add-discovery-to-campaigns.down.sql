@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_campaigns_search;
+DROP INDEX IF EXISTS idx_campaigns_game_system;
+DROP INDEX IF EXISTS idx_campaigns_tags;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS clone_count,
+    DROP COLUMN IF EXISTS game_system,
+    DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,13 @@
+-- Public campaigns are found by their tags, game system and text, and sorted by how often they were cloned
+ALTER TABLE campaigns
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN game_system VARCHAR(50),
+    ADD COLUMN clone_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_tags ON campaigns USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_campaigns_game_system ON campaigns(lower(game_system));
+
+-- Matches the expression searched by SearchPublicCampaigns
+CREATE INDEX IF NOT EXISTS idx_campaigns_search ON campaigns USING GIN (
+    to_tsvector('english', title || ' ' || coalesce(setting_summary, '') || ' ' || coalesce(setting, ''))
+) WHERE is_public;
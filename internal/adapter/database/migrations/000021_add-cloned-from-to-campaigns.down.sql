@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_campaigns_cloned_from;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS cloned_from;
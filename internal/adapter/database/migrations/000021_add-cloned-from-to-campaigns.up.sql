@@ -0,0 +1,5 @@
+-- Clones remember the public campaign they were copied from, the link is dropped when the source is deleted
+ALTER TABLE campaigns
+    ADD COLUMN cloned_from UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_cloned_from ON campaigns(cloned_from);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_campaign_members_user_id_last_accessed;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS archived_at;
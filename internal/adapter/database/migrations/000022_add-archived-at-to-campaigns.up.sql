@@ -0,0 +1,6 @@
+-- Archived campaigns are kept out of the campaign list of their members unless asked for
+ALTER TABLE campaigns
+    ADD COLUMN archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_campaign_members_user_id_last_accessed
+    ON campaign_members(user_id, (coalesce(last_accessed, joined_at)));
@@ -0,0 +1,3 @@
+-- Bumped on every update, the version is the ETag clients send back in If-Match to avoid overwriting each other
+ALTER TABLE campaigns
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
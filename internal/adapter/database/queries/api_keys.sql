@@ -0,0 +1,43 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    id,
+    user_id,
+    name,
+    key_prefix,
+    key_hash,
+    scopes,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListAPIKeysByUser :many
+SELECT * FROM api_keys
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT api_keys.*, users.username, users.email
+FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+WHERE api_keys.key_hash = $1
+  AND api_keys.revoked_at IS NULL
+  AND (api_keys.expires_at IS NULL OR api_keys.expires_at > CURRENT_TIMESTAMP)
+LIMIT 1;
+
+-- name: TouchAPIKey :exec
+-- Records the use of a key, at most once per interval to spare a write on every request
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = @id
+  AND (last_used_at IS NULL OR last_used_at < @used_before::timestamptz);
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserAPIKeys :exec
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND revoked_at IS NULL;
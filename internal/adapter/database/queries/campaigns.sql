@@ -6,23 +6,16 @@ INSERT INTO campaigns (
     setting,
     image_url,
     is_public,
-    created_by
+    created_by,
+    tags,
+    game_system,
+    cloned_from
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: GetCampaignByID :one
-SELECT
-    c.id,
-    c.title,
-    c.setting_summary,
-    c.setting,
-    c.image_url,
-    c.is_public,
-    c.invite_code,
-    c.created_by,
-    c.created_at,
-    c.updated_at
+SELECT c.*
     FROM campaigns as c
                   LEFT JOIN campaign_members as cm
                             ON c.id = cm.campaign_id AND cm.user_id = $2
@@ -32,47 +25,96 @@ WHERE c.id = $1 AND (
 LIMIT 1;
 
 -- name: UpdateCampaign :one
-UPDATE campaigns as c
-SET 
-    title = $3,
-    setting_summary = $4,
-    setting = $5,
-    image_url = $6,
-    is_public = $7,
+UPDATE campaigns
+SET
+    title = $2,
+    setting_summary = $3,
+    setting = $4,
+    image_url = $5,
+    is_public = $6,
+    tags = $7,
+    game_system = $8,
+    version = version + 1,
     updated_at = CURRENT_TIMESTAMP
-FROM campaign_members AS cm
-WHERE cm.campaign_id = c.id
-  AND c.id = $1
-  AND (
-      c.is_public = true OR
-      cm.user_id = $2
-    )
-RETURNING
+WHERE id = $1
+RETURNING *;
+
+-- name: PatchCampaign :one
+-- Only applies when the campaign is still at the version the patch was computed from
+UPDATE campaigns
+SET title = @title,
+    setting_summary = @setting_summary,
+    setting = @setting,
+    image_url = @image_url,
+    is_public = @is_public,
+    tags = @tags,
+    game_system = @game_system,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND version = @version
+RETURNING *;
+
+-- name: DeleteCampaign :exec
+DELETE FROM campaigns
+WHERE id = $1;
+
+-- name: ListMemberCampaigns :many
+-- Lists the campaigns a user is a member of along with their role, sorted by sort_key then ID, both descending.
+-- The sort key and ID of the last campaign of a page are the cursor of the next one.
+WITH matches AS (
+    SELECT
+        c.id,
+        c.title,
+        c.setting_summary,
+        c.image_url,
+        c.is_public,
+        c.tags,
+        c.game_system,
+        c.created_by,
+        c.created_at,
+        c.updated_at,
+        c.archived_at,
+        cm.role,
+        cm.joined_at,
+        cm.last_accessed
+    FROM campaigns AS c
+    JOIN campaign_members AS cm ON cm.campaign_id = c.id
+    WHERE cm.user_id = @user_id
+      AND (sqlc.narg(role)::member_role IS NULL OR cm.role = sqlc.narg(role)::member_role)
+      AND (c.archived_at IS NOT NULL) = @archived::boolean
+      AND (sqlc.narg(query)::text IS NULL
+        OR position(lower(sqlc.narg(query)::text) IN lower(c.title || ' ' || coalesce(c.setting_summary, ''))) > 0)
+), keyed AS (
+    SELECT
+        matches.*,
+        (CASE @sort::text
+            WHEN 'last_accessed' THEN extract(epoch FROM coalesce(matches.last_accessed, matches.joined_at)) * 1000000
+            ELSE extract(epoch FROM matches.updated_at) * 1000000
+        END)::bigint AS sort_key
+    FROM matches
+)
+SELECT * FROM keyed
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+   OR (keyed.sort_key, keyed.id) < (sqlc.narg(cursor_key)::bigint, sqlc.narg(cursor_id)::uuid)
+ORDER BY keyed.sort_key DESC, keyed.id DESC
+LIMIT @page_size;
+
+-- name: ListPublicCampaignsByUserID :many
+-- The setting and invite code are left out, they are only shown to members
+SELECT
     c.id,
     c.title,
     c.setting_summary,
-    c.setting,
     c.image_url,
-    c.is_public,
-    c.invite_code,
+    c.tags,
+    c.game_system,
     c.created_by,
     c.created_at,
-    c.updated_at;
-
--- name: DeleteCampaign :exec
-DELETE FROM campaigns AS c
-USING campaign_members AS cm
-WHERE cm.campaign_id = c.id
-  AND c.id = $1
-  AND (
-     (cm.user_id = $2 AND cm.role = 'gm'::member_role)
-     OR c.created_by = $2
-     );
-
--- name: ListCampaignsByUserID :many
-SELECT c.* FROM campaigns c
+    c.updated_at
+FROM campaigns c
 JOIN campaign_members cm ON c.id = cm.campaign_id
-WHERE cm.user_id = $1;
+WHERE cm.user_id = $1 AND c.is_public = true
+ORDER BY c.created_at DESC;
 
 -- name: CreateCampaignMember :one
 INSERT INTO campaign_members (
@@ -97,21 +139,156 @@ SET
 WHERE campaign_id = $1 AND user_id = $2
 RETURNING *;
 
--- name: DeleteCampaignMember :exec
-DELETE FROM campaign_members
+-- name: TouchCampaignMember :exec
+UPDATE campaign_members
+SET last_accessed = CURRENT_TIMESTAMP
 WHERE campaign_id = $1 AND user_id = $2;
 
+-- name: DeleteCampaignMember :execrows
+-- Deletes a membership unless it is the last GM of the campaign. The GM rows are locked so two GMs removed
+-- at the same time can't both see the other one as remaining.
+DELETE FROM campaign_members
+WHERE campaign_members.campaign_id = @campaign_id
+  AND campaign_members.user_id = @user_id
+  AND (
+    campaign_members.role <> 'gm'
+    OR (
+        SELECT COUNT(*) FROM (
+            SELECT 1 FROM campaign_members gms
+            WHERE gms.campaign_id = @campaign_id AND gms.role = 'gm'
+            FOR UPDATE
+        ) locked_gms
+    ) > 1
+  );
+
 -- name: ListCampaignMembers :many
-SELECT * FROM campaign_members
-WHERE campaign_id = $1;
+SELECT
+    cm.id,
+    cm.campaign_id,
+    cm.user_id,
+    cm.role,
+    cm.joined_at,
+    cm.last_accessed,
+    u.username,
+    u.avatar_url
+FROM campaign_members AS cm
+JOIN users AS u ON u.id = cm.user_id
+WHERE cm.campaign_id = $1
+ORDER BY cm.joined_at;
 
 -- name: GenerateInviteCode :one
 UPDATE campaigns
-SET invite_code = $2
+SET
+    invite_code = $2,
+    invite_code_expires_at = $3,
+    invite_code_max_uses = $4,
+    invite_code_uses = 0
 WHERE id = $1
 RETURNING *;
 
+-- name: RevokeInviteCode :exec
+UPDATE campaigns
+SET
+    invite_code = NULL,
+    invite_code_expires_at = NULL,
+    invite_code_max_uses = NULL,
+    invite_code_uses = 0
+WHERE id = $1;
+
+-- name: RedeemInviteCode :one
+UPDATE campaigns
+SET invite_code_uses = invite_code_uses + 1
+WHERE invite_code = $1
+  AND (invite_code_expires_at IS NULL OR invite_code_expires_at > CURRENT_TIMESTAMP)
+  AND (invite_code_max_uses IS NULL OR invite_code_uses < invite_code_max_uses)
+RETURNING *;
+
+-- name: ReleaseInviteCodeUse :exec
+-- Gives back a use redeemed by a join that failed, unless the code was rotated since
+UPDATE campaigns
+SET invite_code_uses = invite_code_uses - 1
+WHERE id = $1 AND invite_code = $2 AND invite_code_uses > 0;
+
 -- name: GetCampaignByInviteCode :one
 SELECT * FROM campaigns
 WHERE invite_code = $1
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: ListCampaignsCreatedByUser :many
+SELECT * FROM campaigns
+WHERE created_by = $1
+ORDER BY created_at;
+
+-- name: GetCampaignSuccessor :one
+-- The member taking over a campaign from its creator, game masters first then whoever joined earliest
+SELECT * FROM campaign_members
+WHERE campaign_id = @campaign_id AND user_id <> @user_id
+ORDER BY (role = 'gm'::member_role) DESC, joined_at
+LIMIT 1;
+
+-- name: TransferCampaign :exec
+UPDATE campaigns
+SET created_by = @created_by,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id;
+
+-- name: ArchiveCampaign :one
+UPDATE campaigns
+SET archived_at = coalesce(archived_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: UnarchiveCampaign :one
+UPDATE campaigns
+SET archived_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: IncrementCampaignCloneCount :exec
+UPDATE campaigns
+SET clone_count = clone_count + 1
+WHERE id = $1;
+
+-- name: SearchPublicCampaigns :many
+-- Lists public campaigns matching the filters, sorted by sort_key then ID, both descending.
+-- The sort key and ID of the last campaign of a page are the cursor of the next one.
+WITH matches AS (
+    SELECT
+        c.id,
+        c.title,
+        c.setting_summary,
+        c.image_url,
+        c.tags,
+        c.game_system,
+        c.clone_count,
+        c.created_by,
+        u.username AS creator_username,
+        (SELECT COUNT(*) FROM campaign_members AS cm WHERE cm.campaign_id = c.id) AS member_count,
+        c.created_at,
+        c.updated_at
+    FROM campaigns AS c
+    JOIN users AS u ON u.id = c.created_by
+    WHERE c.is_public
+      AND (sqlc.narg(query)::text IS NULL
+        OR to_tsvector('english', c.title || ' ' || coalesce(c.setting_summary, '') || ' ' || coalesce(c.setting, ''))
+           @@ websearch_to_tsquery('english', sqlc.narg(query)::text))
+      AND c.tags @> @tags::text[]
+      AND (sqlc.narg(game_system)::text IS NULL OR lower(c.game_system) = lower(sqlc.narg(game_system)::text))
+      AND (sqlc.narg(creator)::text IS NULL OR u.username = sqlc.narg(creator)::text)
+), keyed AS (
+    SELECT
+        matches.*,
+        (CASE @sort::text
+            WHEN 'most_cloned' THEN matches.clone_count::bigint
+            WHEN 'most_members' THEN matches.member_count
+            ELSE (extract(epoch FROM matches.created_at) * 1000000)::bigint
+        END)::bigint AS sort_key
+    FROM matches
+)
+SELECT * FROM keyed
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+   OR (keyed.sort_key, keyed.id) < (sqlc.narg(cursor_key)::bigint, sqlc.narg(cursor_id)::uuid)
+ORDER BY keyed.sort_key DESC, keyed.id DESC
+LIMIT @page_size;
@@ -0,0 +1,41 @@
+-- name: CreateCharacter :one
+INSERT INTO characters (
+    id, name, race, class, level, appearance, personality, backstory, image_url,
+    campaign_id, user_id, is_npc, metadata
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING *;
+
+-- name: GetCharacterByID :one
+SELECT * FROM characters
+WHERE id = $1 AND campaign_id = $2;
+
+-- name: ListCharactersByCampaign :many
+SELECT * FROM characters
+WHERE campaign_id = $1
+ORDER BY is_npc, name;
+
+-- name: UpdateCharacter :one
+UPDATE characters
+SET name = $3,
+    race = $4,
+    class = $5,
+    level = $6,
+    appearance = $7,
+    personality = $8,
+    backstory = $9,
+    image_url = $10,
+    is_npc = $11,
+    metadata = $12,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND campaign_id = $2
+RETURNING *;
+
+-- name: DeleteCharacter :exec
+DELETE FROM characters
+WHERE id = $1 AND campaign_id = $2;
+
+-- name: ListCharactersByUser :many
+SELECT * FROM characters
+WHERE user_id = $1
+ORDER BY created_at;
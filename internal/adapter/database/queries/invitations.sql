@@ -0,0 +1,116 @@
+-- name: CreateInvitation :one
+INSERT INTO invitations (
+    id,
+    campaign_id,
+    email,
+    invited_by,
+    token,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetInvitationByToken :one
+SELECT * FROM invitations
+WHERE token = $1
+LIMIT 1;
+
+-- name: GetPendingInvitationByCampaignAndEmail :one
+SELECT * FROM invitations
+WHERE campaign_id = $1
+  AND lower(email) = lower(sqlc.arg(email))
+  AND status = 'pending'::invitation_status
+  AND expires_at > CURRENT_TIMESTAMP
+LIMIT 1;
+
+-- name: ListInvitationsByCampaign :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at
+FROM invitations AS i
+WHERE i.campaign_id = $1
+ORDER BY i.created_at DESC;
+
+-- name: ListPendingInvitationsByEmail :many
+-- The token is left out, invitees open the link of the invitation email to answer it
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at,
+    c.title AS campaign_title,
+    u.username AS invited_by_username
+FROM invitations AS i
+JOIN campaigns AS c ON c.id = i.campaign_id
+JOIN users AS u ON u.id = i.invited_by
+WHERE lower(i.email) = lower(sqlc.arg(email))
+  AND i.status = 'pending'::invitation_status
+ORDER BY i.created_at DESC;
+
+-- name: UpdateInvitationStatus :one
+UPDATE invitations
+SET
+    status = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: ExpireInvitations :exec
+UPDATE invitations
+SET
+    status = 'expired'::invitation_status,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'pending'::invitation_status
+  AND expires_at <= CURRENT_TIMESTAMP;
+
+-- name: DeleteInvitation :execrows
+DELETE FROM invitations
+WHERE id = $1 AND campaign_id = $2;
+
+-- name: ListInvitationsSentByUser :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at
+FROM invitations AS i
+WHERE i.invited_by = $1
+ORDER BY i.created_at;
+
+-- name: ListInvitationsReceivedByEmail :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at
+FROM invitations AS i
+WHERE lower(i.email) = lower(sqlc.arg(email))
+ORDER BY i.created_at;
+
+-- name: HandOverInvitations :exec
+-- Hands the invitations a user sent to campaigns created by someone else over to the campaign creator
+UPDATE invitations AS i
+SET invited_by = c.created_by,
+    updated_at = CURRENT_TIMESTAMP
+FROM campaigns AS c
+WHERE c.id = i.campaign_id
+  AND i.invited_by = @user_id
+  AND c.created_by <> @user_id;
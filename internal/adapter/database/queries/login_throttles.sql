@@ -0,0 +1,32 @@
+-- name: ListLoginThrottles :many
+SELECT * FROM login_throttles
+WHERE key = ANY(@keys::text[]);
+
+-- name: RecordLoginFailure :one
+-- Counts a failed attempt, starting over once the last failure and lockout are older than reset_before
+INSERT INTO login_throttles (
+    key,
+    failed_count
+) VALUES (
+    @key, 1
+)
+ON CONFLICT (key) DO UPDATE
+SET failed_count = CASE
+        WHEN GREATEST(login_throttles.updated_at, login_throttles.locked_until) < @reset_before::timestamptz THEN 1
+        ELSE login_throttles.failed_count + 1
+    END,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: LockLogin :exec
+UPDATE login_throttles
+SET locked_until = $2
+WHERE key = $1;
+
+-- name: ClearLoginThrottle :exec
+DELETE FROM login_throttles
+WHERE key = $1;
+
+-- name: DeleteStaleLoginThrottles :exec
+DELETE FROM login_throttles
+WHERE GREATEST(updated_at, locked_until) < @reset_before::timestamptz;
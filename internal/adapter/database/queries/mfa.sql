@@ -0,0 +1,50 @@
+-- name: SetUserMFASecret :execrows
+-- Starts a new enrollment, unless two-factor authentication is already enabled
+UPDATE users
+SET mfa_secret = $2,
+    mfa_last_used_step = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND NOT mfa_enabled;
+
+-- name: EnableUserMFA :execrows
+UPDATE users
+SET mfa_enabled = true,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND mfa_secret IS NOT NULL AND NOT mfa_enabled;
+
+-- name: DisableUserMFA :exec
+UPDATE users
+SET mfa_secret = NULL,
+    mfa_enabled = false,
+    mfa_last_used_step = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ClaimMFAStep :execrows
+-- Records the time step of an accepted TOTP code, failing if it or a later one was already used
+UPDATE users
+SET mfa_last_used_step = @step::bigint
+WHERE id = @id
+  AND (mfa_last_used_step IS NULL OR mfa_last_used_step < @step::bigint);
+
+-- name: CreateMFARecoveryCode :exec
+INSERT INTO mfa_recovery_codes (
+    id,
+    user_id,
+    code_hash
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: ListUnusedMFARecoveryCodes :many
+SELECT * FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: UseMFARecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
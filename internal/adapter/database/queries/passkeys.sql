@@ -0,0 +1,55 @@
+-- name: CreatePasskey :one
+INSERT INTO passkeys (
+    id,
+    user_id,
+    name,
+    credential_id,
+    public_key,
+    attestation_type,
+    transports,
+    aaguid,
+    sign_count,
+    backup_eligible,
+    backup_state
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: ListPasskeysByUser :many
+SELECT * FROM passkeys
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetPasskeyByCredentialID :one
+SELECT * FROM passkeys
+WHERE credential_id = $1;
+
+-- name: RecordPasskeyUse :execrows
+-- Only moves the signature counter forward, so a concurrent login replaying the same counter fails
+UPDATE passkeys
+SET sign_count = @sign_count,
+    backup_state = @backup_state,
+    last_used_at = CURRENT_TIMESTAMP
+WHERE id = @id
+  AND (sign_count < @sign_count OR (sign_count = 0 AND @sign_count::bigint = 0));
+
+-- name: DeletePasskey :execrows
+DELETE FROM passkeys
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateWebAuthnSession :exec
+INSERT INTO webauthn_sessions (
+    session_hash, ceremony, user_id, data, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ConsumeWebAuthnSession :one
+-- A session can only be used once, returning nothing if it is unknown, expired or of another ceremony
+DELETE FROM webauthn_sessions
+WHERE session_hash = $1 AND ceremony = $2 AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteExpiredWebAuthnSessions :exec
+DELETE FROM webauthn_sessions
+WHERE expires_at <= CURRENT_TIMESTAMP;
@@ -0,0 +1,19 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+    id, user_id, token_hash, expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ConsumePasswordResetToken :one
+-- Marks a reset token as used, returning nothing if it is unknown, expired or already used
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeletePasswordResetTokensByUser :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1;
@@ -0,0 +1,58 @@
+-- name: CreateSession :one
+INSERT INTO sessions (
+    id, user_id, user_agent, ip_address, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetActiveSession :one
+SELECT * FROM sessions
+WHERE id = $1
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: ListActiveSessionsByUser :many
+SELECT * FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+ORDER BY last_seen_at DESC;
+
+-- name: TouchSession :exec
+-- Records the last request of a session, at most once per interval to spare a write on every request
+UPDATE sessions
+SET last_seen_at = CURRENT_TIMESTAMP
+WHERE id = @id
+  AND last_seen_at < @seen_before::timestamptz;
+
+-- name: ExtendSession :exec
+-- A refresh keeps the session alive as long as its new refresh token
+UPDATE sessions
+SET last_seen_at = CURRENT_TIMESTAMP,
+    expires_at = @expires_at
+WHERE id = @id;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = @id
+  AND user_id = @user_id
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: RevokeUserSessions :exec
+-- Ends every session of a user except keep_id, when given
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = @user_id
+  AND revoked_at IS NULL
+  AND (sqlc.narg(keep_id)::uuid IS NULL OR id <> sqlc.narg(keep_id));
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions
+WHERE expires_at < CURRENT_TIMESTAMP;
@@ -0,0 +1,48 @@
+-- name: CreateTimelineEvent :one
+INSERT INTO timeline_events (
+    id, campaign_id, title, description, event_date, is_public, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetTimelineEventByID :one
+SELECT * FROM timeline_events
+WHERE id = $1 AND campaign_id = $2;
+
+-- name: ListTimelineEvents :many
+-- Events without a date are listed last and excluded whenever a range filter is applied
+SELECT * FROM timeline_events
+WHERE campaign_id = @campaign_id
+  AND (@include_secret::boolean OR is_public)
+  AND (sqlc.narg(from_date)::timestamptz IS NULL OR event_date >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::timestamptz IS NULL OR event_date <= sqlc.narg(to_date))
+ORDER BY event_date NULLS LAST, created_at;
+
+-- name: UpdateTimelineEvent :one
+UPDATE timeline_events
+SET title = $3,
+    description = $4,
+    event_date = $5,
+    is_public = $6,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND campaign_id = $2
+RETURNING *;
+
+-- name: DeleteTimelineEvent :exec
+DELETE FROM timeline_events
+WHERE id = $1 AND campaign_id = $2;
+
+-- name: ListTimelineEventsByCreator :many
+SELECT * FROM timeline_events
+WHERE created_by = $1
+ORDER BY created_at;
+
+-- name: HandOverTimelineEvents :exec
+-- Hands the events a user wrote in campaigns created by someone else over to the campaign creator
+UPDATE timeline_events AS te
+SET created_by = c.created_by,
+    updated_at = CURRENT_TIMESTAMP
+FROM campaigns AS c
+WHERE c.id = te.campaign_id
+  AND te.created_by = @user_id
+  AND c.created_by <> @user_id;
@@ -0,0 +1,69 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (
+    id, user_id, family_id, token_hash, access_token_id, access_token_expires_at, expires_at, scopes
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetRefreshTokenByHash :one
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1;
+
+-- name: MarkRefreshTokenUsed :execrows
+-- Only one request can rotate a refresh token, any other sees zero affected rows
+UPDATE refresh_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamilyAccessTokens :exec
+-- Blocks every access token issued alongside a refresh token of the family that is still alive
+INSERT INTO revoked_tokens (token_id, expires_at)
+SELECT access_token_id, access_token_expires_at
+FROM refresh_tokens
+WHERE family_id = $1 AND access_token_expires_at > CURRENT_TIMESTAMP
+ON CONFLICT (token_id) DO NOTHING;
+
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (token_id, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (token_id) DO NOTHING;
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_tokens
+    WHERE token_id = $1
+);
+
+-- name: DeleteExpiredTokens :exec
+WITH expired_revoked_tokens AS (
+    DELETE FROM revoked_tokens
+    WHERE revoked_tokens.expires_at < CURRENT_TIMESTAMP
+)
+DELETE FROM refresh_tokens
+WHERE refresh_tokens.expires_at < CURRENT_TIMESTAMP;
+
+-- name: GetRefreshTokenByAccessTokenID :one
+SELECT * FROM refresh_tokens
+WHERE access_token_id = $1;
+
+-- name: RevokeUserAccessTokens :exec
+-- Blocks the live access tokens of every session of a user except the one of keep_family_id, when given
+INSERT INTO revoked_tokens (token_id, expires_at)
+SELECT access_token_id, access_token_expires_at
+FROM refresh_tokens
+WHERE user_id = @user_id
+  AND access_token_expires_at > CURRENT_TIMESTAMP
+  AND (sqlc.narg(keep_family_id)::uuid IS NULL OR family_id <> sqlc.narg(keep_family_id))
+ON CONFLICT (token_id) DO NOTHING;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = @user_id
+  AND revoked_at IS NULL
+  AND (sqlc.narg(keep_family_id)::uuid IS NULL OR family_id <> sqlc.narg(keep_family_id));
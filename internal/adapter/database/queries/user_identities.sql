@@ -0,0 +1,41 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+    id, user_id, provider, issuer, subject, email
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE issuer = $1 AND subject = $2;
+
+-- name: ListUserIdentities :many
+SELECT * FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (
+    state_hash, provider, nonce, code_verifier, user_id, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ConsumeOIDCLoginState :one
+-- A state can only be used once, returning nothing if it is unknown or expired
+DELETE FROM oidc_login_states
+WHERE state_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLoginStates :exec
+DELETE FROM oidc_login_states
+WHERE expires_at <= CURRENT_TIMESTAMP;
@@ -26,4 +26,78 @@ LIMIT 1;
 -- name: GetUserByUsernameOrEmail :one
 SELECT * FROM users
 WHERE username = $1 OR email = $2
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: ActivateUser :one
+UPDATE users
+SET is_active = true,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkVerificationSent :execrows
+-- Claims the right to send a verification email, unless one was sent after throttle_before
+UPDATE users
+SET verification_sent_at = CURRENT_TIMESTAMP
+WHERE id = @id
+  AND NOT is_active
+  AND (verification_sent_at IS NULL OR verification_sent_at <= @throttle_before::timestamptz);
+
+-- name: MarkPasswordResetSent :execrows
+-- Claims the right to send a password reset email, unless one was sent after throttle_before
+UPDATE users
+SET password_reset_sent_at = CURRENT_TIMESTAMP
+WHERE id = @id
+  AND (password_reset_sent_at IS NULL OR password_reset_sent_at <= @throttle_before::timestamptz);
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET hashed_password = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RehashUserPassword :exec
+-- Only replaces the hash it was computed from, so a password changed meanwhile is kept
+UPDATE users
+SET hashed_password = @hashed_password
+WHERE id = @id AND hashed_password = @previous_hashed_password;
+
+-- name: UpdateUserProfile :one
+-- Changing the email address marks it as unverified again
+UPDATE users
+SET username = @username,
+    email = @email,
+    avatar_url = @avatar_url,
+    is_active = CASE WHEN email = @email THEN is_active ELSE false END,
+    verification_sent_at = CASE WHEN email = @email THEN verification_sent_at ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: UpdateUserLastLogin :exec
+UPDATE users
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deletion_scheduled_at = @deletion_scheduled_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: CancelUserDeletion :exec
+UPDATE users
+SET deletion_scheduled_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deletion_scheduled_at IS NOT NULL;
+
+-- name: ListUsersDueForDeletion :many
+SELECT id FROM users
+WHERE deletion_scheduled_at <= CURRENT_TIMESTAMP
+ORDER BY deletion_scheduled_at;
+
+-- name: DeleteUser :exec
+-- Cascades to everything the user still owns
+DELETE FROM users
+WHERE id = $1;
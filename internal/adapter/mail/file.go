@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+)
+
+// FileMailerAdapter delivers emails into a local maildir instead of sending them, for development and tests
+type FileMailerAdapter struct {
+	dir  string
+	from string
+}
+
+func NewFileMailerAdapter(dir, from string) (*FileMailerAdapter, error) {
+	for _, subDir := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, subDir), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create maildir: %w", err)
+		}
+	}
+
+	return &FileMailerAdapter{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+// Send writes the message into tmp/ and then moves it into new/, so readers never see partial files
+func (m *FileMailerAdapter) Send(message domain.EmailMessage) error {
+	suffix, err := utils.GenerateSecureToken(8)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d.%s.lorecrafter", time.Now().UnixNano(), suffix)
+
+	tmpPath := filepath.Join(m.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, buildMessage(m.from, message), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, filepath.Join(m.dir, "new", name))
+}
@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"net/mail"
+
+	"github.com/knands42/lorecrafter/internal/config"
+	"github.com/knands42/lorecrafter/internal/interfaces"
+)
+
+// NewMailerAdapter creates the mailer selected by the MAIL_DRIVER configuration
+func NewMailerAdapter(cfg config.Config) (interfaces.Mailer, error) {
+	if _, err := envelopeAddress(cfg.MailFrom); err != nil {
+		return nil, err
+	}
+
+	switch cfg.MailDriver {
+	case "smtp", "":
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAIL_DRIVER is smtp")
+		}
+		return NewSMTPMailerAdapter(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	case "file":
+		// Nothing reaches the users, which is only wanted on a development machine
+		log.Printf("MAIL_DRIVER is file, emails are written to %s instead of being sent", cfg.MailDir)
+		return NewFileMailerAdapter(cfg.MailDir, cfg.MailFrom)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.MailDriver)
+	}
+}
+
+// envelopeAddress extracts the bare address from a "Name <address>" sender
+func envelopeAddress(from string) (string, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", fmt.Errorf("invalid MAIL_FROM address: %w", err)
+	}
+
+	return address.Address, nil
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+)
+
+// SMTPMailerAdapter sends emails through an SMTP relay
+type SMTPMailerAdapter struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailerAdapter(host, port, username, password, from string) *SMTPMailerAdapter {
+	return &SMTPMailerAdapter{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send delivers the message, authenticating only when credentials are configured
+func (m *SMTPMailerAdapter) Send(message domain.EmailMessage) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	envelopeFrom, err := envelopeAddress(m.from)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(
+		net.JoinHostPort(m.host, m.port),
+		auth,
+		envelopeFrom,
+		[]string{message.To},
+		buildMessage(m.from, message),
+	)
+}
@@ -0,0 +1,62 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrInvalidCiphertext = errors.New("the ciphertext is not in the correct format")
+
+// AESCipherAdapter encrypts secrets stored in the database with AES-256-GCM
+type AESCipherAdapter struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipherAdapter creates a cipher from a base64 encoded 32 bytes key
+func NewAESCipherAdapter(encodedKey string) (*AESCipherAdapter, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESCipherAdapter{aead: aead}, nil
+}
+
+// Encrypt encrypts a value and encodes it as base64 with the nonce prepended
+func (adapter *AESCipherAdapter) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, adapter.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := adapter.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt, failing if the value was tampered with
+func (adapter *AESCipherAdapter) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < adapter.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, encrypted := sealed[:adapter.aead.NonceSize()], sealed[adapter.aead.NonceSize():]
+	plaintext, err := adapter.aead.Open(nil, nonce, encrypted, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext), nil
+}
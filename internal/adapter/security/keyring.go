@@ -0,0 +1,138 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+)
+
+// keyIDBytes is how many bytes of the public key fingerprint make up a key ID
+const keyIDBytes = 8
+
+// tokenFooter is the unencrypted footer of the tokens, it tells which key signed them
+type tokenFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// SigningKey is an Ed25519 key pair encoded the way PASETO_PRIVATE_KEY and PASETO_PUBLIC_KEY expect it,
+// base64 encoded PEM blocks
+type SigningKey struct {
+	KeyID      string
+	PrivateKey string
+	PublicKey  string
+}
+
+// GenerateSigningKey creates a new Ed25519 key pair for signing tokens
+func GenerateSigningKey() (SigningKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	return encodeSigningKey(privateKey)
+}
+
+// LoadSigningKey decodes a private key and derives its public key and key ID
+func LoadSigningKey(privateKeyContent string) (SigningKey, error) {
+	privateKey, err := loadPrivateKey(privateKeyContent)
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	return encodeSigningKey(privateKey)
+}
+
+// PublicKeyID returns the key ID of an encoded public key
+func PublicKeyID(publicKeyContent string) (string, error) {
+	publicKey, err := loadPublicKey(publicKeyContent)
+	if err != nil {
+		return "", err
+	}
+
+	return keyID(publicKey), nil
+}
+
+// keyID derives a short identifier from the public key, so it never has to be configured
+func keyID(publicKey ed25519.PublicKey) string {
+	fingerprint := sha256.Sum256(publicKey)
+	return hex.EncodeToString(fingerprint[:keyIDBytes])
+}
+
+func encodeSigningKey(privateKey ed25519.PrivateKey) (SigningKey, error) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	return SigningKey{
+		KeyID:      keyID(publicKey),
+		PrivateKey: base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:  base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+	}, nil
+}
+
+func loadPrivateKey(key string) (ed25519.PrivateKey, error) {
+	decode, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(decode)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, ErrInvalidKey
+	}
+
+	return parsePrivateKey(block.Bytes)
+}
+
+func loadPublicKey(key string) (ed25519.PublicKey, error) {
+	decode, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(decode)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidKey
+	}
+
+	return parsePublicKey(block.Bytes)
+}
+
+func parsePrivateKey(der []byte) (ed25519.PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ed25519Key, ok := key.(ed25519.PrivateKey)
+	if !ok || len(ed25519Key) < ed25519.PrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519Key, nil
+}
+
+func parsePublicKey(der []byte) (ed25519.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ed25519Key, ok := key.(ed25519.PublicKey)
+	if !ok || len(ed25519Key) < ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519Key, nil
+}
@@ -0,0 +1,120 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrOIDCExchange   = errors.New("failed to exchange the authorization code")
+	ErrInvalidIDToken = errors.New("ID token is invalid")
+)
+
+// OIDCAdapter logs users in with an OpenID Connect provider through the authorization code flow with PKCE
+type OIDCAdapter struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+
+	// The provider metadata is discovered on first use, so the server starts even if the provider is down
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCAdapter creates a client of the provider at issuer. The openid scope is always requested.
+func NewOIDCAdapter(issuer, clientID, clientSecret, redirectURL string, scopes []string) *OIDCAdapter {
+	return &OIDCAdapter{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+	}
+}
+
+// AuthCodeURL returns the URL of the provider login page.
+// The state and nonce are echoed back and the code verifier must be given again to Exchange.
+func (adapter *OIDCAdapter) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	config, _, err := adapter.config(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier), oidc.Nonce(nonce)), nil
+}
+
+// Exchange trades an authorization code for the identity of the user, checking the ID token was issued
+// by the provider for this client and this login request
+func (adapter *OIDCAdapter) Exchange(ctx context.Context, code, codeVerifier, nonce string) (domain.OIDCIdentity, error) {
+	config, provider, err := adapter.config(ctx)
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: %v", ErrOIDCExchange, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: no ID token in the token response", ErrInvalidIDToken)
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: adapter.clientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if idToken.Nonce != nonce {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: nonce doesn't match", ErrInvalidIDToken)
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	return domain.OIDCIdentity{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+	}, nil
+}
+
+// config discovers the provider endpoints, retrying on the next call if the discovery fails
+func (adapter *OIDCAdapter) config(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+
+	if adapter.provider == nil {
+		provider, err := oidc.NewProvider(ctx, adapter.issuer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to discover OIDC provider %s: %w", adapter.issuer, err)
+		}
+		adapter.provider = provider
+	}
+
+	return &oauth2.Config{
+		ClientID:     adapter.clientID,
+		ClientSecret: adapter.clientSecret,
+		RedirectURL:  adapter.redirectURL,
+		Endpoint:     adapter.provider.Endpoint(),
+		Scopes:       append([]string{oidc.ScopeOpenID}, adapter.scopes...),
+	}, adapter.provider, nil
+}
@@ -0,0 +1,58 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// v4PublicHeader starts every v4.public token, see https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Version4.md
+const v4PublicHeader = "v4.public."
+
+// signV4 signs the message into a v4.public token. The implicit assertion is signed but not part of the
+// token, the same value has to be given to verifyV4.
+func signV4(privateKey ed25519.PrivateKey, message, footer, implicit []byte) (string, error) {
+	secretKey, err := paseto.NewV4AsymmetricSecretKeyFromEd25519(privateKey)
+	if err != nil {
+		return "", err
+	}
+	token, err := paseto.NewTokenFromClaimsJSON(message, footer)
+	if err != nil {
+		return "", err
+	}
+
+	return token.V4Sign(secretKey, implicit), nil
+}
+
+// verifyV4 checks the signature of a v4.public token and returns its message.
+// Expiry is not checked here, the caller checks it on the decoded payload.
+func verifyV4(token string, publicKey ed25519.PublicKey, implicit []byte) ([]byte, error) {
+	key, err := paseto.NewV4AsymmetricPublicKeyFromEd25519(publicKey)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	parsed, err := paseto.NewParserWithoutExpiryCheck().ParseV4Public(key, token, implicit)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return parsed.ClaimsJSON(), nil
+}
+
+// tokenFooterBytes returns the unverified footer of a v2 or v4 token, nil when it has none
+func tokenFooterBytes(token string) []byte {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return nil
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil
+	}
+
+	return footer
+}
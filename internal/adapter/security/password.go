@@ -25,18 +25,20 @@ type Argon2Adapter struct {
 	KeyLength   uint32
 }
 
-func NewArgon2Adapter() *Argon2Adapter {
+// NewArgon2Adapter creates an adapter hashing with the given cost, memory is in KiB.
+// Hashes made with other parameters still verify and are reported as needing a rehash.
+func NewArgon2Adapter(memory, iterations uint32, parallelism uint8) *Argon2Adapter {
 	return &Argon2Adapter{
-		Memory:      64 * 1024, // 64MB
-		Iterations:  3,
-		Parallelism: 2,
+		Memory:      memory,
+		Iterations:  iterations,
+		Parallelism: parallelism,
 		SaltLength:  16,
 		KeyLength:   32,
 	}
 }
 
-// HashPassword hashes a password using Argon2id
-// TODO: dont store hash params in the db
+// HashPassword hashes a password using Argon2id.
+// The parameters are stored in the encoded hash so it can be verified after they change.
 func (arg *Argon2Adapter) HashPassword(password string) (string, error) {
 	// Generate a random salt
 	salt := make([]byte, arg.SaltLength)
@@ -71,12 +73,13 @@ func (arg *Argon2Adapter) HashPassword(password string) (string, error) {
 	return encodedHash, nil
 }
 
-// VerifyPassword checks if a password matches a hash
-func (arg *Argon2Adapter) VerifyPassword(password, encodedHash string) (bool, error) {
+// VerifyPassword checks if a password matches a hash.
+// needsRehash is true when the hash was made with other parameters than the current ones.
+func (arg *Argon2Adapter) VerifyPassword(password, encodedHash string) (match bool, needsRehash bool, err error) {
 	// Extract the parameters, salt, and hash from the encoded hash
 	params, salt, hash, err := arg.decodeHash(encodedHash)
 	if err != nil {
-		return false, err
+		return false, false, err
 	}
 
 	// Hash the password with the same parameters and salt
@@ -91,10 +94,10 @@ func (arg *Argon2Adapter) VerifyPassword(password, encodedHash string) (bool, er
 
 	// Compare the hashes in constant time to prevent timing attacks
 	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
-		return true, nil
+		return true, *params != *arg, nil
 	}
 
-	return false, nil
+	return false, false, nil
 }
 
 // decodeHash decodes an Argon2id hash string into its parameters, salt, and hash
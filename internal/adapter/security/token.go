@@ -2,13 +2,12 @@ package security
 
 import (
 	"crypto/ed25519"
-	"crypto/x509"
-	"encoding/base64"
-	"encoding/pem"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/knands42/lorecrafter/internal/utils"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,59 +21,170 @@ var (
 	ErrInvalidKey   = errors.New("invalid key format")
 )
 
+// PASETO versions tokens can be issued with, tokens of both versions are always verified
+const (
+	TokenVersionV2 = "v2"
+	TokenVersionV4 = "v4"
+)
+
+// TokenMakerAdapter signs tokens with the active key of a keyring and verifies them with any of its keys.
+// The key ID in the token footer picks the verification key, so retired keys keep verifying the tokens
+// they signed until those expire.
+// Tokens are issued as v2.public or v4.public, v4 tokens are also bound to the audience and their purpose
+// through an implicit assertion.
 type TokenMakerAdapter struct {
-	paseto     *paseto.V2
-	privateKey ed25519.PrivateKey
-	publicKey  ed25519.PublicKey
+	paseto       *paseto.V2
+	version      string
+	audience     string
+	signingKeyID string
+	privateKey   ed25519.PrivateKey
+	publicKeys   map[string]ed25519.PublicKey
+}
+
+// implicitAssertion is signed along with v4 tokens without being part of them
+type implicitAssertion struct {
+	Audience string `json:"aud"`
+	Purpose  string `json:"purpose"`
 }
 
-func NewTokenMakerAdapter(privateKeyContent, publicKeyContent string) (*TokenMakerAdapter, error) {
-	maker := &TokenMakerAdapter{}
+// NewTokenMakerAdapter creates a token maker signing with the given key pair and PASETO version.
+// verificationKeys are additional public keys still accepted, e.g. the previous key after a rotation
+// or the next one before it is promoted.
+func NewTokenMakerAdapter(privateKeyContent, publicKeyContent string, verificationKeys []string, version, audience string) (*TokenMakerAdapter, error) {
+	if version != TokenVersionV2 && version != TokenVersionV4 {
+		return nil, fmt.Errorf("unsupported PASETO version %q, must be %s or %s", version, TokenVersionV2, TokenVersionV4)
+	}
+
+	maker := &TokenMakerAdapter{
+		version:    version,
+		audience:   audience,
+		publicKeys: make(map[string]ed25519.PublicKey),
+	}
 
-	privateKey, err := maker.loadPrivateKey(privateKeyContent)
+	privateKey, err := loadPrivateKey(privateKeyContent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load private key: %w", err)
 	}
 
-	publicKey, err := maker.loadPublicKey(publicKeyContent)
+	publicKey, err := loadPublicKey(publicKeyContent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load public key: %w", err)
 	}
+	if !publicKey.Equal(privateKey.Public()) {
+		return nil, fmt.Errorf("public key doesn't match the private key: %w", ErrInvalidKey)
+	}
+
+	for i, verificationKey := range verificationKeys {
+		key, err := loadPublicKey(verificationKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load verification key %d: %w", i+1, err)
+		}
+		maker.publicKeys[keyID(key)] = key
+	}
 
 	maker.paseto = paseto.NewV2()
+	maker.signingKeyID = keyID(publicKey)
 	maker.privateKey = privateKey
-	maker.publicKey = publicKey
+	maker.publicKeys[maker.signingKeyID] = publicKey
 
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific user and duration
-func (maker *TokenMakerAdapter) CreateToken(user sqlc.User, duration time.Duration) (string, time.Time, error) {
+// CreateToken creates a new access token of a session with the given scopes for a specific user and duration
+func (maker *TokenMakerAdapter) CreateToken(user sqlc.User, sessionID uuid.UUID, scopes []string, duration time.Duration) (string, *domain.TokenPayload, error) {
+	return maker.createToken(user, domain.TokenPurposeAccess, sessionID.String(), scopes, duration)
+}
+
+// CreatePurposeToken creates a new token that can only be verified for the given purpose
+func (maker *TokenMakerAdapter) CreatePurposeToken(user sqlc.User, purpose string, scopes []string, duration time.Duration) (string, *domain.TokenPayload, error) {
+	return maker.createToken(user, purpose, "", scopes, duration)
+}
+
+func (maker *TokenMakerAdapter) createToken(user sqlc.User, purpose, sessionID string, scopes []string, duration time.Duration) (string, *domain.TokenPayload, error) {
 	convertedUUID, err := utils.FromPGTypeUUID(user.ID)
 	if err != nil {
-		return "", time.Time{}, utils.ErrInvalidUUID
+		return "", nil, utils.ErrInvalidUUID
+	}
+	tokenID, err := uuid.NewV7()
+	if err != nil {
+		return "", nil, utils.UUIDCreationFailed
 	}
-	payload := domain.TokenPayload{
+	payload := &domain.TokenPayload{
+		ID:        tokenID.String(),
+		Purpose:   purpose,
 		UserID:    convertedUUID.String(),
 		Username:  user.Username,
+		Email:     user.Email,
+		SessionID: sessionID,
+		Scopes:    scopes,
 		IssuedAt:  time.Now(),
 		ExpiresAt: time.Now().Add(duration),
 	}
 
-	token, err := maker.paseto.Sign(maker.privateKey, payload, nil)
+	footer := tokenFooter{KeyID: maker.signingKeyID}
+	if maker.version == TokenVersionV2 {
+		token, err := maker.paseto.Sign(maker.privateKey, payload, footer)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return token, payload, nil
+	}
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+	encodedFooter, err := json.Marshal(footer)
+	if err != nil {
+		return "", nil, err
+	}
+	implicit, err := maker.implicitAssertion(purpose)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := signV4(maker.privateKey, message, encodedFooter, implicit)
 	if err != nil {
-		return "", time.Time{}, err
+		return "", nil, err
 	}
 
-	return token, payload.ExpiresAt, nil
+	return token, payload, nil
 }
 
-// VerifyToken checks if the token is valid and returns the payload
+// VerifyToken checks if the access token is valid and returns the payload
 func (maker *TokenMakerAdapter) VerifyToken(token string) (*domain.TokenPayload, error) {
-	payload := &domain.TokenPayload{}
+	return maker.VerifyPurposeToken(token, domain.TokenPurposeAccess)
+}
 
-	err := maker.paseto.Verify(token, maker.publicKey, payload, nil)
+// VerifyPurposeToken checks if the token is valid for the given purpose and returns the payload
+func (maker *TokenMakerAdapter) VerifyPurposeToken(token, purpose string) (*domain.TokenPayload, error) {
+	publicKey, err := maker.verificationKey(token)
 	if err != nil {
+		return nil, err
+	}
+
+	payload := &domain.TokenPayload{}
+	switch {
+	case strings.HasPrefix(token, v4PublicHeader):
+		implicit, err := maker.implicitAssertion(purpose)
+		if err != nil {
+			return nil, err
+		}
+		message, err := verifyV4(token, publicKey, implicit)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(message, payload); err != nil {
+			return nil, ErrInvalidToken
+		}
+	default:
+		if err := maker.paseto.Verify(token, publicKey, payload, nil); err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	if payload.Purpose != purpose {
 		return nil, ErrInvalidToken
 	}
 
@@ -94,68 +204,35 @@ func (maker *TokenMakerAdapter) ParseUserID(payload *domain.TokenPayload) (uuid.
 	return userID, nil
 }
 
-func (maker *TokenMakerAdapter) loadPrivateKey(key string) (ed25519.PrivateKey, error) {
-	decode, err := base64.StdEncoding.DecodeString(key)
+// ParseTokenID parses the token ID from the token payload
+func (maker *TokenMakerAdapter) ParseTokenID(payload *domain.TokenPayload) (uuid.UUID, error) {
+	tokenID, err := uuid.Parse(payload.ID)
 	if err != nil {
-		return nil, err
-	}
-
-	block, _ := pem.Decode(decode)
-	if block == nil || block.Type != "PRIVATE KEY" {
-		return nil, ErrInvalidKey
-	}
-
-	privateKey, err := maker.parsePrivateKey(block.Bytes)
-	if err != nil {
-		return nil, err
+		return uuid.Nil, fmt.Errorf("invalid token ID: %w", err)
 	}
-
-	return privateKey, nil
+	return tokenID, nil
 }
 
-func (maker *TokenMakerAdapter) loadPublicKey(key string) (ed25519.PublicKey, error) {
-	decode, err := base64.StdEncoding.DecodeString(key)
-	if err != nil {
-		return nil, err
-	}
-
-	block, _ := pem.Decode(decode)
-	if block == nil || block.Type != "PUBLIC KEY" {
-		return nil, ErrInvalidKey
-	}
-
-	publicKey, err := maker.parsePublicKey(block.Bytes)
-	if err != nil {
-		return nil, err
-	}
-
-	return publicKey, nil
+// implicitAssertion binds a v4 token to the audience and the purpose it was issued for
+func (maker *TokenMakerAdapter) implicitAssertion(purpose string) ([]byte, error) {
+	return json.Marshal(implicitAssertion{
+		Audience: maker.audience,
+		Purpose:  purpose,
+	})
 }
 
-func (maker *TokenMakerAdapter) parsePrivateKey(der []byte) (ed25519.PrivateKey, error) {
-	key, err := x509.ParsePKCS8PrivateKey(der)
-	if err != nil {
-		return nil, err
-	}
-
-	ed25519Key := key.(ed25519.PrivateKey)
-	if len(ed25519Key) < ed25519.PrivateKeySize {
-		return nil, ErrInvalidKey
+// verificationKey picks the public key matching the key ID in the token footer.
+// Tokens signed before key IDs were introduced have no footer and are checked against the signing key.
+func (maker *TokenMakerAdapter) verificationKey(token string) (ed25519.PublicKey, error) {
+	var footer tokenFooter
+	if err := json.Unmarshal(tokenFooterBytes(token), &footer); err != nil || footer.KeyID == "" {
+		return maker.publicKeys[maker.signingKeyID], nil
 	}
 
-	return ed25519Key, nil
-}
-
-func (maker *TokenMakerAdapter) parsePublicKey(der []byte) (ed25519.PublicKey, error) {
-	key, err := x509.ParsePKIXPublicKey(der)
-	if err != nil {
-		return nil, err
-	}
-
-	ed25519Key := key.(ed25519.PublicKey)
-	if len(ed25519Key) < ed25519.PublicKeySize {
-		return nil, ErrInvalidKey
+	publicKey, ok := maker.publicKeys[footer.KeyID]
+	if !ok {
+		return nil, ErrInvalidToken
 	}
 
-	return ed25519Key, nil
+	return publicKey, nil
 }
@@ -0,0 +1,110 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPAdapter implements RFC 6238 time-based one-time passwords with the defaults authenticator apps expect
+type TOTPAdapter struct {
+	Issuer      string
+	SecretBytes int
+	Digits      int
+	Period      time.Duration
+	// Number of time steps before and after the current one still accepted, to absorb clock drift
+	Skew int64
+}
+
+func NewTOTPAdapter(issuer string) *TOTPAdapter {
+	return &TOTPAdapter{
+		Issuer:      issuer,
+		SecretBytes: 20,
+		Digits:      6,
+		Period:      30 * time.Second,
+		Skew:        1,
+	}
+}
+
+// GenerateSecret returns a new random base32 encoded secret
+func (adapter *TOTPAdapter) GenerateSecret() (string, error) {
+	secret := make([]byte, adapter.SecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI builds the otpauth:// URI authenticator apps read from a QR code
+func (adapter *TOTPAdapter) ProvisioningURI(secret, accountName string) string {
+	label := url.PathEscape(adapter.Issuer + ":" + accountName)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", adapter.Issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(adapter.Digits))
+	query.Set("period", fmt.Sprint(int64(adapter.Period.Seconds())))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// GenerateCode returns the code of the time step containing at
+func (adapter *TOTPAdapter) GenerateCode(secret string, at time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	return adapter.code(key, adapter.step(at)), nil
+}
+
+// Validate checks a code against the time steps around at and returns the step it belongs to
+func (adapter *TOTPAdapter) Validate(secret, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != adapter.Digits {
+		return 0, false
+	}
+
+	current := adapter.step(at)
+	for step := current - adapter.Skew; step <= current+adapter.Skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(adapter.code(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func (adapter *TOTPAdapter) step(at time.Time) int64 {
+	return at.Unix() / int64(adapter.Period.Seconds())
+}
+
+// code computes the HOTP value of a counter as described in RFC 4226
+func (adapter *TOTPAdapter) code(key []byte, counter int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < adapter.Digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", adapter.Digits, value%modulo)
+}
@@ -0,0 +1,189 @@
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/knands42/lorecrafter/internal/domain"
+)
+
+var (
+	ErrInvalidPasskeyCredential = errors.New("passkey credential is invalid")
+	ErrInvalidPasskeySession    = errors.New("passkey session is invalid")
+)
+
+// WebAuthnAdapter runs the WebAuthn registration and login ceremonies of passkeys.
+// Passkeys are discoverable credentials with user verification, so they log users in without a username or password.
+type WebAuthnAdapter struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+// NewWebAuthnAdapter creates the relying party of the passkeys.
+// rpID is the domain the passkeys are bound to and rpOrigins the origins of the web app allowed to use them.
+func NewWebAuthnAdapter(rpID, rpDisplayName string, rpOrigins []string) (*WebAuthnAdapter, error) {
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpDisplayName,
+		RPOrigins:     rpOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnAdapter{webAuthn: webAuthn}, nil
+}
+
+// BeginRegistration returns the options of navigator.credentials.create() and the session to keep until it completes.
+// Authenticators already registered by the user are excluded.
+func (adapter *WebAuthnAdapter) BeginRegistration(user domain.PasskeyUser) ([]byte, []byte, error) {
+	webAuthnUser := newWebAuthnUser(user)
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(webAuthnUser.credentials))
+	for _, credential := range webAuthnUser.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, session, err := adapter.webAuthn.BeginRegistration(webAuthnUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return marshalCeremony(creation, session)
+}
+
+// FinishRegistration checks the credential created by the authenticator against the session of the registration
+func (adapter *WebAuthnAdapter) FinishRegistration(user domain.PasskeyUser, sessionData, response []byte) (domain.PasskeyCredential, error) {
+	var session webauthn.SessionData
+	if err := json.Unmarshal(sessionData, &session); err != nil {
+		return domain.PasskeyCredential{}, fmt.Errorf("%w: %v", ErrInvalidPasskeySession, err)
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return domain.PasskeyCredential{}, fmt.Errorf("%w: %v", ErrInvalidPasskeyCredential, err)
+	}
+
+	credential, err := adapter.webAuthn.CreateCredential(newWebAuthnUser(user), session, parsed)
+	if err != nil {
+		return domain.PasskeyCredential{}, fmt.Errorf("%w: %v", ErrInvalidPasskeyCredential, err)
+	}
+
+	return toPasskeyCredential(*credential), nil
+}
+
+// BeginLogin returns the options of navigator.credentials.get() and the session to keep until it completes.
+// The user isn't known yet, the authenticator picks one of its passkeys for this relying party.
+func (adapter *WebAuthnAdapter) BeginLogin() ([]byte, []byte, error) {
+	assertion, session, err := adapter.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return marshalCeremony(assertion, session)
+}
+
+// FinishLogin checks the assertion signed by the authenticator against the session of the login.
+// findUser looks up the user by the handle the authenticator stored with the passkey.
+func (adapter *WebAuthnAdapter) FinishLogin(sessionData, response []byte, findUser func(handle []byte) (domain.PasskeyUser, error)) (domain.PasskeyCredential, error) {
+	var session webauthn.SessionData
+	if err := json.Unmarshal(sessionData, &session); err != nil {
+		return domain.PasskeyCredential{}, fmt.Errorf("%w: %v", ErrInvalidPasskeySession, err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return domain.PasskeyCredential{}, fmt.Errorf("%w: %v", ErrInvalidPasskeyCredential, err)
+	}
+
+	credential, err := adapter.webAuthn.ValidateDiscoverableLogin(func(_, handle []byte) (webauthn.User, error) {
+		user, err := findUser(handle)
+		if err != nil {
+			return nil, err
+		}
+		return newWebAuthnUser(user), nil
+	}, session, parsed)
+	if err != nil {
+		return domain.PasskeyCredential{}, fmt.Errorf("%w: %v", ErrInvalidPasskeyCredential, err)
+	}
+
+	return toPasskeyCredential(*credential), nil
+}
+
+// webAuthnUser exposes a user to the WebAuthn library
+type webAuthnUser struct {
+	user        domain.PasskeyUser
+	credentials []webauthn.Credential
+}
+
+func newWebAuthnUser(user domain.PasskeyUser) *webAuthnUser {
+	credentials := make([]webauthn.Credential, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+		for _, transport := range credential.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+
+	return &webAuthnUser{user: user, credentials: credentials}
+}
+
+func (user *webAuthnUser) WebAuthnID() []byte                         { return user.user.Handle }
+func (user *webAuthnUser) WebAuthnName() string                       { return user.user.Name }
+func (user *webAuthnUser) WebAuthnDisplayName() string                { return user.user.DisplayName }
+func (user *webAuthnUser) WebAuthnCredentials() []webauthn.Credential { return user.credentials }
+func (user *webAuthnUser) WebAuthnIcon() string                       { return "" }
+
+func toPasskeyCredential(credential webauthn.Credential) domain.PasskeyCredential {
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return domain.PasskeyCredential{
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		CloneWarning:    credential.Authenticator.CloneWarning,
+	}
+}
+
+func marshalCeremony(options any, session *webauthn.SessionData) ([]byte, []byte, error) {
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	encodedSession, err := json.Marshal(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encodedOptions, encodedSession, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -16,12 +17,100 @@ type Config struct {
 	PrivateKey  string        `mapstructure:"PASETO_PRIVATE_KEY"`
 	PublicKey   string        `mapstructure:"PASETO_PUBLIC_KEY"`
 
+	// Comma separated public keys accepted besides PASETO_PUBLIC_KEY while signing keys are rotated
+	VerificationKeys []string `mapstructure:"PASETO_VERIFICATION_KEYS"`
+
+	// PASETO version new tokens are issued with, "v2" or "v4". Tokens of both versions are verified so
+	// switching doesn't log anyone out. v4 tokens are bound to TokenAudience.
+	TokenVersion  string `mapstructure:"PASETO_VERSION"`
+	TokenAudience string `mapstructure:"PASETO_AUDIENCE"`
+
+	// Argon2id cost of new password hashes, memory in KiB. Raising them upgrades the stored hashes as users log in.
+	Argon2Memory      uint32 `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations  uint32 `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism uint8  `mapstructure:"ARGON2_PARALLELISM"`
+
+	// Lifetime of the refresh tokens exchanged for new short-lived access tokens
+	RefreshTokenExpiry time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRY"`
+
+	// Email verification, unverified users can log in but can't create or join campaigns when required
+	EmailVerificationRequired  bool          `mapstructure:"EMAIL_VERIFICATION_REQUIRED"`
+	VerificationTokenExpiry    time.Duration `mapstructure:"VERIFICATION_TOKEN_EXPIRY"`
+	VerificationResendInterval time.Duration `mapstructure:"VERIFICATION_RESEND_INTERVAL"`
+
+	// Lifetime of the single-use password reset links and minimum time between two reset emails of an account
+	PasswordResetExpiry         time.Duration `mapstructure:"PASSWORD_RESET_EXPIRY"`
+	PasswordResetResendInterval time.Duration `mapstructure:"PASSWORD_RESET_RESEND_INTERVAL"`
+
+	// Two-factor authentication, TOTP secrets are encrypted with MFAEncryptionKey (base64 encoded 32 bytes key).
+	// Users can't enable it while the key is empty.
+	MFAIssuer             string        `mapstructure:"MFA_ISSUER"`
+	MFAEncryptionKey      string        `mapstructure:"MFA_ENCRYPTION_KEY"`
+	MFAPendingTokenExpiry time.Duration `mapstructure:"MFA_PENDING_TOKEN_EXPIRY"`
+
+	// Failed login lockout, see usecases.LoginThrottleSettings
+	LoginMaxAccountAttempts int           `mapstructure:"LOGIN_MAX_ACCOUNT_ATTEMPTS"`
+	LoginMaxIPAttempts      int           `mapstructure:"LOGIN_MAX_IP_ATTEMPTS"`
+	LoginBaseLockout        time.Duration `mapstructure:"LOGIN_BASE_LOCKOUT"`
+	LoginMaxLockout         time.Duration `mapstructure:"LOGIN_MAX_LOCKOUT"`
+	LoginFailureWindow      time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+
+	// OpenID Connect login providers, each name in OIDC_PROVIDERS is configured by OIDC_<NAME>_ISSUER,
+	// OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET and OIDC_<NAME>_SCOPES.
+	// Providers redirect back to OIDCCallbackBaseURL/api/auth/oidc/<name>/callback.
+	OIDCProviderNames   []string             `mapstructure:"OIDC_PROVIDERS"`
+	OIDCProviders       []OIDCProviderConfig `mapstructure:"-"`
+	OIDCCallbackBaseURL string               `mapstructure:"OIDC_CALLBACK_BASE_URL"`
+	OIDCStateExpiry     time.Duration        `mapstructure:"OIDC_STATE_EXPIRY"`
+
+	// Passkeys are bound to the WebAuthn relying party ID, the domain of the web app, and usable from its origins
+	WebAuthnRPID          string        `mapstructure:"WEBAUTHN_RP_ID"`
+	WebAuthnRPDisplayName string        `mapstructure:"WEBAUTHN_RP_DISPLAY_NAME"`
+	WebAuthnRPOrigins     []string      `mapstructure:"WEBAUTHN_RP_ORIGINS"`
+	WebAuthnSessionExpiry time.Duration `mapstructure:"WEBAUTHN_SESSION_EXPIRY"`
+
+	// Accounts asked to be deleted are kept for the grace period, their deletion is checked every purge interval
+	AccountDeletionGracePeriod time.Duration `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD"`
+	AccountPurgeInterval       time.Duration `mapstructure:"ACCOUNT_PURGE_INTERVAL"`
+
+	// Only enable behind a reverse proxy that sets X-Forwarded-For, clients could spoof their address otherwise
+	TrustProxyHeaders bool `mapstructure:"TRUST_PROXY_HEADERS"`
+
 	// API Keys
 	GoogleAPIKey string `mapstructure:"GOOGLE_API_KEY"`
 	OpenAIAPIKey string `mapstructure:"OPENAI_API_KEY"`
 
 	// Database configuration
 	PostgresURL string `mapstructure:"POSTGRES_URL"`
+
+	// Public URL of the web app, used to build links sent by email
+	AppBaseURL string `mapstructure:"APP_BASE_URL"`
+
+	// Mail configuration, MailDriver is either "smtp" or "file"
+	MailDriver   string `mapstructure:"MAIL_DRIVER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailDir      string `mapstructure:"MAIL_DIR"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     string `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+
+	// Campaign invitations
+	InvitationExpiry time.Duration `mapstructure:"INVITATION_EXPIRY"`
+}
+
+// OIDCProviderConfig holds the client registration of an OpenID Connect provider
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// CallbackURL is the redirect URL registered at the provider
+func (provider OIDCProviderConfig) CallbackURL(baseURL string) string {
+	return fmt.Sprintf("%s/api/auth/oidc/%s/callback", strings.TrimRight(baseURL, "/"), provider.Name)
 }
 
 // LoadConfig loads the configuration from .env file and environment variables
@@ -33,6 +122,41 @@ func LoadConfig(path string) (config Config, err error) {
 
 	v.AutomaticEnv()
 
+	v.SetDefault("TOKEN_EXPIRY", "15m")
+	v.SetDefault("REFRESH_TOKEN_EXPIRY", "720h")
+	v.SetDefault("ARGON2_MEMORY", 64*1024)
+	v.SetDefault("ARGON2_ITERATIONS", 3)
+	v.SetDefault("ARGON2_PARALLELISM", 2)
+	v.SetDefault("PASETO_VERSION", "v2")
+	v.SetDefault("PASETO_AUDIENCE", "lorecrafter-api")
+	v.SetDefault("EMAIL_VERIFICATION_REQUIRED", true)
+	v.SetDefault("VERIFICATION_TOKEN_EXPIRY", "24h")
+	v.SetDefault("VERIFICATION_RESEND_INTERVAL", "1m")
+	v.SetDefault("PASSWORD_RESET_EXPIRY", "1h")
+	v.SetDefault("PASSWORD_RESET_RESEND_INTERVAL", "1m")
+	v.SetDefault("MFA_ISSUER", "LoreCrafter")
+	v.SetDefault("MFA_PENDING_TOKEN_EXPIRY", "5m")
+	v.SetDefault("LOGIN_MAX_ACCOUNT_ATTEMPTS", 5)
+	v.SetDefault("LOGIN_MAX_IP_ATTEMPTS", 20)
+	v.SetDefault("LOGIN_BASE_LOCKOUT", "1m")
+	v.SetDefault("LOGIN_MAX_LOCKOUT", "1h")
+	v.SetDefault("LOGIN_FAILURE_WINDOW", "15m")
+	v.SetDefault("TRUST_PROXY_HEADERS", false)
+	v.SetDefault("APP_BASE_URL", "http://localhost:8000")
+	v.SetDefault("OIDC_CALLBACK_BASE_URL", "http://localhost:8000")
+	v.SetDefault("OIDC_STATE_EXPIRY", "10m")
+	v.SetDefault("WEBAUTHN_RP_ID", "localhost")
+	v.SetDefault("WEBAUTHN_RP_DISPLAY_NAME", "LoreCrafter")
+	v.SetDefault("WEBAUTHN_RP_ORIGINS", "http://localhost:8000")
+	v.SetDefault("WEBAUTHN_SESSION_EXPIRY", "5m")
+	v.SetDefault("ACCOUNT_DELETION_GRACE_PERIOD", "720h")
+	v.SetDefault("ACCOUNT_PURGE_INTERVAL", "1h")
+	v.SetDefault("MAIL_DRIVER", "smtp")
+	v.SetDefault("MAIL_FROM", "LoreCrafter <no-reply@lorecrafter.local>")
+	v.SetDefault("MAIL_DIR", "tmp/mail")
+	v.SetDefault("SMTP_PORT", "587")
+	v.SetDefault("INVITATION_EXPIRY", "168h")
+
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			log.Printf("error reading config file: %v", err)
@@ -43,11 +167,50 @@ func LoadConfig(path string) (config Config, err error) {
 		"ENVIRONMENT",
 		"SERVER_PORT",
 		"TOKEN_EXPIRY",
+		"REFRESH_TOKEN_EXPIRY",
+		"ARGON2_MEMORY",
+		"ARGON2_ITERATIONS",
+		"ARGON2_PARALLELISM",
+		"EMAIL_VERIFICATION_REQUIRED",
+		"VERIFICATION_TOKEN_EXPIRY",
+		"VERIFICATION_RESEND_INTERVAL",
+		"PASSWORD_RESET_EXPIRY",
+		"PASSWORD_RESET_RESEND_INTERVAL",
+		"MFA_ISSUER",
+		"MFA_ENCRYPTION_KEY",
+		"MFA_PENDING_TOKEN_EXPIRY",
+		"LOGIN_MAX_ACCOUNT_ATTEMPTS",
+		"LOGIN_MAX_IP_ATTEMPTS",
+		"LOGIN_BASE_LOCKOUT",
+		"LOGIN_MAX_LOCKOUT",
+		"LOGIN_FAILURE_WINDOW",
+		"TRUST_PROXY_HEADERS",
+		"OIDC_PROVIDERS",
+		"OIDC_CALLBACK_BASE_URL",
+		"OIDC_STATE_EXPIRY",
+		"WEBAUTHN_RP_ID",
+		"WEBAUTHN_RP_DISPLAY_NAME",
+		"WEBAUTHN_RP_ORIGINS",
+		"WEBAUTHN_SESSION_EXPIRY",
+		"ACCOUNT_DELETION_GRACE_PERIOD",
+		"ACCOUNT_PURGE_INTERVAL",
 		"PASETO_PRIVATE_KEY",
 		"PASETO_PUBLIC_KEY",
+		"PASETO_VERIFICATION_KEYS",
+		"PASETO_VERSION",
+		"PASETO_AUDIENCE",
 		"GOOGLE_API_KEY",
 		"OPENAI_API_KEY",
 		"POSTGRES_URL",
+		"APP_BASE_URL",
+		"MAIL_DRIVER",
+		"MAIL_FROM",
+		"MAIL_DIR",
+		"SMTP_HOST",
+		"SMTP_PORT",
+		"SMTP_USERNAME",
+		"SMTP_PASSWORD",
+		"INVITATION_EXPIRY",
 	}
 	for _, key := range keys {
 		err := v.BindEnv(key)
@@ -62,5 +225,30 @@ func LoadConfig(path string) (config Config, err error) {
 		return config, fmt.Errorf("unable to decode into config struct: %w", err)
 	}
 
+	// The settings of each provider are named after it, so they can only be read once the names are known
+	for _, name := range config.OIDCProviderNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       v.GetString(prefix + "ISSUER"),
+			ClientID:     v.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: v.GetString(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(strings.ReplaceAll(v.GetString(prefix+"SCOPES"), ",", " ")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return config, fmt.Errorf("OIDC provider %s needs %sISSUER and %sCLIENT_ID", name, prefix, prefix)
+		}
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = []string{"email", "profile"}
+		}
+
+		config.OIDCProviders = append(config.OIDCProviders, provider)
+	}
+
 	return config, nil
 }
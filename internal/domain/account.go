@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// DeleteAccountInput represents a request to delete the account of the logged user, confirmed with their password
+type DeleteAccountInput struct {
+	Password string `json:"password"`
+}
+
+func (input *DeleteAccountInput) Validate() error {
+	if input.Password == "" {
+		return &utils.ValidationError{Errors: []string{"password is required"}}
+	}
+
+	return nil
+}
+
+// AccountDeletion tells when a deleted account is gone for good, logging in before then cancels the deletion
+type AccountDeletion struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// AccountExport holds everything a user owns, invitation tokens are left out
+type AccountExport struct {
+	ExportedAt          time.Time                                `json:"exported_at"`
+	Profile             UserProfile                              `json:"profile"`
+	Campaigns           []sqlc.Campaign                          `json:"campaigns"`
+	Characters          []sqlc.Character                         `json:"characters"`
+	TimelineEvents      []sqlc.TimelineEvent                     `json:"timeline_events"`
+	InvitationsSent     []sqlc.ListInvitationsSentByUserRow      `json:"invitations_sent"`
+	InvitationsReceived []sqlc.ListInvitationsReceivedByEmailRow `json:"invitations_received"`
+}
+
+// Files splits the export into one JSON document per section, as laid out in the ZIP archive
+func (export AccountExport) Files() []ExportFile {
+	return []ExportFile{
+		{Name: "profile.json", Content: export.Profile},
+		{Name: "campaigns.json", Content: export.Campaigns},
+		{Name: "characters.json", Content: export.Characters},
+		{Name: "timeline_events.json", Content: export.TimelineEvents},
+		{Name: "invitations_sent.json", Content: export.InvitationsSent},
+		{Name: "invitations_received.json", Content: export.InvitationsReceived},
+	}
+}
+
+// ExportFile is a section of an account export
+type ExportFile struct {
+	Name    string
+	Content any
+}
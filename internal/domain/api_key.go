@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+const (
+	// APIKeyPrefix starts every API key so they can be told apart from PASETO tokens
+	APIKeyPrefix = "lc_"
+	// TokenPurposeAPIKey marks the payloads of requests authenticated with an API key
+	TokenPurposeAPIKey = "api_key"
+)
+
+// APIKeyScopes are the scopes an API key can be granted, API keys can never manage the account itself
+var APIKeyScopes = []string{ScopeRead, ScopeCampaignsWrite, ScopeCharactersWrite}
+
+// APIKeyInput represents the details of a new API key
+type APIKeyInput struct {
+	Name      string     `json:"name" example:"Discord bot"`
+	Scopes    []string   `json:"scopes" example:"read,characters:write"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-01-01T00:00:00Z"`
+}
+
+func (input *APIKeyInput) Validate() error {
+	var validationErrors []string
+
+	if strings.TrimSpace(input.Name) == "" {
+		validationErrors = append(validationErrors, "name is required")
+	}
+
+	if len(input.Name) > 100 {
+		validationErrors = append(validationErrors, "name must be at most 100 characters")
+	}
+
+	if len(input.Scopes) == 0 {
+		validationErrors = append(validationErrors, "at least one scope is required")
+	}
+
+	validationErrors = append(validationErrors, validateScopes(input.Scopes, APIKeyScopes)...)
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		validationErrors = append(validationErrors, "expires_at must be in the future")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+func (input *APIKeyInput) ToSqlcParams(userID pgtype.UUID, key string) (sqlc.CreateAPIKeyParams, error) {
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.CreateAPIKeyParams{}, err
+	}
+
+	return sqlc.CreateAPIKeyParams{
+		ID:        newUUUIDV7,
+		UserID:    userID,
+		Name:      strings.TrimSpace(input.Name),
+		KeyPrefix: key[:len(APIKeyPrefix)+8],
+		KeyHash:   utils.HashToken(key),
+		Scopes:    normalizeScopes(input.Scopes),
+		ExpiresAt: optionalTimestamptz(input.ExpiresAt),
+	}, nil
+}
+
+// APIKey is an API key as returned by the API, it never carries the key itself
+type APIKey struct {
+	ID         pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Name       string             `json:"name" example:"Discord bot"`
+	KeyPrefix  string             `json:"key_prefix" example:"lc_1a2b3c4d"`
+	Scopes     []string           `json:"scopes" example:"read,characters:write"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at" swaggertype:"string"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at" swaggertype:"string"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+}
+
+func NewAPIKey(apiKey sqlc.ApiKey) APIKey {
+	return APIKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		KeyPrefix:  apiKey.KeyPrefix,
+		Scopes:     apiKey.Scopes,
+		LastUsedAt: apiKey.LastUsedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+// CreatedAPIKey is returned once when an API key is created, Key can't be retrieved afterwards
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"lc_1a2b3c4d5e6f..."`
+}
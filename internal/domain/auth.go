@@ -2,14 +2,28 @@ package domain
 
 import (
 	"github.com/knands42/lorecrafter/internal/utils"
-	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"slices"
+	"strings"
 	"time"
 )
 
-// LoginInput represents a request to authenticate a user
+// Scopes limit what a token or an API key can do
+const (
+	ScopeRead            = "read"
+	ScopeCampaignsWrite  = "campaigns:write"
+	ScopeCharactersWrite = "characters:write"
+	ScopeAccount         = "account"
+)
+
+// AllScopes are granted to sessions that don't ask for fewer
+var AllScopes = []string{ScopeAccount, ScopeCampaignsWrite, ScopeCharactersWrite, ScopeRead}
+
+// LoginInput represents a request to authenticate a user.
+// Scopes is optional and narrows the session, e.g. to hand a read-only token to a player-facing tool.
 type LoginInput struct {
 	Username string
 	Password string
+	Scopes   []string `example:"read"`
 }
 
 func (input *LoginInput) Validate() error {
@@ -23,6 +37,8 @@ func (input *LoginInput) Validate() error {
 		validationErrors = append(validationErrors, "password must be at least 8 characters long")
 	}
 
+	validationErrors = append(validationErrors, validateScopes(input.Scopes, AllScopes)...)
+
 	if len(validationErrors) > 0 {
 		return &utils.ValidationError{Errors: validationErrors}
 	}
@@ -30,17 +46,162 @@ func (input *LoginInput) Validate() error {
 	return nil
 }
 
-// AuthOutput represents the response after successful authentication
+// AuthOutput represents the response after successful authentication.
+// Token is a short-lived access token and RefreshToken can be exchanged once for a new pair.
 type AuthOutput struct {
-	User      sqlc.User
-	Token     string
-	ExpiresAt time.Time
+	User                  UserProfile
+	Token                 string
+	ExpiresAt             time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// RefreshTokenInput represents a request carrying a refresh token
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
+func (input *RefreshTokenInput) Validate() error {
+	if input.RefreshToken == "" {
+		return &utils.ValidationError{Errors: []string{"refresh_token is required"}}
+	}
+
+	return nil
+}
+
+// VerifyEmailInput represents a request to verify the email address of an account
+type VerifyEmailInput struct {
+	Token string `json:"token"`
+}
+
+func (input *VerifyEmailInput) Validate() error {
+	if input.Token == "" {
+		return &utils.ValidationError{Errors: []string{"token is required"}}
+	}
+
+	return nil
+}
+
+// MinPasswordLength is the minimum length of an account password
+const MinPasswordLength = 8
+
+// ForgotPasswordInput represents a request to receive a password reset link
+type ForgotPasswordInput struct {
+	Email string `json:"email" example:"john@mail.com"`
+}
+
+func (input *ForgotPasswordInput) Validate() error {
+	if strings.TrimSpace(input.Email) == "" {
+		return &utils.ValidationError{Errors: []string{"email is required"}}
+	}
+
+	return nil
+}
+
+// ResetPasswordInput represents a request to set a new password with a reset token
+type ResetPasswordInput struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password" example:"12345678"`
+}
+
+func (input *ResetPasswordInput) Validate() error {
+	var validationErrors []string
+
+	if input.Token == "" {
+		validationErrors = append(validationErrors, "token is required")
+	}
+
+	if len(input.NewPassword) < MinPasswordLength {
+		validationErrors = append(validationErrors, "new_password must be at least 8 characters")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// ChangePasswordInput represents a request from a logged user to change their password
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" example:"12345678"`
+	NewPassword     string `json:"new_password" example:"87654321"`
+	// Revokes every personal API key of the user as well
+	RevokeAPIKeys bool `json:"revoke_api_keys" example:"false"`
+}
+
+func (input *ChangePasswordInput) Validate() error {
+	var validationErrors []string
+
+	if input.CurrentPassword == "" {
+		validationErrors = append(validationErrors, "current_password is required")
+	}
+
+	if len(input.NewPassword) < MinPasswordLength {
+		validationErrors = append(validationErrors, "new_password must be at least 8 characters")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// Token purposes keep tokens signed with the same key from being used for something else
+const (
+	TokenPurposeAccess            = "access"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
 // TokenPayload represents the data stored in the authentication token
 type TokenPayload struct {
+	ID        string    `json:"id"`
+	Purpose   string    `json:"purpose"`
 	UserID    string    `json:"user_id"`
 	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	SessionID string    `json:"session_id,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
+
+// MissingScopes returns the required scopes the token wasn't granted
+func (payload *TokenPayload) MissingScopes(required ...string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !slices.Contains(payload.Scopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+
+	return missing
+}
+
+// SessionScopes returns the scopes a new session is granted, all of them unless fewer were asked for
+func SessionScopes(requested []string) []string {
+	if len(requested) == 0 {
+		return slices.Clone(AllScopes)
+	}
+
+	return normalizeScopes(requested)
+}
+
+// normalizeScopes sorts the scopes and drops the repeated ones
+func normalizeScopes(scopes []string) []string {
+	normalized := slices.Clone(scopes)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+func validateScopes(scopes, allowed []string) []string {
+	var validationErrors []string
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			validationErrors = append(validationErrors, "unknown scope "+scope+", must be one of "+strings.Join(allowed, ", "))
+		}
+	}
+
+	return validationErrors
+}
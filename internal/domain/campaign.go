@@ -2,11 +2,14 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/knands42/lorecrafter/internal/utils"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Campaign permission errors
@@ -15,13 +18,21 @@ var (
 	ErrInsufficientPermission = errors.New("user does not have sufficient permissions for this action")
 )
 
+// Limits of the tags public campaigns are found by
+const (
+	MaxCampaignTags      = 10
+	MaxCampaignTagLength = 30
+)
+
 // CampaignCreationInput represents the input for creating a new campaign
 type CampaignCreationInput struct {
-	Title          string `json:"title"`
-	SettingSummary string `json:"setting_summary"`
-	Setting        string `json:"setting"`
-	ImageURL       string `json:"image_url"`
-	IsPublic       bool   `json:"is_public"`
+	Title          string   `json:"title"`
+	SettingSummary string   `json:"setting_summary"`
+	Setting        string   `json:"setting"`
+	ImageURL       string   `json:"image_url"`
+	IsPublic       bool     `json:"is_public"`
+	Tags           []string `json:"tags" example:"horror,homebrew"`
+	GameSystem     string   `json:"game_system" example:"D&D 5e"`
 }
 
 func (campaign *CampaignCreationInput) Validate() error {
@@ -35,6 +46,8 @@ func (campaign *CampaignCreationInput) Validate() error {
 		validationErrors = append(validationErrors, "title must be at most 100 characters")
 	}
 
+	validationErrors = append(validationErrors, validateCampaignDiscovery(campaign.Tags, campaign.GameSystem)...)
+
 	if len(validationErrors) > 0 {
 		return &utils.ValidationError{Errors: validationErrors}
 	}
@@ -60,11 +73,47 @@ func (campaign *CampaignCreationInput) ToSqlcParams(creatorID uuid.UUID) (sqlc.C
 			String: campaign.Setting,
 			Valid:  true,
 		},
-		IsPublic:  campaign.IsPublic,
-		CreatedBy: creatorUUUIDV7,
+		IsPublic:   campaign.IsPublic,
+		CreatedBy:  creatorUUUIDV7,
+		Tags:       NormalizeTags(campaign.Tags),
+		GameSystem: optionalText(campaign.GameSystem),
 	}, nil
 }
 
+// NormalizeTags lower-cases and trims tags and drops empty and repeated ones, so searches match them regardless of casing
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized
+}
+
+func validateCampaignDiscovery(tags []string, gameSystem string) []string {
+	var validationErrors []string
+
+	normalized := NormalizeTags(tags)
+	if len(normalized) > MaxCampaignTags {
+		validationErrors = append(validationErrors, fmt.Sprintf("at most %d tags are allowed", MaxCampaignTags))
+	}
+	for _, tag := range normalized {
+		if len(tag) > MaxCampaignTagLength {
+			validationErrors = append(validationErrors, fmt.Sprintf("tags must be at most %d characters", MaxCampaignTagLength))
+			break
+		}
+	}
+
+	if len(strings.TrimSpace(gameSystem)) > 50 {
+		validationErrors = append(validationErrors, "game_system must be at most 50 characters")
+	}
+
+	return validationErrors
+}
+
 func HasPermission(memberRole sqlc.MemberRole, requiredRole sqlc.MemberRole) bool {
 	if memberRole == sqlc.MemberRoleGm {
 		return true
@@ -76,6 +125,18 @@ func HasPermission(memberRole sqlc.MemberRole, requiredRole sqlc.MemberRole) boo
 	return false
 }
 
+// ParseMemberRole converts a role name into a MemberRole, defaulting to player when empty
+func ParseMemberRole(role string) (sqlc.MemberRole, error) {
+	switch sqlc.MemberRole(strings.ToLower(strings.TrimSpace(role))) {
+	case "", sqlc.MemberRolePlayer:
+		return sqlc.MemberRolePlayer, nil
+	case sqlc.MemberRoleGm:
+		return sqlc.MemberRoleGm, nil
+	}
+
+	return "", &utils.ValidationError{Errors: []string{"role must be either gm or player"}}
+}
+
 type GetCampaignInput struct {
 	UserId     uuid.UUID `json:"user_id"`
 	CampaignId uuid.UUID `json:"campaign_id"`
@@ -105,6 +166,17 @@ type UpdateCampaignInput struct {
 	Setting        string    `json:"setting"`
 	ImageURL       string    `json:"image_url"`
 	IsPublic       bool      `json:"is_public"`
+	Tags           []string  `json:"tags" example:"horror,homebrew"`
+	GameSystem     string    `json:"game_system" example:"D&D 5e"`
+}
+
+func (campaign *UpdateCampaignInput) Validate() error {
+	validationErrors := validateCampaignDiscovery(campaign.Tags, campaign.GameSystem)
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
 }
 
 func (campaign *UpdateCampaignInput) ToSqlcParams() (sqlc.UpdateCampaignParams, error) {
@@ -112,15 +184,10 @@ func (campaign *UpdateCampaignInput) ToSqlcParams() (sqlc.UpdateCampaignParams,
 	if err != nil {
 		return sqlc.UpdateCampaignParams{}, err
 	}
-	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaign.UserId)
-	if err != nil {
-		return sqlc.UpdateCampaignParams{}, err
-	}
 
 	return sqlc.UpdateCampaignParams{
-		ID:     campaignPGUUID,
-		UserID: userPGUUID,
-		Title:  campaign.Title,
+		ID:    campaignPGUUID,
+		Title: campaign.Title,
 		SettingSummary: pgtype.Text{
 			String: campaign.SettingSummary,
 			Valid:  true,
@@ -133,7 +200,9 @@ func (campaign *UpdateCampaignInput) ToSqlcParams() (sqlc.UpdateCampaignParams,
 			String: campaign.ImageURL,
 			Valid:  true,
 		},
-		IsPublic: campaign.IsPublic,
+		IsPublic:   campaign.IsPublic,
+		Tags:       NormalizeTags(campaign.Tags),
+		GameSystem: optionalText(campaign.GameSystem),
 	}, nil
 }
 
@@ -142,18 +211,77 @@ type DeleteCampaignInput struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
-func (campaign *DeleteCampaignInput) ToSqlcParams() (sqlc.DeleteCampaignParams, error) {
-	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaign.ID)
-	if err != nil {
-		return sqlc.DeleteCampaignParams{}, err
+// MaxInviteCodeLifetimeHours caps how long an invite code may stay valid
+const MaxInviteCodeLifetimeHours = 30 * 24
+
+// InviteCodeInput represents the limits applied to a newly generated campaign invite code.
+// Zero values mean the code never expires or can be used any number of times.
+type InviteCodeInput struct {
+	ExpiresInHours int   `json:"expires_in_hours" example:"48"`
+	MaxUses        int32 `json:"max_uses" example:"10"`
+}
+
+func (input *InviteCodeInput) Validate() error {
+	var validationErrors []string
+
+	if input.ExpiresInHours < 0 {
+		validationErrors = append(validationErrors, "expires_in_hours must not be negative")
 	}
-	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaign.UserID)
-	if err != nil {
-		return sqlc.DeleteCampaignParams{}, err
+
+	if input.ExpiresInHours > MaxInviteCodeLifetimeHours {
+		validationErrors = append(validationErrors, fmt.Sprintf("expires_in_hours must be at most %d", MaxInviteCodeLifetimeHours))
 	}
 
-	return sqlc.DeleteCampaignParams{
-		ID:     campaignPGUUID,
-		UserID: userPGUUID,
-	}, nil
+	if input.MaxUses < 0 {
+		validationErrors = append(validationErrors, "max_uses must not be negative")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+func (input *InviteCodeInput) ToSqlcParams(campaignID pgtype.UUID, code string) sqlc.GenerateInviteCodeParams {
+	params := sqlc.GenerateInviteCodeParams{
+		ID: campaignID,
+		InviteCode: pgtype.Text{
+			String: code,
+			Valid:  true,
+		},
+	}
+
+	if input.ExpiresInHours > 0 {
+		params.InviteCodeExpiresAt = pgtype.Timestamptz{
+			Time:  time.Now().Add(time.Duration(input.ExpiresInHours) * time.Hour),
+			Valid: true,
+		}
+	}
+
+	if input.MaxUses > 0 {
+		params.InviteCodeMaxUses = pgtype.Int4{
+			Int32: input.MaxUses,
+			Valid: true,
+		}
+	}
+
+	return params
+}
+
+// InviteCodeOutput represents the current invite code of a campaign and its usage
+type InviteCodeOutput struct {
+	Code      string             `json:"code"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	MaxUses   pgtype.Int4        `json:"max_uses"`
+	Uses      int32              `json:"uses"`
+}
+
+func NewInviteCodeOutput(campaign sqlc.Campaign) InviteCodeOutput {
+	return InviteCodeOutput{
+		Code:      campaign.InviteCode.String,
+		ExpiresAt: campaign.InviteCodeExpiresAt,
+		MaxUses:   campaign.InviteCodeMaxUses,
+		Uses:      campaign.InviteCodeUses,
+	}
 }
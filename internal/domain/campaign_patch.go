@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// CampaignPatch is a JSON Merge Patch (RFC 7386) of a campaign: fields left out are kept as they are
+// and fields set to null are cleared
+type CampaignPatch map[string]json.RawMessage
+
+var patchableCampaignFields = []string{"title", "setting_summary", "setting", "image_url", "is_public", "tags", "game_system"}
+
+// Apply computes the campaign resulting from the patch, ready to be saved if the campaign is still at the same version
+func (patch CampaignPatch) Apply(campaign sqlc.Campaign) (sqlc.PatchCampaignParams, error) {
+	params := sqlc.PatchCampaignParams{
+		ID:             campaign.ID,
+		Version:        campaign.Version,
+		Title:          campaign.Title,
+		SettingSummary: campaign.SettingSummary,
+		Setting:        campaign.Setting,
+		ImageUrl:       campaign.ImageUrl,
+		IsPublic:       campaign.IsPublic,
+		Tags:           campaign.Tags,
+		GameSystem:     campaign.GameSystem,
+	}
+
+	var validationErrors []string
+	for field, value := range patch {
+		if !slices.Contains(patchableCampaignFields, field) {
+			validationErrors = append(validationErrors, field+" cannot be patched")
+			continue
+		}
+
+		var err error
+		switch field {
+		case "title":
+			err = patchRequired(value, &params.Title)
+		case "setting_summary":
+			params.SettingSummary, err = patchText(value)
+		case "setting":
+			params.Setting, err = patchText(value)
+		case "image_url":
+			params.ImageUrl, err = patchText(value)
+		case "is_public":
+			err = patchRequired(value, &params.IsPublic)
+		case "tags":
+			var tags []string
+			err = json.Unmarshal(value, &tags)
+			params.Tags = NormalizeTags(tags)
+		case "game_system":
+			params.GameSystem, err = patchText(value)
+		}
+		if err != nil {
+			validationErrors = append(validationErrors, field+": "+err.Error())
+		}
+	}
+
+	if strings.TrimSpace(params.Title) == "" {
+		validationErrors = append(validationErrors, "title is required")
+	}
+	if len(params.Title) > 100 {
+		validationErrors = append(validationErrors, "title must be at most 100 characters")
+	}
+	validationErrors = append(validationErrors, validateCampaignDiscovery(params.Tags, params.GameSystem.String)...)
+
+	if len(validationErrors) > 0 {
+		slices.Sort(validationErrors)
+		return sqlc.PatchCampaignParams{}, &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return params, nil
+}
+
+// patchRequired decodes the value of a field that cannot be cleared
+func patchRequired[T any](value json.RawMessage, field *T) error {
+	if string(value) == "null" {
+		return errors.New("cannot be null")
+	}
+
+	return json.Unmarshal(value, field)
+}
+
+// patchText decodes the value of a text field, null or blank clearing it
+func patchText(value json.RawMessage) (pgtype.Text, error) {
+	var text *string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return pgtype.Text{}, err
+	}
+	if text == nil {
+		return pgtype.Text{}, nil
+	}
+
+	return optionalText(*text), nil
+}
+
+// CampaignETag is the entity tag of a campaign version, as sent in the ETag header
+func CampaignETag(campaign sqlc.Campaign) string {
+	return strconv.Quote(strconv.Itoa(int(campaign.Version)))
+}
+
+// MatchesCampaignETag tells whether an If-Match header holds the entity tag of the campaign version.
+// Weak tags never match, as If-Match requires a strong comparison.
+func MatchesCampaignETag(ifMatch string, campaign sqlc.Campaign) bool {
+	etag := CampaignETag(campaign)
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
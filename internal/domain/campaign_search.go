@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// Orders of the public campaign listing, each from the highest value down
+const (
+	CampaignSortNewest      = "newest"
+	CampaignSortMostCloned  = "most_cloned"
+	CampaignSortMostMembers = "most_members"
+)
+
+var campaignSorts = []string{CampaignSortNewest, CampaignSortMostCloned, CampaignSortMostMembers}
+
+// Page sizes of the public campaign listing
+const (
+	DefaultCampaignPageSize = 20
+	MaxCampaignPageSize     = 100
+)
+
+// PublicCampaignFilter narrows down and orders the listing of public campaigns.
+// Cursor is the next_cursor of the previous page, it only continues a listing with the same sort.
+type PublicCampaignFilter struct {
+	Query      string
+	Tags       []string
+	GameSystem string
+	Creator    string
+	Sort       string
+	Cursor     string
+	Limit      int
+}
+
+func (filter *PublicCampaignFilter) Validate() error {
+	var validationErrors []string
+
+	if filter.Sort != "" && !slices.Contains(campaignSorts, filter.Sort) {
+		validationErrors = append(validationErrors, "sort must be one of "+strings.Join(campaignSorts, ", "))
+	}
+
+	if filter.Limit < 0 || filter.Limit > MaxCampaignPageSize {
+		validationErrors = append(validationErrors, fmt.Sprintf("limit must be between 1 and %d", MaxCampaignPageSize))
+	}
+
+	if filter.Cursor != "" {
+		if _, _, err := decodeCampaignCursor(filter.Cursor, filter.sort()); err != nil {
+			validationErrors = append(validationErrors, "cursor is invalid")
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// ToSqlcParams asks for one campaign more than the page holds, to tell whether there is a next page
+func (filter *PublicCampaignFilter) ToSqlcParams() (sqlc.SearchPublicCampaignsParams, error) {
+	params := sqlc.SearchPublicCampaignsParams{
+		Query:      optionalText(filter.Query),
+		Tags:       NormalizeTags(filter.Tags),
+		GameSystem: optionalText(filter.GameSystem),
+		Creator:    optionalText(filter.Creator),
+		Sort:       filter.sort(),
+		PageSize:   int32(filter.limit() + 1),
+	}
+
+	if filter.Cursor != "" {
+		key, id, err := decodeCampaignCursor(filter.Cursor, params.Sort)
+		if err != nil {
+			return sqlc.SearchPublicCampaignsParams{}, err
+		}
+		params.CursorKey = pgtype.Int8{Int64: key, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	return params, nil
+}
+
+func (filter *PublicCampaignFilter) sort() string {
+	if filter.Sort == "" {
+		return CampaignSortNewest
+	}
+	return filter.Sort
+}
+
+func (filter *PublicCampaignFilter) limit() int {
+	if filter.Limit == 0 {
+		return DefaultCampaignPageSize
+	}
+	return filter.Limit
+}
+
+// PublicCampaign is a public campaign as listed to everyone, its setting and invite code are left out
+type PublicCampaign struct {
+	ID              pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Title           string             `json:"title"`
+	SettingSummary  pgtype.Text        `json:"setting_summary" swaggertype:"string"`
+	ImageURL        pgtype.Text        `json:"image_url" swaggertype:"string"`
+	Tags            []string           `json:"tags"`
+	GameSystem      pgtype.Text        `json:"game_system" swaggertype:"string"`
+	CreatedBy       pgtype.UUID        `json:"created_by" swaggertype:"string"`
+	CreatorUsername string             `json:"creator_username"`
+	MemberCount     int64              `json:"member_count"`
+	CloneCount      int32              `json:"clone_count"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at" swaggertype:"string"`
+}
+
+// PublicCampaignPage is a page of public campaigns, NextCursor is empty on the last page
+type PublicCampaignPage struct {
+	Campaigns  []PublicCampaign `json:"campaigns"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// NewPublicCampaignPage builds a page out of the rows fetched with the filter, which hold one row too many when
+// there is a next page
+func NewPublicCampaignPage(rows []sqlc.SearchPublicCampaignsRow, filter PublicCampaignFilter) PublicCampaignPage {
+	page := PublicCampaignPage{Campaigns: make([]PublicCampaign, 0, len(rows))}
+
+	if len(rows) > filter.limit() {
+		rows = rows[:filter.limit()]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeCampaignCursor(filter.sort(), last.SortKey, last.ID.Bytes)
+	}
+
+	for _, row := range rows {
+		page.Campaigns = append(page.Campaigns, PublicCampaign{
+			ID:              row.ID,
+			Title:           row.Title,
+			SettingSummary:  row.SettingSummary,
+			ImageURL:        row.ImageUrl,
+			Tags:            row.Tags,
+			GameSystem:      row.GameSystem,
+			CreatedBy:       row.CreatedBy,
+			CreatorUsername: row.CreatorUsername,
+			MemberCount:     row.MemberCount,
+			CloneCount:      row.CloneCount,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		})
+	}
+
+	return page
+}
+
+// encodeCampaignCursor packs the sort, the sort key and the ID of the last campaign of a page
+func encodeCampaignCursor(sort string, key int64, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%s:%d:%s", sort, key, id))
+}
+
+func decodeCampaignCursor(cursor, sort string) (int64, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, uuid.UUID{}, err
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || parts[0] != sort {
+		return 0, uuid.UUID{}, fmt.Errorf("cursor doesn't continue a listing sorted by %s", sort)
+	}
+	key, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, uuid.UUID{}, err
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return 0, uuid.UUID{}, err
+	}
+
+	return key, id, nil
+}
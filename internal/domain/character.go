@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+const (
+	MinCharacterLevel = 1
+	MaxCharacterLevel = 100
+)
+
+// CharacterInput represents the details of a character when creating or updating it
+type CharacterInput struct {
+	Name        string          `json:"name" example:"Elara Moonwhisper"`
+	Race        string          `json:"race" example:"Elf"`
+	Class       string          `json:"class" example:"Ranger"`
+	Level       int32           `json:"level" example:"3"`
+	Appearance  string          `json:"appearance"`
+	Personality string          `json:"personality"`
+	Backstory   string          `json:"backstory"`
+	ImageURL    string          `json:"image_url"`
+	IsNPC       bool            `json:"is_npc"`
+	Metadata    json.RawMessage `json:"metadata" swaggertype:"object"`
+}
+
+func (character *CharacterInput) Validate() error {
+	var validationErrors []string
+
+	if strings.TrimSpace(character.Name) == "" {
+		validationErrors = append(validationErrors, "name is required")
+	}
+
+	if len(character.Name) > 100 {
+		validationErrors = append(validationErrors, "name must be at most 100 characters")
+	}
+
+	if len(character.Race) > 50 {
+		validationErrors = append(validationErrors, "race must be at most 50 characters")
+	}
+
+	if len(character.Class) > 50 {
+		validationErrors = append(validationErrors, "class must be at most 50 characters")
+	}
+
+	// A missing level defaults to the first one
+	if character.Level != 0 && (character.Level < MinCharacterLevel || character.Level > MaxCharacterLevel) {
+		validationErrors = append(validationErrors, "level must be between 1 and 100")
+	}
+
+	if len(character.Metadata) > 0 && !isJSONObject(character.Metadata) {
+		validationErrors = append(validationErrors, "metadata must be a JSON object")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+func (character *CharacterInput) ToSqlcParams(campaignID, ownerID pgtype.UUID) (sqlc.CreateCharacterParams, error) {
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.CreateCharacterParams{}, err
+	}
+
+	return sqlc.CreateCharacterParams{
+		ID:          newUUUIDV7,
+		Name:        strings.TrimSpace(character.Name),
+		Race:        optionalText(character.Race),
+		Class:       optionalText(character.Class),
+		Level:       character.level(),
+		Appearance:  optionalText(character.Appearance),
+		Personality: optionalText(character.Personality),
+		Backstory:   optionalText(character.Backstory),
+		ImageUrl:    optionalText(character.ImageURL),
+		CampaignID:  campaignID,
+		UserID:      ownerID,
+		IsNpc:       character.IsNPC,
+		Metadata:    character.metadata(),
+	}, nil
+}
+
+func (character *CharacterInput) ToSqlcUpdateParams(characterID, campaignID pgtype.UUID) sqlc.UpdateCharacterParams {
+	return sqlc.UpdateCharacterParams{
+		ID:          characterID,
+		CampaignID:  campaignID,
+		Name:        strings.TrimSpace(character.Name),
+		Race:        optionalText(character.Race),
+		Class:       optionalText(character.Class),
+		Level:       character.level(),
+		Appearance:  optionalText(character.Appearance),
+		Personality: optionalText(character.Personality),
+		Backstory:   optionalText(character.Backstory),
+		ImageUrl:    optionalText(character.ImageURL),
+		IsNpc:       character.IsNPC,
+		Metadata:    character.metadata(),
+	}
+}
+
+func (character *CharacterInput) level() int32 {
+	if character.Level == 0 {
+		return MinCharacterLevel
+	}
+	return character.Level
+}
+
+func (character *CharacterInput) metadata() json.RawMessage {
+	if len(character.Metadata) == 0 || bytes.Equal(bytes.TrimSpace(character.Metadata), []byte("null")) {
+		return json.RawMessage("{}")
+	}
+	return character.Metadata
+}
+
+// isJSONObject reports whether the raw value is a JSON object, treating null as an empty one
+func isJSONObject(raw json.RawMessage) bool {
+	var object map[string]any
+	return json.Unmarshal(raw, &object) == nil
+}
+
+// optionalText maps blank strings to NULL so optional columns stay empty
+func optionalText(value string) pgtype.Text {
+	value = strings.TrimSpace(value)
+	return pgtype.Text{
+		String: value,
+		Valid:  value != "",
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+// EmailMessage represents a plain text email sent to a single recipient
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
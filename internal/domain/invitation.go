@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// InvitationCreationInput represents a request to invite someone to a campaign by email
+type InvitationCreationInput struct {
+	Email string `json:"email" example:"player@mail.com"`
+}
+
+func (input *InvitationCreationInput) Validate() error {
+	var validationErrors []string
+
+	if strings.TrimSpace(input.Email) == "" {
+		validationErrors = append(validationErrors, "email is required")
+	} else if !strings.Contains(input.Email, "@") {
+		validationErrors = append(validationErrors, "email is invalid")
+	}
+
+	if len(input.Email) > 120 {
+		validationErrors = append(validationErrors, "email must be at most 120 characters")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+func (input *InvitationCreationInput) ToSqlcParams(
+	campaignID, invitedBy pgtype.UUID,
+	token string,
+	expiresAt time.Time,
+) (sqlc.CreateInvitationParams, error) {
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.CreateInvitationParams{}, err
+	}
+
+	return sqlc.CreateInvitationParams{
+		ID:         newUUUIDV7,
+		CampaignID: campaignID,
+		Email:      NormalizeEmail(input.Email),
+		InvitedBy:  invitedBy,
+		Token:      token,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  expiresAt,
+			Valid: true,
+		},
+	}, nil
+}
+
+// NormalizeEmail trims and lower-cases an email so invitations match the invitee regardless of casing
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
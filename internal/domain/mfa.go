@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/utils"
+)
+
+// TokenPurposeMFAPending marks the short-lived token given after the password step of a two-factor login
+const TokenPurposeMFAPending = "mfa_pending"
+
+// MFAChallenge is returned by the login of users with two-factor authentication enabled.
+// MFAToken must be sent back with a code to get the access and refresh tokens.
+type MFAChallenge struct {
+	MFARequired bool      `json:"mfa_required" example:"true"`
+	MFAToken    string    `json:"mfa_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// MFALoginInput represents the second step of a two-factor login
+type MFALoginInput struct {
+	MFAToken string `json:"mfa_token"`
+	// Either a code from the authenticator app or an unused recovery code
+	Code string `json:"code" example:"123456"`
+}
+
+func (input *MFALoginInput) Validate() error {
+	var validationErrors []string
+
+	if input.MFAToken == "" {
+		validationErrors = append(validationErrors, "mfa_token is required")
+	}
+
+	if strings.TrimSpace(input.Code) == "" {
+		validationErrors = append(validationErrors, "code is required")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// MFAEnrollment holds the secret of a pending enrollment, to be added to an authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	// otpauth:// URI to render as a QR code
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/LoreCrafter:johndoe?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=LoreCrafter"`
+}
+
+// MFACodeInput represents a code from the authenticator app
+type MFACodeInput struct {
+	Code string `json:"code" example:"123456"`
+}
+
+func (input *MFACodeInput) Validate() error {
+	if strings.TrimSpace(input.Code) == "" {
+		return &utils.ValidationError{Errors: []string{"code is required"}}
+	}
+
+	return nil
+}
+
+// MFARecoveryCodes are shown once when two-factor authentication is enabled, each can replace a code a single time
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"ABCDE-FGH23"`
+}
+
+// DisableMFAInput represents a request to turn two-factor authentication off
+type DisableMFAInput struct {
+	Password string `json:"password"`
+	// Either a code from the authenticator app or an unused recovery code
+	Code string `json:"code" example:"123456"`
+}
+
+func (input *DisableMFAInput) Validate() error {
+	var validationErrors []string
+
+	if input.Password == "" {
+		validationErrors = append(validationErrors, "password is required")
+	}
+
+	if strings.TrimSpace(input.Code) == "" {
+		validationErrors = append(validationErrors, "code is required")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
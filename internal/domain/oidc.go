@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// Bounds of the usernames given to accounts created through a login provider
+const (
+	minProvisionedUsernameLength = 4
+	maxProvisionedUsernameLength = 30
+)
+
+// OIDCIdentity is the account of a user at an OpenID Connect provider, as told by a verified ID token
+type OIDCIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+// ToSqlcParams links the identity to a user
+func (identity *OIDCIdentity) ToSqlcParams(userID pgtype.UUID, provider string) (sqlc.CreateUserIdentityParams, error) {
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.CreateUserIdentityParams{}, err
+	}
+
+	return sqlc.CreateUserIdentityParams{
+		ID:       newUUUIDV7,
+		UserID:   userID,
+		Provider: provider,
+		Issuer:   identity.Issuer,
+		Subject:  identity.Subject,
+		Email:    optionalText(identity.Email),
+	}, nil
+}
+
+// Username suggests a username for an account created from the identity, it may already be taken
+func (identity *OIDCIdentity) Username() string {
+	candidates := []string{identity.PreferredUsername, identity.Name}
+	if localPart, _, found := strings.Cut(identity.Email, "@"); found {
+		candidates = append(candidates, localPart)
+	}
+
+	for _, candidate := range candidates {
+		username := strings.Map(func(r rune) rune {
+			switch {
+			case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+				return unicode.ToLower(r)
+			case r == '_' || r == '-' || r == '.':
+				return r
+			case unicode.IsSpace(r):
+				return '_'
+			default:
+				return -1
+			}
+		}, candidate)
+		if len(username) > maxProvisionedUsernameLength {
+			username = username[:maxProvisionedUsernameLength]
+		}
+		if len(username) >= minProvisionedUsernameLength {
+			return username
+		}
+	}
+
+	return "adventurer"
+}
+
+// OIDCProviders lists the providers users can log in with
+type OIDCProviders struct {
+	Providers []string `json:"providers" example:"google,github"`
+}
+
+// OIDCAuthorization points the user to the login page of the provider
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://accounts.google.com/o/oauth2/v2/auth?client_id=..."`
+}
+
+// OIDCCallbackInput represents the query parameters the provider redirects back with
+type OIDCCallbackInput struct {
+	Code             string
+	State            string
+	Error            string
+	ErrorDescription string
+}
+
+func (input *OIDCCallbackInput) Validate() error {
+	var validationErrors []string
+
+	if input.Error != "" {
+		validationErrors = append(validationErrors, "provider error: "+strings.TrimSpace(input.Error+" "+input.ErrorDescription))
+	}
+
+	if input.Code == "" {
+		validationErrors = append(validationErrors, "code is required")
+	}
+
+	if input.State == "" {
+		validationErrors = append(validationErrors, "state is required")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// OIDCCallbackResult is the outcome of coming back from a provider, only one of its fields is set.
+// Logins give either Auth or, for users with two-factor authentication enabled, Challenge.
+// Linking a provider to the logged user gives Identity.
+type OIDCCallbackResult struct {
+	Auth      *AuthOutput
+	Challenge *MFAChallenge
+	Identity  *UserIdentity
+}
+
+// UserIdentity is a provider account linked to a user as returned by the API
+type UserIdentity struct {
+	ID          pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Provider    string             `json:"provider" example:"google"`
+	Email       pgtype.Text        `json:"email" swaggertype:"string" example:"john@gmail.com"`
+	LastLoginAt pgtype.Timestamptz `json:"last_login_at" swaggertype:"string"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+}
+
+func NewUserIdentity(identity sqlc.UserIdentity) UserIdentity {
+	return UserIdentity{
+		ID:          identity.ID,
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		LastLoginAt: identity.LastLoginAt,
+		CreatedAt:   identity.CreatedAt,
+	}
+}
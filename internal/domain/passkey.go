@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// PasskeyUser is a user as seen by the WebAuthn ceremonies, the handle is the ID of the user
+type PasskeyUser struct {
+	Handle      []byte
+	Name        string
+	DisplayName string
+	Credentials []PasskeyCredential
+}
+
+func NewPasskeyUser(user sqlc.User, passkeys []sqlc.Passkey) PasskeyUser {
+	credentials := make([]PasskeyCredential, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		credentials = append(credentials, NewPasskeyCredential(passkey))
+	}
+
+	return PasskeyUser{
+		Handle:      user.ID.Bytes[:],
+		Name:        user.Username,
+		DisplayName: user.Username,
+		Credentials: credentials,
+	}
+}
+
+// PasskeyCredential is the public key of an authenticator along with what it reported about itself.
+// CloneWarning is set when a login presented a signature counter that didn't increase.
+type PasskeyCredential struct {
+	CredentialID    []byte
+	PublicKey       []byte
+	AttestationType string
+	Transports      []string
+	AAGUID          []byte
+	SignCount       uint32
+	BackupEligible  bool
+	BackupState     bool
+	CloneWarning    bool
+}
+
+func NewPasskeyCredential(passkey sqlc.Passkey) PasskeyCredential {
+	return PasskeyCredential{
+		CredentialID:    passkey.CredentialID,
+		PublicKey:       passkey.PublicKey,
+		AttestationType: passkey.AttestationType,
+		Transports:      passkey.Transports,
+		AAGUID:          passkey.Aaguid,
+		SignCount:       uint32(passkey.SignCount),
+		BackupEligible:  passkey.BackupEligible,
+		BackupState:     passkey.BackupState,
+	}
+}
+
+// PasskeyOptions starts a registration or login ceremony.
+// Options are passed to navigator.credentials.create() or get(), the session ID is sent back with the result.
+type PasskeyOptions struct {
+	SessionID string          `json:"session_id" example:"8f14e45fceea167a5a36dedd4bea2543..."`
+	Options   json.RawMessage `json:"options" swaggertype:"object"`
+}
+
+// PasskeyRegistrationInput represents the credential created by the authenticator, in its JSON serialization
+type PasskeyRegistrationInput struct {
+	SessionID  string          `json:"session_id" example:"8f14e45fceea167a5a36dedd4bea2543..."`
+	Name       string          `json:"name" example:"YubiKey"`
+	Credential json.RawMessage `json:"credential" swaggertype:"object"`
+}
+
+func (input *PasskeyRegistrationInput) Validate() error {
+	var validationErrors []string
+
+	if input.SessionID == "" {
+		validationErrors = append(validationErrors, "session_id is required")
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		validationErrors = append(validationErrors, "name is required")
+	}
+
+	if len(input.Name) > 100 {
+		validationErrors = append(validationErrors, "name must be at most 100 characters")
+	}
+
+	if len(input.Credential) == 0 {
+		validationErrors = append(validationErrors, "credential is required")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+func (input *PasskeyRegistrationInput) ToSqlcParams(userID pgtype.UUID, credential PasskeyCredential) (sqlc.CreatePasskeyParams, error) {
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.CreatePasskeyParams{}, err
+	}
+
+	transports := credential.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+
+	return sqlc.CreatePasskeyParams{
+		ID:              newUUUIDV7,
+		UserID:          userID,
+		Name:            strings.TrimSpace(input.Name),
+		CredentialID:    credential.CredentialID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		Aaguid:          credential.AAGUID,
+		SignCount:       int64(credential.SignCount),
+		BackupEligible:  credential.BackupEligible,
+		BackupState:     credential.BackupState,
+	}, nil
+}
+
+// PasskeyLoginInput represents the assertion signed by the authenticator, in its JSON serialization
+type PasskeyLoginInput struct {
+	SessionID  string          `json:"session_id" example:"8f14e45fceea167a5a36dedd4bea2543..."`
+	Credential json.RawMessage `json:"credential" swaggertype:"object"`
+}
+
+func (input *PasskeyLoginInput) Validate() error {
+	var validationErrors []string
+
+	if input.SessionID == "" {
+		validationErrors = append(validationErrors, "session_id is required")
+	}
+
+	if len(input.Credential) == 0 {
+		validationErrors = append(validationErrors, "credential is required")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// Passkey is a registered authenticator as returned by the API, it never carries the public key
+type Passkey struct {
+	ID             pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Name           string             `json:"name" example:"YubiKey"`
+	Transports     []string           `json:"transports" example:"usb,nfc"`
+	BackupEligible bool               `json:"backup_eligible" example:"false"`
+	BackupState    bool               `json:"backup_state" example:"false"`
+	LastUsedAt     pgtype.Timestamptz `json:"last_used_at" swaggertype:"string"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+}
+
+func NewPasskey(passkey sqlc.Passkey) Passkey {
+	return Passkey{
+		ID:             passkey.ID,
+		Name:           passkey.Name,
+		Transports:     passkey.Transports,
+		BackupEligible: passkey.BackupEligible,
+		BackupState:    passkey.BackupState,
+		LastUsedAt:     passkey.LastUsedAt,
+		CreatedAt:      passkey.CreatedAt,
+	}
+}
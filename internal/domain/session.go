@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// SessionClient describes the device a session is started from
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session is a login of the user on a device, it lasts as long as its refresh tokens keep being rotated
+type Session struct {
+	ID         pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	UserAgent  string             `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0"`
+	IPAddress  string             `json:"ip_address" example:"203.0.113.7"`
+	Current    bool               `json:"current" example:"true"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at" swaggertype:"string"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at" swaggertype:"string"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+}
+
+func NewSession(session sqlc.Session, current bool) Session {
+	return Session{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IpAddress,
+		Current:    current,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		CreatedAt:  session.CreatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// TimelineEventInput represents the details of a timeline event when creating or updating it.
+// Events are public unless is_public is explicitly set to false, in which case only GMs can see them.
+type TimelineEventInput struct {
+	Title       string     `json:"title" example:"The fall of Greyhold"`
+	Description string     `json:"description"`
+	EventDate   *time.Time `json:"event_date" example:"2024-03-01T18:00:00Z"`
+	IsPublic    *bool      `json:"is_public" example:"true"`
+}
+
+func (event *TimelineEventInput) Validate() error {
+	var validationErrors []string
+
+	if strings.TrimSpace(event.Title) == "" {
+		validationErrors = append(validationErrors, "title is required")
+	}
+
+	if len(event.Title) > 200 {
+		validationErrors = append(validationErrors, "title must be at most 200 characters")
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+func (event *TimelineEventInput) ToSqlcParams(campaignID, creatorID pgtype.UUID) (sqlc.CreateTimelineEventParams, error) {
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.CreateTimelineEventParams{}, err
+	}
+
+	return sqlc.CreateTimelineEventParams{
+		ID:          newUUUIDV7,
+		CampaignID:  campaignID,
+		Title:       strings.TrimSpace(event.Title),
+		Description: optionalText(event.Description),
+		EventDate:   optionalTimestamptz(event.EventDate),
+		IsPublic:    event.isPublic(),
+		CreatedBy:   creatorID,
+	}, nil
+}
+
+func (event *TimelineEventInput) ToSqlcUpdateParams(eventID, campaignID pgtype.UUID) sqlc.UpdateTimelineEventParams {
+	return sqlc.UpdateTimelineEventParams{
+		ID:          eventID,
+		CampaignID:  campaignID,
+		Title:       strings.TrimSpace(event.Title),
+		Description: optionalText(event.Description),
+		EventDate:   optionalTimestamptz(event.EventDate),
+		IsPublic:    event.isPublic(),
+	}
+}
+
+func (event *TimelineEventInput) isPublic() bool {
+	return event.IsPublic == nil || *event.IsPublic
+}
+
+// TimelineEventFilter restricts a timeline listing to events dated within an optional range
+type TimelineEventFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+func (filter *TimelineEventFilter) Validate() error {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return &utils.ValidationError{Errors: []string{"from must not be after to"}}
+	}
+
+	return nil
+}
+
+func (filter *TimelineEventFilter) ToSqlcParams(campaignID pgtype.UUID, includeSecret bool) sqlc.ListTimelineEventsParams {
+	return sqlc.ListTimelineEventsParams{
+		CampaignID:    campaignID,
+		IncludeSecret: includeSecret,
+		FromDate:      optionalTimestamptz(filter.From),
+		ToDate:        optionalTimestamptz(filter.To),
+	}
+}
+
+// optionalTimestamptz maps a nil time to NULL
+func optionalTimestamptz(value *time.Time) pgtype.Timestamptz {
+	if value == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{
+		Time:  *value,
+		Valid: true,
+	}
+}
@@ -2,8 +2,10 @@ package domain
 
 import (
 	"errors"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/knands42/lorecrafter/internal/utils"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"net/url"
 	"strings"
 )
 
@@ -54,3 +56,145 @@ func (user *UserCreationInput) ToSqlcParams(hashedPassword string) (sqlc.CreateU
 		HashedPassword: hashedPassword,
 	}, nil
 }
+
+// UserProfile is the account of a user as returned by the API, it never carries the password hash
+type UserProfile struct {
+	ID          pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Username    string             `json:"username" example:"johndoe"`
+	Email       string             `json:"email" example:"john@mail.com"`
+	IsActive    bool               `json:"is_active"`
+	MFAEnabled  bool               `json:"mfa_enabled"`
+	AvatarURL   pgtype.Text        `json:"avatar_url" swaggertype:"string"`
+	LastLoginAt pgtype.Timestamptz `json:"last_login_at" swaggertype:"string"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at" swaggertype:"string"`
+}
+
+func NewUserProfile(user sqlc.User) UserProfile {
+	return UserProfile{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		IsActive:    user.IsActive,
+		MFAEnabled:  user.MfaEnabled,
+		AvatarURL:   user.AvatarUrl,
+		LastLoginAt: user.LastLoginAt,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
+
+// PublicUserProfile is the profile of a user as seen by other users, along with the public campaigns they play in
+type PublicUserProfile struct {
+	ID        pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Username  string             `json:"username" example:"johndoe"`
+	AvatarURL pgtype.Text        `json:"avatar_url" swaggertype:"string"`
+	CreatedAt pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+	Campaigns []ProfileCampaign  `json:"campaigns"`
+}
+
+// ProfileCampaign is a public campaign as listed on the profile of its members, its setting and invite code are left out
+type ProfileCampaign struct {
+	ID             pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Title          string             `json:"title"`
+	SettingSummary pgtype.Text        `json:"setting_summary" swaggertype:"string"`
+	ImageURL       pgtype.Text        `json:"image_url" swaggertype:"string"`
+	Tags           []string           `json:"tags"`
+	GameSystem     pgtype.Text        `json:"game_system" swaggertype:"string"`
+	CreatedBy      pgtype.UUID        `json:"created_by" swaggertype:"string"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at" swaggertype:"string"`
+}
+
+func NewPublicUserProfile(user sqlc.User, campaigns []sqlc.ListPublicCampaignsByUserIDRow) PublicUserProfile {
+	profile := PublicUserProfile{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarUrl,
+		CreatedAt: user.CreatedAt,
+		Campaigns: make([]ProfileCampaign, 0, len(campaigns)),
+	}
+
+	for _, campaign := range campaigns {
+		profile.Campaigns = append(profile.Campaigns, ProfileCampaign{
+			ID:             campaign.ID,
+			Title:          campaign.Title,
+			SettingSummary: campaign.SettingSummary,
+			ImageURL:       campaign.ImageUrl,
+			Tags:           campaign.Tags,
+			GameSystem:     campaign.GameSystem,
+			CreatedBy:      campaign.CreatedBy,
+			CreatedAt:      campaign.CreatedAt,
+			UpdatedAt:      campaign.UpdatedAt,
+		})
+	}
+
+	return profile
+}
+
+// UpdateProfileInput represents the changes to the profile of the logged user, fields left out are kept as they are.
+// An empty avatar_url removes the avatar.
+type UpdateProfileInput struct {
+	Username  *string `json:"username,omitempty" example:"johndoe"`
+	Email     *string `json:"email,omitempty" example:"john@mail.com"`
+	AvatarURL *string `json:"avatar_url,omitempty" example:"https://example.com/avatar.png"`
+}
+
+func (input *UpdateProfileInput) Validate() error {
+	var validationErrors []string
+
+	if input.Username != nil {
+		username := strings.TrimSpace(*input.Username)
+		if username == "" {
+			validationErrors = append(validationErrors, "username is required")
+		} else if len(username) > 80 {
+			validationErrors = append(validationErrors, "username must be at most 80 characters")
+		}
+	}
+
+	if input.Email != nil {
+		email := strings.TrimSpace(*input.Email)
+		if email == "" {
+			validationErrors = append(validationErrors, "email is required")
+		} else if !strings.Contains(email, "@") {
+			validationErrors = append(validationErrors, "email is invalid")
+		} else if len(email) > 120 {
+			validationErrors = append(validationErrors, "email must be at most 120 characters")
+		}
+	}
+
+	if input.AvatarURL != nil && *input.AvatarURL != "" {
+		avatarURL, err := url.Parse(*input.AvatarURL)
+		if err != nil || (avatarURL.Scheme != "http" && avatarURL.Scheme != "https") || avatarURL.Host == "" {
+			validationErrors = append(validationErrors, "avatar_url must be an http or https URL")
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// ToSqlcParams applies the changes to the current state of the user
+func (input *UpdateProfileInput) ToSqlcParams(user sqlc.User) sqlc.UpdateUserProfileParams {
+	params := sqlc.UpdateUserProfileParams{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		AvatarUrl: user.AvatarUrl,
+	}
+
+	if input.Username != nil {
+		params.Username = strings.TrimSpace(*input.Username)
+	}
+	if input.Email != nil {
+		params.Email = strings.TrimSpace(*input.Email)
+	}
+	if input.AvatarURL != nil {
+		params.AvatarUrl = optionalText(*input.AvatarURL)
+	}
+
+	return params
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// Orders of the campaign list of a user, each from the most recent down
+const (
+	CampaignSortUpdatedAt    = "updated_at"
+	CampaignSortLastAccessed = "last_accessed"
+)
+
+var userCampaignSorts = []string{CampaignSortUpdatedAt, CampaignSortLastAccessed}
+
+// UserCampaignFilter narrows down and orders the campaigns a user is a member of.
+// Archived lists the archived campaigns instead of the active ones.
+// Cursor is the next_cursor of the previous page, it only continues a listing with the same sort.
+type UserCampaignFilter struct {
+	UserID   uuid.UUID
+	Query    string
+	Role     string
+	Archived bool
+	Sort     string
+	Cursor   string
+	Limit    int
+}
+
+func (filter *UserCampaignFilter) Validate() error {
+	var validationErrors []string
+
+	if filter.Role != "" {
+		if _, err := ParseMemberRole(filter.Role); err != nil {
+			validationErrors = append(validationErrors, "role must be either gm or player")
+		}
+	}
+
+	if filter.Sort != "" && !slices.Contains(userCampaignSorts, filter.Sort) {
+		validationErrors = append(validationErrors, "sort must be one of "+strings.Join(userCampaignSorts, ", "))
+	}
+
+	if filter.Limit < 0 || filter.Limit > MaxCampaignPageSize {
+		validationErrors = append(validationErrors, fmt.Sprintf("limit must be between 1 and %d", MaxCampaignPageSize))
+	}
+
+	if filter.Cursor != "" {
+		if _, _, err := decodeCampaignCursor(filter.Cursor, filter.sort()); err != nil {
+			validationErrors = append(validationErrors, "cursor is invalid")
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return &utils.ValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
+
+// ToSqlcParams asks for one campaign more than the page holds, to tell whether there is a next page
+func (filter *UserCampaignFilter) ToSqlcParams() (sqlc.ListMemberCampaignsParams, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(filter.UserID)
+	if err != nil {
+		return sqlc.ListMemberCampaignsParams{}, err
+	}
+
+	params := sqlc.ListMemberCampaignsParams{
+		UserID:   userPGUUID,
+		Archived: filter.Archived,
+		Query:    optionalText(filter.Query),
+		Sort:     filter.sort(),
+		PageSize: int32(filter.limit() + 1),
+	}
+
+	if filter.Role != "" {
+		role, err := ParseMemberRole(filter.Role)
+		if err != nil {
+			return sqlc.ListMemberCampaignsParams{}, err
+		}
+		params.Role = sqlc.NullMemberRole{MemberRole: role, Valid: true}
+	}
+
+	if filter.Cursor != "" {
+		key, id, err := decodeCampaignCursor(filter.Cursor, params.Sort)
+		if err != nil {
+			return sqlc.ListMemberCampaignsParams{}, err
+		}
+		params.CursorKey = pgtype.Int8{Int64: key, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	return params, nil
+}
+
+func (filter *UserCampaignFilter) sort() string {
+	if filter.Sort == "" {
+		return CampaignSortUpdatedAt
+	}
+	return filter.Sort
+}
+
+func (filter *UserCampaignFilter) limit() int {
+	if filter.Limit == 0 {
+		return DefaultCampaignPageSize
+	}
+	return filter.Limit
+}
+
+// UserCampaign is a campaign as listed to one of its members, along with their role in it.
+// The setting and invite code are left out, they are fetched with the campaign itself.
+type UserCampaign struct {
+	ID             pgtype.UUID        `json:"id" swaggertype:"string" example:"0190b4a5-8c3e-7d8e-9f10-1a2b3c4d5e6f"`
+	Title          string             `json:"title"`
+	SettingSummary pgtype.Text        `json:"setting_summary" swaggertype:"string"`
+	ImageURL       pgtype.Text        `json:"image_url" swaggertype:"string"`
+	IsPublic       bool               `json:"is_public"`
+	Tags           []string           `json:"tags"`
+	GameSystem     pgtype.Text        `json:"game_system" swaggertype:"string"`
+	CreatedBy      pgtype.UUID        `json:"created_by" swaggertype:"string"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at" swaggertype:"string"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at" swaggertype:"string"`
+	ArchivedAt     pgtype.Timestamptz `json:"archived_at" swaggertype:"string"`
+	Role           sqlc.MemberRole    `json:"role" example:"gm"`
+	JoinedAt       pgtype.Timestamptz `json:"joined_at" swaggertype:"string"`
+	LastAccessed   pgtype.Timestamptz `json:"last_accessed" swaggertype:"string"`
+}
+
+// UserCampaignPage is a page of the campaigns of a user, NextCursor is empty on the last page
+type UserCampaignPage struct {
+	Campaigns  []UserCampaign `json:"campaigns"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// NewUserCampaignPage builds a page out of the rows fetched with the filter, which hold one row too many when
+// there is a next page
+func NewUserCampaignPage(rows []sqlc.ListMemberCampaignsRow, filter UserCampaignFilter) UserCampaignPage {
+	page := UserCampaignPage{Campaigns: make([]UserCampaign, 0, len(rows))}
+
+	if len(rows) > filter.limit() {
+		rows = rows[:filter.limit()]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeCampaignCursor(filter.sort(), last.SortKey, last.ID.Bytes)
+	}
+
+	for _, row := range rows {
+		page.Campaigns = append(page.Campaigns, UserCampaign{
+			ID:             row.ID,
+			Title:          row.Title,
+			SettingSummary: row.SettingSummary,
+			ImageURL:       row.ImageUrl,
+			IsPublic:       row.IsPublic,
+			Tags:           row.Tags,
+			GameSystem:     row.GameSystem,
+			CreatedBy:      row.CreatedBy,
+			CreatedAt:      row.CreatedAt,
+			UpdatedAt:      row.UpdatedAt,
+			ArchivedAt:     row.ArchivedAt,
+			Role:           row.Role,
+			JoinedAt:       row.JoinedAt,
+			LastAccessed:   row.LastAccessed,
+		})
+	}
+
+	return page
+}
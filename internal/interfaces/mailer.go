@@ -0,0 +1,7 @@
+package interfaces
+
+import "github.com/knands42/lorecrafter/internal/domain"
+
+type Mailer interface {
+	Send(message domain.EmailMessage) error
+}
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/knands42/lorecrafter/internal/domain"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
@@ -8,12 +9,38 @@ import (
 )
 
 type TokenMaker interface {
-	CreateToken(user sqlc.User, duration time.Duration) (string, time.Time, error)
+	CreateToken(user sqlc.User, sessionID uuid.UUID, scopes []string, duration time.Duration) (string, *domain.TokenPayload, error)
+	CreatePurposeToken(user sqlc.User, purpose string, scopes []string, duration time.Duration) (string, *domain.TokenPayload, error)
 	VerifyToken(token string) (*domain.TokenPayload, error)
+	VerifyPurposeToken(token, purpose string) (*domain.TokenPayload, error)
 	ParseUserID(payload *domain.TokenPayload) (uuid.UUID, error)
+	ParseTokenID(payload *domain.TokenPayload) (uuid.UUID, error)
 }
 
 type Argon2Hash interface {
 	HashPassword(password string) (string, error)
-	VerifyPassword(password, encodedHash string) (bool, error)
+	VerifyPassword(password, encodedHash string) (match bool, needsRehash bool, err error)
+}
+
+type TOTP interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret, accountName string) string
+	Validate(secret, code string, at time.Time) (int64, bool)
+}
+
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (domain.OIDCIdentity, error)
+}
+
+type WebAuthn interface {
+	BeginRegistration(user domain.PasskeyUser) (options []byte, session []byte, err error)
+	FinishRegistration(user domain.PasskeyUser, session, response []byte) (domain.PasskeyCredential, error)
+	BeginLogin() (options []byte, session []byte, err error)
+	FinishLogin(session, response []byte, findUser func(handle []byte) (domain.PasskeyUser, error)) (domain.PasskeyCredential, error)
 }
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrAPIKeyCreation = errors.New("error creating API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+const apiKeyBytes = 32
+
+// APIKeyUseCase implements the management of personal API keys
+type APIKeyUseCase struct {
+	ctx  context.Context
+	repo sqlc.Querier
+}
+
+// NewAPIKeyUseCase creates a new API key use case
+func NewAPIKeyUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+// CreateAPIKey creates an API key for the user. The key is only returned here, it is stored hashed.
+func (uc *APIKeyUseCase) CreateAPIKey(userID uuid.UUID, input domain.APIKeyInput) (domain.CreatedAPIKey, error) {
+	if err := input.Validate(); err != nil {
+		return domain.CreatedAPIKey{}, err
+	}
+
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return domain.CreatedAPIKey{}, err
+	}
+
+	secret, err := utils.GenerateSecureToken(apiKeyBytes)
+	if err != nil {
+		return domain.CreatedAPIKey{}, fmt.Errorf("error generating API key: %w", err)
+	}
+	key := domain.APIKeyPrefix + secret
+
+	createAPIKeyParams, err := input.ToSqlcParams(userPGUUID, key)
+	if err != nil {
+		return domain.CreatedAPIKey{}, err
+	}
+	apiKey, err := uc.repo.CreateAPIKey(uc.ctx, createAPIKeyParams)
+	if err != nil {
+		log.Printf("Error saving API key: %v", err)
+		return domain.CreatedAPIKey{}, ErrAPIKeyCreation
+	}
+
+	return domain.CreatedAPIKey{
+		APIKey: domain.NewAPIKey(apiKey),
+		Key:    key,
+	}, nil
+}
+
+// ListAPIKeys lists the API keys of the user that weren't revoked
+func (uc *APIKeyUseCase) ListAPIKeys(userID uuid.UUID) ([]domain.APIKey, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := uc.repo.ListAPIKeysByUser(uc.ctx, userPGUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.APIKey, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		result = append(result, domain.NewAPIKey(apiKey))
+	}
+
+	return result, nil
+}
+
+// RevokeAPIKey revokes an API key of the user, requests made with it are rejected right away
+func (uc *APIKeyUseCase) RevokeAPIKey(userID, apiKeyID uuid.UUID) error {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+	apiKeyPGUUID, err := utils.GeneratePGUUIDFromCustomId(apiKeyID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := uc.repo.RevokeAPIKey(uc.ctx, sqlc.RevokeAPIKeyParams{
+		ID:     apiKeyPGUUID,
+		UserID: userPGUUID,
+	})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,370 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor authentication enrollment was not started")
+	ErrInvalidMFACode    = errors.New("two-factor authentication code is invalid")
+	ErrInvalidMFAToken   = errors.New("two-factor login token is invalid or expired")
+	ErrMFANotConfigured  = errors.New("two-factor authentication is not configured")
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+// EnrollMFA starts enabling two-factor authentication by generating a new TOTP secret.
+// It only takes effect once a code from the authenticator app is confirmed with ConfirmMFA.
+func (uc *AuthUseCase) EnrollMFA(userID uuid.UUID) (domain.MFAEnrollment, error) {
+	if uc.secretCipher == nil {
+		return domain.MFAEnrollment{}, ErrMFANotConfigured
+	}
+
+	user, err := uc.getUser(userID)
+	if err != nil {
+		return domain.MFAEnrollment{}, err
+	}
+	if user.MfaEnabled {
+		return domain.MFAEnrollment{}, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := uc.totp.GenerateSecret()
+	if err != nil {
+		return domain.MFAEnrollment{}, fmt.Errorf("error generating MFA secret: %w", err)
+	}
+	encryptedSecret, err := uc.secretCipher.Encrypt(secret)
+	if err != nil {
+		return domain.MFAEnrollment{}, fmt.Errorf("error encrypting MFA secret: %w", err)
+	}
+
+	updated, err := uc.userRepo.SetUserMFASecret(uc.ctx, sqlc.SetUserMFASecretParams{
+		ID: user.ID,
+		MfaSecret: pgtype.Text{
+			String: encryptedSecret,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return domain.MFAEnrollment{}, err
+	}
+	if updated == 0 {
+		return domain.MFAEnrollment{}, ErrMFAAlreadyEnabled
+	}
+
+	return domain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: uc.totp.ProvisioningURI(secret, user.Username),
+	}, nil
+}
+
+// ConfirmMFA enables two-factor authentication once the user proves their authenticator app works,
+// returning the recovery codes that are never shown again
+func (uc *AuthUseCase) ConfirmMFA(userID uuid.UUID, input domain.MFACodeInput) (domain.MFARecoveryCodes, error) {
+	if err := input.Validate(); err != nil {
+		return domain.MFARecoveryCodes{}, err
+	}
+	if uc.secretCipher == nil {
+		return domain.MFARecoveryCodes{}, ErrMFANotConfigured
+	}
+
+	user, err := uc.getUser(userID)
+	if err != nil {
+		return domain.MFARecoveryCodes{}, err
+	}
+	if user.MfaEnabled {
+		return domain.MFARecoveryCodes{}, ErrMFAAlreadyEnabled
+	}
+	if !user.MfaSecret.Valid {
+		return domain.MFARecoveryCodes{}, ErrMFANotEnrolled
+	}
+
+	if err := uc.verifyTOTPCode(user, strings.TrimSpace(input.Code)); err != nil {
+		return domain.MFARecoveryCodes{}, err
+	}
+
+	recoveryCodes, err := uc.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return domain.MFARecoveryCodes{}, err
+	}
+
+	enabled, err := uc.userRepo.EnableUserMFA(uc.ctx, user.ID)
+	if err != nil {
+		return domain.MFARecoveryCodes{}, err
+	}
+	if enabled == 0 {
+		return domain.MFARecoveryCodes{}, ErrMFAAlreadyEnabled
+	}
+
+	return domain.MFARecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableMFA turns two-factor authentication off, which requires both the password and a code
+func (uc *AuthUseCase) DisableMFA(userID uuid.UUID, input domain.DisableMFAInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	user, err := uc.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.MfaEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if err := uc.verifyAccountPassword(user, input.Password); err != nil {
+		return err
+	}
+
+	if err := uc.verifyMFACode(user, input.Code); err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.DisableUserMFA(uc.ctx, user.ID); err != nil {
+		return err
+	}
+
+	return uc.userRepo.DeleteMFARecoveryCodes(uc.ctx, user.ID)
+}
+
+// LoginMFA completes the login of a user with two-factor authentication enabled.
+// The MFA token can only be exchanged once and wrong codes count as failed logins.
+func (uc *AuthUseCase) LoginMFA(input domain.MFALoginInput, client domain.SessionClient) (*domain.AuthOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	payload, err := uc.tokenMaker.VerifyPurposeToken(input.MFAToken, domain.TokenPurposeMFAPending)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	tokenID, err := uc.tokenMaker.ParseTokenID(payload)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	tokenPGUUID := pgtype.UUID{
+		Bytes: tokenID,
+		Valid: true,
+	}
+	revoked, err := uc.userRepo.IsTokenRevoked(uc.ctx, tokenPGUUID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidMFAToken
+	}
+
+	userID, err := uc.tokenMaker.ParseUserID(payload)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	user, err := uc.getUser(userID)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	if !user.MfaEnabled {
+		return nil, ErrInvalidMFAToken
+	}
+
+	accountKey, ipKey := loginThrottleKeys(user.Username, client.IPAddress)
+	if err := uc.checkLoginLock(accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
+	if err := uc.verifyMFACode(user, input.Code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			uc.recordLoginFailure(accountKey, ipKey)
+		}
+		return nil, err
+	}
+
+	err = uc.userRepo.RevokeToken(uc.ctx, sqlc.RevokeTokenParams{
+		TokenID: tokenPGUUID,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  payload.ExpiresAt,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.completeLogin(user, accountKey, domain.SessionScopes(payload.Scopes), client)
+}
+
+// createMFAChallenge creates the short-lived token proving the password step of a two-factor login.
+// It carries the scopes the session was asked for until the login is completed.
+func (uc *AuthUseCase) createMFAChallenge(user sqlc.User, scopes []string) (*domain.MFAChallenge, error) {
+	token, payload, err := uc.tokenMaker.CreatePurposeToken(user, domain.TokenPurposeMFAPending, scopes, uc.mfaPendingExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("error generating MFA token: %w", err)
+	}
+
+	return &domain.MFAChallenge{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresAt:   payload.ExpiresAt,
+	}, nil
+}
+
+// verifyMFACode accepts either a code from the authenticator app or an unused recovery code
+func (uc *AuthUseCase) verifyMFACode(user sqlc.User, code string) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return uc.verifyTOTPCode(user, code)
+	}
+
+	return uc.useRecoveryCode(user, code)
+}
+
+// verifyTOTPCode checks a code against the secret of the user, a code can only be used once.
+// Without a cipher the secrets can't be read, only the recovery codes keep working.
+func (uc *AuthUseCase) verifyTOTPCode(user sqlc.User, code string) error {
+	if uc.secretCipher == nil {
+		return ErrMFANotConfigured
+	}
+
+	secret, err := uc.secretCipher.Decrypt(user.MfaSecret.String)
+	if err != nil {
+		return fmt.Errorf("error decrypting MFA secret: %w", err)
+	}
+
+	step, valid := uc.totp.Validate(secret, code, time.Now())
+	if !valid {
+		return ErrInvalidMFACode
+	}
+
+	claimed, err := uc.userRepo.ClaimMFAStep(uc.ctx, sqlc.ClaimMFAStepParams{
+		ID:   user.ID,
+		Step: step,
+	})
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// useRecoveryCode marks the matching recovery code of the user as used
+func (uc *AuthUseCase) useRecoveryCode(user sqlc.User, code string) error {
+	code = normalizeRecoveryCode(code)
+	if len(code) != recoveryCodeLength {
+		return ErrInvalidMFACode
+	}
+
+	recoveryCodes, err := uc.userRepo.ListUnusedMFARecoveryCodes(uc.ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, recoveryCode := range recoveryCodes {
+		match, _, err := uc.argon2Hash.VerifyPassword(code, recoveryCode.CodeHash)
+		if err != nil {
+			log.Printf("error verifying recovery code: %v", err)
+			continue
+		}
+		if !match {
+			continue
+		}
+
+		used, err := uc.userRepo.UseMFARecoveryCode(uc.ctx, recoveryCode.ID)
+		if err != nil {
+			return err
+		}
+		if used == 0 {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	return ErrInvalidMFACode
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes, invalidating the previous ones
+func (uc *AuthUseCase) replaceRecoveryCodes(userID pgtype.UUID) ([]string, error) {
+	if err := uc.userRepo.DeleteMFARecoveryCodes(uc.ctx, userID); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := utils.GenerateRandomString(recoveryCodeLength, utils.InviteCodeAlphabet)
+		if err != nil {
+			return nil, fmt.Errorf("error generating recovery code: %w", err)
+		}
+		codeHash, err := uc.argon2Hash.HashPassword(code)
+		if err != nil {
+			log.Printf("Error hashing recovery code: %v", err)
+			return nil, ErrHashPassword
+		}
+		recoveryCodeID, err := utils.GeneratePGUUID()
+		if err != nil {
+			return nil, err
+		}
+
+		err = uc.userRepo.CreateMFARecoveryCode(uc.ctx, sqlc.CreateMFARecoveryCodeParams{
+			ID:       recoveryCodeID,
+			UserID:   userID,
+			CodeHash: codeHash,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+	}
+
+	return recoveryCodes, nil
+}
+
+// getUser fetches a user by ID
+func (uc *AuthUseCase) getUser(userID uuid.UUID) (sqlc.User, error) {
+	user, err := uc.userRepo.GetUserByID(uc.ctx, pgtype.UUID{
+		Bytes: userID,
+		Valid: true,
+	})
+	if err != nil {
+		return sqlc.User{}, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// isTOTPCode reports whether a code looks like it comes from an authenticator app rather than a recovery code
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, char := range code {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeRecoveryCode makes recovery codes case insensitive and ignores separators
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
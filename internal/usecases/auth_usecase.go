@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/knands42/lorecrafter/internal/interfaces"
+	"github.com/knands42/lorecrafter/internal/utils"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/knands42/lorecrafter/internal/domain"
@@ -19,14 +24,47 @@ var (
 	ErrCreateUser         = errors.New("error creating user")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrHashPassword       = errors.New("error hashing password")
+
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+	ErrRevokedToken        = errors.New("token has been revoked")
+
+	ErrInvalidVerificationToken = errors.New("verification token is invalid or expired")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationThrottled    = errors.New("a verification email was sent recently")
+	ErrSendingVerification      = errors.New("error sending verification email")
+
+	ErrInvalidPasswordResetToken = errors.New("password reset token is invalid, expired or already used")
+	ErrIncorrectPassword         = errors.New("current password is incorrect")
+)
+
+const (
+	refreshTokenBytes       = 32
+	passwordResetTokenBytes = 32
+	// How often the last use of an API key or the last request of a session is recorded
+	apiKeyUsageInterval     = time.Minute
+	sessionActivityInterval = time.Minute
 )
 
 type AuthUseCase struct {
-	ctx         context.Context
-	userRepo    sqlc.Querier
-	tokenMaker  interfaces.TokenMaker
-	argon2Hash  interfaces.Argon2Hash
-	tokenExpiry time.Duration
+	ctx                         context.Context
+	userRepo                    sqlc.Querier
+	tokenMaker                  interfaces.TokenMaker
+	argon2Hash                  interfaces.Argon2Hash
+	mailer                      interfaces.Mailer
+	totp                        interfaces.TOTP
+	secretCipher                interfaces.SecretCipher
+	appBaseURL                  string
+	tokenExpiry                 time.Duration
+	refreshTokenExpiry          time.Duration
+	verificationExpiry          time.Duration
+	verificationResendInterval  time.Duration
+	passwordResetExpiry         time.Duration
+	passwordResetResendInterval time.Duration
+	mfaPendingExpiry            time.Duration
+	loginThrottle               LoginThrottleSettings
+	// Checked against the passwords of unknown users so their logins take as long as the others
+	dummyPasswordHash string
 }
 
 func NewAuthUseCase(
@@ -34,19 +72,48 @@ func NewAuthUseCase(
 	userRepo sqlc.Querier,
 	tokenMaker interfaces.TokenMaker,
 	argon2Hash interfaces.Argon2Hash,
+	mailer interfaces.Mailer,
+	totp interfaces.TOTP,
+	secretCipher interfaces.SecretCipher,
+	appBaseURL string,
 	tokenExpiry time.Duration,
-) *AuthUseCase {
-	return &AuthUseCase{
-		ctx:         ctx,
-		userRepo:    userRepo,
-		tokenMaker:  tokenMaker,
-		argon2Hash:  argon2Hash,
-		tokenExpiry: tokenExpiry,
+	refreshTokenExpiry time.Duration,
+	verificationExpiry time.Duration,
+	verificationResendInterval time.Duration,
+	passwordResetExpiry time.Duration,
+	passwordResetResendInterval time.Duration,
+	mfaPendingExpiry time.Duration,
+	loginThrottle LoginThrottleSettings,
+) (*AuthUseCase, error) {
+	// Without it logins of unknown users would fail differently and reveal which usernames exist
+	dummyPasswordHash, err := argon2Hash.HashPassword("lorecrafter-dummy-password")
+	if err != nil {
+		return nil, fmt.Errorf("error hashing dummy password: %w", err)
 	}
+
+	return &AuthUseCase{
+		ctx:                         ctx,
+		userRepo:                    userRepo,
+		tokenMaker:                  tokenMaker,
+		argon2Hash:                  argon2Hash,
+		mailer:                      mailer,
+		totp:                        totp,
+		secretCipher:                secretCipher,
+		appBaseURL:                  strings.TrimRight(appBaseURL, "/"),
+		tokenExpiry:                 tokenExpiry,
+		refreshTokenExpiry:          refreshTokenExpiry,
+		verificationExpiry:          verificationExpiry,
+		verificationResendInterval:  verificationResendInterval,
+		passwordResetExpiry:         passwordResetExpiry,
+		passwordResetResendInterval: passwordResetResendInterval,
+		mfaPendingExpiry:            mfaPendingExpiry,
+		loginThrottle:               loginThrottle,
+		dummyPasswordHash:           dummyPasswordHash,
+	}, nil
 }
 
-// Register creates a new user and generates a token for them
-func (uc *AuthUseCase) Register(input domain.UserCreationInput) (*domain.AuthOutput, error) {
+// Register creates a new user, emails them a verification link and starts a session for them on the client
+func (uc *AuthUseCase) Register(input domain.UserCreationInput, client domain.SessionClient) (*domain.AuthOutput, error) {
 	validationErrors := input.Validate()
 	if validationErrors != nil {
 		log.Printf("validation errors: %v", validationErrors)
@@ -80,58 +147,767 @@ func (uc *AuthUseCase) Register(input domain.UserCreationInput) (*domain.AuthOut
 		return nil, ErrCreateUser
 	}
 
-	// Generate a token
-	token, expiresAt, err := uc.tokenMaker.CreateToken(createdUser, uc.tokenExpiry)
-	if err != nil {
-		return nil, fmt.Errorf("error generating token: %w", err)
+	// The user can ask for another verification email if this one gets lost
+	if err := uc.sendVerificationEmail(createdUser); err != nil {
+		log.Printf("Error sending verification email: %v", err)
 	}
 
-	return &domain.AuthOutput{
-		User:      createdUser,
-		Token:     token,
-		ExpiresAt: expiresAt,
-	}, nil
+	// Generate the tokens of a new session
+	return uc.startSession(createdUser, domain.AllScopes, client)
 }
 
-// Login authenticates a user and generates a token for them
-func (uc *AuthUseCase) Login(req domain.LoginInput) (*domain.AuthOutput, error) {
+// Login authenticates a user and generates a token for them.
+// Users with two-factor authentication enabled get an MFA challenge instead, to be completed with LoginMFA.
+// Failed attempts are counted per account and per IP address, both get locked out for a while after too many.
+func (uc *AuthUseCase) Login(req domain.LoginInput, client domain.SessionClient) (*domain.AuthOutput, *domain.MFAChallenge, error) {
 	// Validate the input
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	accountKey, ipKey := loginThrottleKeys(req.Username, client.IPAddress)
+	if err := uc.checkLoginLock(accountKey, ipKey); err != nil {
+		return nil, nil, err
 	}
 
-	// Get the user by username
+	// Get the user by username, unknown users still go through a password check so the response time doesn't tell them apart
+	passwordHash := uc.dummyPasswordHash
 	user, err := uc.userRepo.GetUserByUsername(uc.ctx, req.Username)
-	if err != nil {
-		log.Printf("error getting user: %v", err)
-		return nil, ErrUserNotFound
+	if err == nil {
+		passwordHash = user.HashedPassword
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, err
 	}
 
 	// Verify the password
-	match, err := uc.argon2Hash.VerifyPassword(req.Password, user.HashedPassword)
+	match, needsRehash, err := uc.argon2Hash.VerifyPassword(req.Password, passwordHash)
 	if err != nil {
 		log.Printf("error verifying password: %v", err)
-		return nil, ErrCheckingPassword
+		return nil, nil, ErrCheckingPassword
 	}
 
-	if !match {
-		return nil, ErrInvalidCredentials
+	if !match || !user.ID.Valid {
+		uc.recordLoginFailure(accountKey, ipKey)
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		uc.rehashPassword(user, req.Password)
+	}
+
+	scopes := domain.SessionScopes(req.Scopes)
+	if user.MfaEnabled {
+		challenge, err := uc.createMFAChallenge(user, scopes)
+		return nil, challenge, err
+	}
+
+	// Generate the tokens of a new session
+	output, err := uc.completeLogin(user, accountKey, scopes, client)
+	return output, nil, err
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Presenting a refresh token that was already exchanged revokes its whole family, since either the
+// legitimate client or an attacker is holding a stolen copy.
+func (uc *AuthUseCase) Refresh(input domain.RefreshTokenInput) (*domain.AuthOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := uc.userRepo.GetRefreshTokenByHash(uc.ctx, utils.HashToken(input.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if refreshToken.UsedAt.Valid || refreshToken.RevokedAt.Valid {
+		return nil, uc.revokeReusedFamily(refreshToken)
 	}
 
-	// Generate a token
-	token, expiresAt, err := uc.tokenMaker.CreateToken(user, uc.tokenExpiry)
+	if !refreshToken.ExpiresAt.Time.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// A concurrent refresh with the same token already won the rotation
+	rotated, err := uc.userRepo.MarkRefreshTokenUsed(uc.ctx, refreshToken.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rotated == 0 {
+		return nil, uc.revokeReusedFamily(refreshToken)
+	}
+
+	user, err := uc.userRepo.GetUserByID(uc.ctx, refreshToken.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// The new pair keeps the scopes the session started with
+	return uc.issueTokens(user, refreshToken.FamilyID, refreshToken.Scopes)
+}
+
+// Logout revokes the access token or API key of the request and, when given, the session of the refresh token
+func (uc *AuthUseCase) Logout(payload *domain.TokenPayload, input domain.RefreshTokenInput) error {
+	if err := uc.revokeRequestCredential(payload); err != nil {
+		return err
+	}
+
+	if input.RefreshToken != "" {
+		userID, err := uc.tokenMaker.ParseUserID(payload)
+		if err != nil {
+			return err
+		}
+
+		refreshToken, err := uc.userRepo.GetRefreshTokenByHash(uc.ctx, utils.HashToken(input.RefreshToken))
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// Nothing left to revoke
+		case err != nil:
+			return err
+		// Refresh tokens of other users are ignored so logging out can't end someone else's session
+		case refreshToken.UserID.Bytes == userID:
+			if err := uc.revokeFamily(refreshToken.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := uc.userRepo.DeleteExpiredTokens(uc.ctx); err != nil {
+		log.Printf("error deleting expired tokens: %v", err)
+	}
+	if err := uc.userRepo.DeleteExpiredSessions(uc.ctx); err != nil {
+		log.Printf("error deleting expired sessions: %v", err)
+	}
+
+	return nil
+}
+
+// VerifyEmail activates the account a verification token was issued for
+func (uc *AuthUseCase) VerifyEmail(input domain.VerifyEmailInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	payload, err := uc.tokenMaker.VerifyPurposeToken(input.Token, domain.TokenPurposeEmailVerification)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	userID, err := uc.tokenMaker.ParseUserID(payload)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := uc.userRepo.GetUserByID(uc.ctx, pgtype.UUID{
+		Bytes: userID,
+		Valid: true,
+	})
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	// Links sent to a previous address must not verify the current one
+	if !strings.EqualFold(payload.Email, user.Email) {
+		return ErrInvalidVerificationToken
+	}
+	if user.IsActive {
+		return nil
+	}
+
+	_, err = uc.userRepo.ActivateUser(uc.ctx, user.ID)
+	return err
+}
+
+// ResendVerification emails a new verification link, at most once per resend interval
+func (uc *AuthUseCase) ResendVerification(userID uuid.UUID) error {
+	user, err := uc.userRepo.GetUserByID(uc.ctx, pgtype.UUID{
+		Bytes: userID,
+		Valid: true,
+	})
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.IsActive {
+		return ErrEmailAlreadyVerified
+	}
+
+	if err := uc.sendVerificationEmail(user); err != nil {
+		if errors.Is(err, ErrVerificationThrottled) {
+			return err
+		}
+		log.Printf("Error sending verification email: %v", err)
+		return ErrSendingVerification
+	}
+
+	return nil
+}
+
+// VerificationResendInterval is the minimum time between two verification emails
+func (uc *AuthUseCase) VerificationResendInterval() time.Duration {
+	return uc.verificationResendInterval
+}
+
+// ForgotPassword emails a password reset link if the email belongs to an account.
+// The outcome is never reported back, so the endpoint can't be used to find out which emails are registered.
+func (uc *AuthUseCase) ForgotPassword(input domain.ForgotPasswordInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	// Done in the background so the response time doesn't reveal whether the account exists either
+	go uc.sendPasswordResetEmail(strings.TrimSpace(input.Email))
+
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token, ends every session of the user and revokes their API keys
+func (uc *AuthUseCase) ResetPassword(input domain.ResetPasswordInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	resetToken, err := uc.userRepo.ConsumePasswordResetToken(uc.ctx, utils.HashToken(input.Token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidPasswordResetToken
+		}
+		return err
+	}
+
+	if err := uc.updatePassword(resetToken.UserID, input.NewPassword); err != nil {
+		return err
+	}
+
+	if err := uc.revokeUserSessions(resetToken.UserID, pgtype.UUID{}); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password may have created keys with it
+	return uc.userRepo.RevokeUserAPIKeys(uc.ctx, resetToken.UserID)
+}
+
+// ChangePassword replaces the password of the logged user and ends every other session.
+// Their API keys are revoked too when the input asks for it.
+func (uc *AuthUseCase) ChangePassword(payload *domain.TokenPayload, input domain.ChangePasswordInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	userID, err := uc.tokenMaker.ParseUserID(payload)
+	if err != nil {
+		return err
+	}
+	user, err := uc.userRepo.GetUserByID(uc.ctx, pgtype.UUID{
+		Bytes: userID,
+		Valid: true,
+	})
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := uc.verifyAccountPassword(user, input.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := uc.updatePassword(user.ID, input.NewPassword); err != nil {
+		return err
+	}
+
+	// Keep the session the request was made from
+	tokenID, err := uc.tokenMaker.ParseTokenID(payload)
+	if err != nil {
+		return err
+	}
+	var currentFamilyID pgtype.UUID
+	currentRefreshToken, err := uc.userRepo.GetRefreshTokenByAccessTokenID(uc.ctx, pgtype.UUID{
+		Bytes: tokenID,
+		Valid: true,
+	})
+	if err == nil {
+		currentFamilyID = currentRefreshToken.FamilyID
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if err := uc.revokeUserSessions(user.ID, currentFamilyID); err != nil {
+		return err
+	}
+
+	if !input.RevokeAPIKeys {
+		return nil
+	}
+	return uc.userRepo.RevokeUserAPIKeys(uc.ctx, user.ID)
+}
+
+// revokeRequestCredential revokes what the request was authenticated with.
+// API keys aren't checked against the revoked tokens, so they are revoked like from the API key list.
+func (uc *AuthUseCase) revokeRequestCredential(payload *domain.TokenPayload) error {
+	tokenID, err := uc.tokenMaker.ParseTokenID(payload)
+	if err != nil {
+		return err
+	}
+
+	if payload.Purpose == domain.TokenPurposeAPIKey {
+		userID, err := uc.tokenMaker.ParseUserID(payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = uc.userRepo.RevokeAPIKey(uc.ctx, sqlc.RevokeAPIKeyParams{
+			ID: pgtype.UUID{
+				Bytes: tokenID,
+				Valid: true,
+			},
+			UserID: pgtype.UUID{
+				Bytes: userID,
+				Valid: true,
+			},
+		})
+		return err
+	}
+
+	return uc.userRepo.RevokeToken(uc.ctx, sqlc.RevokeTokenParams{
+		TokenID: pgtype.UUID{
+			Bytes: tokenID,
+			Valid: true,
+		},
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  payload.ExpiresAt,
+			Valid: true,
+		},
+	})
+}
+
+// VerifyToken verifies a token, rejecting the ones that were revoked, and returns the payload
+func (uc *AuthUseCase) VerifyToken(token string) (*domain.TokenPayload, error) {
+	if strings.HasPrefix(token, domain.APIKeyPrefix) {
+		return uc.verifyAPIKey(token)
+	}
+
+	payload, err := uc.tokenMaker.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := uc.tokenMaker.ParseTokenID(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := uc.userRepo.IsTokenRevoked(uc.ctx, pgtype.UUID{
+		Bytes: tokenID,
+		Valid: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrRevokedToken
+	}
+
+	// Access tokens stop working as soon as their session is revoked
+	if payload.SessionID != "" {
+		if err := uc.checkSession(payload.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
+
+// checkSession rejects sessions that were revoked or expired and records the activity of the others
+func (uc *AuthUseCase) checkSession(sessionID string) error {
+	parsedSessionID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrRevokedToken
+	}
+	sessionPGUUID := pgtype.UUID{
+		Bytes: parsedSessionID,
+		Valid: true,
+	}
+
+	if _, err := uc.userRepo.GetActiveSession(uc.ctx, sessionPGUUID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRevokedToken
+		}
+		return err
+	}
+
+	err = uc.userRepo.TouchSession(uc.ctx, sqlc.TouchSessionParams{
+		ID: sessionPGUUID,
+		SeenBefore: pgtype.Timestamptz{
+			Time:  time.Now().Add(-sessionActivityInterval),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Error recording session activity: %v", err)
+	}
+
+	return nil
+}
+
+// verifyAPIKey checks an API key and describes it as a token payload carrying its scopes
+func (uc *AuthUseCase) verifyAPIKey(key string) (*domain.TokenPayload, error) {
+	apiKey, err := uc.userRepo.GetActiveAPIKeyByHash(uc.ctx, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRevokedToken
+		}
+		return nil, err
+	}
+
+	err = uc.userRepo.TouchAPIKey(uc.ctx, sqlc.TouchAPIKeyParams{
+		ID: apiKey.ID,
+		UsedBefore: pgtype.Timestamptz{
+			Time:  time.Now().Add(-apiKeyUsageInterval),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Error recording API key usage: %v", err)
+	}
+
+	keyID, err := utils.FromPGTypeUUID(apiKey.ID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := utils.FromPGTypeUUID(apiKey.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPayload{
+		ID:        keyID.String(),
+		Purpose:   domain.TokenPurposeAPIKey,
+		UserID:    userID.String(),
+		Username:  apiKey.Username,
+		Email:     apiKey.Email,
+		Scopes:    apiKey.Scopes,
+		IssuedAt:  apiKey.CreatedAt.Time,
+		ExpiresAt: apiKey.ExpiresAt.Time,
+	}, nil
+}
+
+// completeLogin records the login of a user and starts a new session with the given scopes on the client.
+// Logging in cancels the deletion of an account still in its grace period.
+func (uc *AuthUseCase) completeLogin(user sqlc.User, accountKey string, scopes []string, client domain.SessionClient) (*domain.AuthOutput, error) {
+	uc.clearLoginFailures(accountKey)
+	if err := uc.userRepo.UpdateUserLastLogin(uc.ctx, user.ID); err != nil {
+		log.Printf("Error updating last login: %v", err)
+	}
+	if user.DeletionScheduledAt.Valid {
+		if err := uc.userRepo.CancelUserDeletion(uc.ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("error cancelling account deletion: %w", err)
+		}
+	}
+
+	return uc.startSession(user, scopes, client)
+}
+
+// startSession records a new session of the user on the client and issues its first tokens
+func (uc *AuthUseCase) startSession(user sqlc.User, scopes []string, client domain.SessionClient) (*domain.AuthOutput, error) {
+	sessionID, err := utils.GeneratePGUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = uc.userRepo.CreateSession(uc.ctx, sqlc.CreateSessionParams{
+		ID:        sessionID,
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IpAddress: client.IPAddress,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(uc.refreshTokenExpiry),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving session: %w", err)
+	}
+
+	return uc.issueTokens(user, sessionID, scopes)
+}
+
+// issueTokens creates an access token and a refresh token of the session with the given scopes for the user.
+// The refresh token joins the family of the session, which lives on as long as it.
+func (uc *AuthUseCase) issueTokens(user sqlc.User, familyID pgtype.UUID, scopes []string) (*domain.AuthOutput, error) {
+	token, payload, err := uc.tokenMaker.CreateToken(user, familyID.Bytes, scopes, uc.tokenExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("error generating token: %w", err)
+	}
+	tokenID, err := uc.tokenMaker.ParseTokenID(payload)
 	if err != nil {
 		return nil, fmt.Errorf("error generating token: %w", err)
 	}
 
+	refreshTokenID, err := utils.GeneratePGUUID()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := utils.GenerateSecureToken(refreshTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error generating refresh token: %w", err)
+	}
+
+	refreshTokenExpiresAt := time.Now().Add(uc.refreshTokenExpiry)
+	_, err = uc.userRepo.CreateRefreshToken(uc.ctx, sqlc.CreateRefreshTokenParams{
+		ID:        refreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(refreshToken),
+		AccessTokenID: pgtype.UUID{
+			Bytes: tokenID,
+			Valid: true,
+		},
+		AccessTokenExpiresAt: pgtype.Timestamptz{
+			Time:  payload.ExpiresAt,
+			Valid: true,
+		},
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  refreshTokenExpiresAt,
+			Valid: true,
+		},
+		Scopes: scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving refresh token: %w", err)
+	}
+
+	err = uc.userRepo.ExtendSession(uc.ctx, sqlc.ExtendSessionParams{
+		ID: familyID,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  refreshTokenExpiresAt,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error extending session: %w", err)
+	}
+
 	return &domain.AuthOutput{
-		User:      user,
-		Token:     token,
-		ExpiresAt: expiresAt,
+		User:                  domain.NewUserProfile(user),
+		Token:                 token,
+		ExpiresAt:             payload.ExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshTokenExpiresAt,
 	}, nil
 }
 
-// VerifyToken verifies a token and returns the payload
-func (uc *AuthUseCase) VerifyToken(token string) (*domain.TokenPayload, error) {
-	return uc.tokenMaker.VerifyToken(token)
+// sendVerificationEmail emails a verification link unless one was sent within the resend interval
+func (uc *AuthUseCase) sendVerificationEmail(user sqlc.User) error {
+	claimed, err := uc.userRepo.MarkVerificationSent(uc.ctx, sqlc.MarkVerificationSentParams{
+		ID: user.ID,
+		ThrottleBefore: pgtype.Timestamptz{
+			Time:  time.Now().Add(-uc.verificationResendInterval),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return ErrVerificationThrottled
+	}
+
+	token, payload, err := uc.tokenMaker.CreatePurposeToken(user, domain.TokenPurposeEmailVerification, nil, uc.verificationExpiry)
+	if err != nil {
+		return fmt.Errorf("error generating verification token: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/verify-email/%s", uc.appBaseURL, token)
+	return uc.mailer.Send(domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Verify your LoreCrafter email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n"+
+				"Open the link below to verify your email address and activate your LoreCrafter account:\n%s\n\n"+
+				"This link expires on %s. If you didn't create an account, you can ignore this email.\n",
+			user.Username,
+			link,
+			payload.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	})
+}
+
+// sendPasswordResetEmail replaces the pending reset tokens of the account with the email and sends a reset link,
+// at most once per resend interval
+func (uc *AuthUseCase) sendPasswordResetEmail(email string) {
+	user, err := uc.userRepo.GetUserByEmail(uc.ctx, email)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Error getting user for password reset: %v", err)
+		}
+		return
+	}
+
+	claimed, err := uc.userRepo.MarkPasswordResetSent(uc.ctx, sqlc.MarkPasswordResetSentParams{
+		ID: user.ID,
+		ThrottleBefore: pgtype.Timestamptz{
+			Time:  time.Now().Add(-uc.passwordResetResendInterval),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Error claiming password reset email: %v", err)
+		return
+	}
+	// The link sent a moment ago is still valid, so the request is ignored
+	if claimed == 0 {
+		return
+	}
+
+	if err := uc.userRepo.DeletePasswordResetTokensByUser(uc.ctx, user.ID); err != nil {
+		log.Printf("Error deleting password reset tokens: %v", err)
+		return
+	}
+
+	resetTokenID, err := utils.GeneratePGUUID()
+	if err != nil {
+		log.Printf("Error generating password reset token ID: %v", err)
+		return
+	}
+	token, err := utils.GenerateSecureToken(passwordResetTokenBytes)
+	if err != nil {
+		log.Printf("Error generating password reset token: %v", err)
+		return
+	}
+
+	resetToken, err := uc.userRepo.CreatePasswordResetToken(uc.ctx, sqlc.CreatePasswordResetTokenParams{
+		ID:        resetTokenID,
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(uc.passwordResetExpiry),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Error saving password reset token: %v", err)
+		return
+	}
+
+	link := fmt.Sprintf("%s/reset-password/%s", uc.appBaseURL, token)
+	err = uc.mailer.Send(domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your LoreCrafter password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n"+
+				"Someone asked to reset the password of your LoreCrafter account. Open the link below to choose a new one:\n%s\n\n"+
+				"This link can be used once and expires on %s. If you didn't ask for it, you can ignore this email.\n",
+			user.Username,
+			link,
+			resetToken.ExpiresAt.Time.UTC().Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		log.Printf("Error sending password reset email: %v", err)
+	}
+}
+
+// verifyAccountPassword confirms the password of a logged user before a sensitive change.
+// Wrong passwords count towards the lockout of the account, the same as failed logins.
+func (uc *AuthUseCase) verifyAccountPassword(user sqlc.User, password string) error {
+	accountKey, _ := loginThrottleKeys(user.Username, "")
+	if err := uc.checkLoginLock(accountKey); err != nil {
+		return err
+	}
+
+	match, _, err := uc.argon2Hash.VerifyPassword(password, user.HashedPassword)
+	if err != nil {
+		log.Printf("error verifying password: %v", err)
+		return ErrCheckingPassword
+	}
+	if !match {
+		uc.recordThrottleFailure(accountKey, uc.loginThrottle.MaxAccountAttempts)
+		return ErrIncorrectPassword
+	}
+
+	return nil
+}
+
+// updatePassword hashes and stores a new password, dropping the reset tokens that are still pending
+func (uc *AuthUseCase) updatePassword(userID pgtype.UUID, password string) error {
+	hashedPassword, err := uc.argon2Hash.HashPassword(password)
+	if err != nil {
+		log.Printf("Error hashing password: %v", err)
+		return ErrHashPassword
+	}
+
+	err = uc.userRepo.UpdateUserPassword(uc.ctx, sqlc.UpdateUserPasswordParams{
+		ID:             userID,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.userRepo.DeletePasswordResetTokensByUser(uc.ctx, userID)
+}
+
+// rehashPassword upgrades the hash of a password made with older Argon2 parameters.
+// The login goes on with the old hash if this fails, it is tried again on the next one.
+func (uc *AuthUseCase) rehashPassword(user sqlc.User, password string) {
+	hashedPassword, err := uc.argon2Hash.HashPassword(password)
+	if err != nil {
+		log.Printf("Error rehashing password: %v", err)
+		return
+	}
+
+	err = uc.userRepo.RehashUserPassword(uc.ctx, sqlc.RehashUserPasswordParams{
+		ID:                     user.ID,
+		HashedPassword:         hashedPassword,
+		PreviousHashedPassword: user.HashedPassword,
+	})
+	if err != nil {
+		log.Printf("Error saving rehashed password: %v", err)
+	}
+}
+
+// revokeUserSessions revokes every session of a user and their tokens except the one of keepFamilyID, when valid
+func (uc *AuthUseCase) revokeUserSessions(userID, keepFamilyID pgtype.UUID) error {
+	err := uc.userRepo.RevokeUserSessions(uc.ctx, sqlc.RevokeUserSessionsParams{
+		UserID: userID,
+		KeepID: keepFamilyID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = uc.userRepo.RevokeUserAccessTokens(uc.ctx, sqlc.RevokeUserAccessTokensParams{
+		UserID:       userID,
+		KeepFamilyID: keepFamilyID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.userRepo.RevokeUserRefreshTokens(uc.ctx, sqlc.RevokeUserRefreshTokensParams{
+		UserID:       userID,
+		KeepFamilyID: keepFamilyID,
+	})
+}
+
+// revokeReusedFamily ends the session of a refresh token that was presented twice
+func (uc *AuthUseCase) revokeReusedFamily(refreshToken sqlc.RefreshToken) error {
+	log.Printf("refresh token reuse detected for user %s, revoking its session", uuid.UUID(refreshToken.UserID.Bytes))
+
+	if err := uc.revokeFamily(refreshToken.FamilyID); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+// revokeFamily revokes the session of a family, every refresh token of it and the access tokens issued with them
+func (uc *AuthUseCase) revokeFamily(familyID pgtype.UUID) error {
+	if err := uc.userRepo.RevokeSession(uc.ctx, familyID); err != nil {
+		return err
+	}
+	if err := uc.userRepo.RevokeRefreshTokenFamilyAccessTokens(uc.ctx, familyID); err != nil {
+		return err
+	}
+
+	return uc.userRepo.RevokeRefreshTokenFamily(uc.ctx, familyID)
 }
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// loadCampaignMember fetches the membership of a user in a campaign and checks it against the required role.
+// Non-members get ErrCampaignNotFound for campaigns they cannot see and ErrInsufficientPermissions for public ones,
+// so private campaigns never leak their existence.
+func loadCampaignMember(
+	ctx context.Context,
+	repo sqlc.Querier,
+	campaignID, userID pgtype.UUID,
+	requiredRole sqlc.MemberRole,
+) (sqlc.CampaignMember, error) {
+	member, err := repo.GetCampaignMember(ctx, sqlc.GetCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = repo.GetCampaignByID(ctx, sqlc.GetCampaignByIDParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.CampaignMember{}, ErrCampaignNotFound
+		}
+		if err != nil {
+			return sqlc.CampaignMember{}, err
+		}
+		return sqlc.CampaignMember{}, ErrInsufficientPermissions
+	}
+	if err != nil {
+		return sqlc.CampaignMember{}, err
+	}
+
+	if !domain.HasPermission(member.Role, requiredRole) {
+		return sqlc.CampaignMember{}, ErrInsufficientPermissions
+	}
+
+	return member, nil
+}
+
+// ensureEmailVerified rejects users who haven't verified their email address yet when the policy requires it
+func ensureEmailVerified(ctx context.Context, repo sqlc.Querier, userID pgtype.UUID, required bool) error {
+	if !required {
+		return nil
+	}
+
+	user, err := repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !user.IsActive {
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/knands42/lorecrafter/internal/domain"
 	"github.com/knands42/lorecrafter/internal/utils"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
 	"log"
+	"strings"
 )
 
 var (
@@ -15,22 +18,41 @@ var (
 	ErrCampaignNotFound        = errors.New("campaign not found")
 	ErrCampaignMemberCreation  = errors.New("error creating campaign member")
 	ErrInsufficientPermissions = errors.New("insufficient permissions")
+	ErrEmailNotVerified        = errors.New("email address is not verified")
+
+	ErrCampaignMemberNotFound      = errors.New("campaign member not found")
+	ErrCampaignMemberAlreadyExists = errors.New("user is already a campaign member")
+	ErrLastGameMaster              = errors.New("campaign must keep at least one gm")
+
+	ErrCampaignVersionMismatch = errors.New("campaign was modified since it was fetched")
+
+	ErrInviteCodeNotFound = errors.New("invite code not found")
+	ErrInviteCodeExpired  = errors.New("invite code has expired or reached its usage limit")
+)
+
+const (
+	inviteCodeLength            = 8
+	inviteCodeGenerationRetries = 3
 )
 
 // CampaignUseCase implements the campaign business logic
 type CampaignUseCase struct {
-	ctx  context.Context
-	repo sqlc.Querier
+	ctx                       context.Context
+	repo                      sqlc.Querier
+	emailVerificationRequired bool
 }
 
-// NewCampaignUseCase creates a new campaign use case
+// NewCampaignUseCase creates a new campaign use case.
+// When emailVerificationRequired is set, users must verify their email before creating or joining campaigns.
 func NewCampaignUseCase(
 	ctx context.Context,
 	repo sqlc.Querier,
+	emailVerificationRequired bool,
 ) *CampaignUseCase {
 	return &CampaignUseCase{
-		ctx:  ctx,
-		repo: repo,
+		ctx:                       ctx,
+		repo:                      repo,
+		emailVerificationRequired: emailVerificationRequired,
 	}
 }
 
@@ -47,6 +69,9 @@ func (uc *CampaignUseCase) CreateCampaign(input domain.CampaignCreationInput, cr
 	if err != nil {
 		return sqlc.Campaign{}, err
 	}
+	if err := ensureEmailVerified(uc.ctx, uc.repo, createCampaignParams.CreatedBy, uc.emailVerificationRequired); err != nil {
+		return sqlc.Campaign{}, err
+	}
 	createdCampaign, err := uc.repo.CreateCampaign(uc.ctx, createCampaignParams)
 	if err != nil {
 		log.Printf("Error saving campaign: %v", err)
@@ -54,22 +79,31 @@ func (uc *CampaignUseCase) CreateCampaign(input domain.CampaignCreationInput, cr
 	}
 
 	// Add the creator as a GM
+	if err := uc.addCreatorAsGameMaster(createdCampaign); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	return createdCampaign, nil
+}
+
+// addCreatorAsGameMaster makes the creator of a new campaign its first GM
+func (uc *CampaignUseCase) addCreatorAsGameMaster(campaign sqlc.Campaign) error {
 	newUUUIDV7, err := utils.GeneratePGUUID()
 	if err != nil {
-		return sqlc.Campaign{}, err
+		return err
 	}
 	createCampaignMemberParams := sqlc.CreateCampaignMemberParams{
 		ID:         newUUUIDV7,
-		CampaignID: createdCampaign.ID,
-		UserID:     createdCampaign.CreatedBy,
+		CampaignID: campaign.ID,
+		UserID:     campaign.CreatedBy,
 		Role:       sqlc.MemberRoleGm,
 	}
 	if _, err := uc.repo.CreateCampaignMember(uc.ctx, createCampaignMemberParams); err != nil {
 		log.Printf("Error saving campaign member: %v", err)
-		return sqlc.Campaign{}, ErrCampaignMemberCreation
+		return ErrCampaignMemberCreation
 	}
 
-	return createdCampaign, nil
+	return nil
 }
 
 // GetCampaign retrieves a campaign by ID if the user has access
@@ -84,58 +118,593 @@ func (uc *CampaignUseCase) GetCampaign(input domain.GetCampaignInput) (sqlc.Camp
 		return sqlc.Campaign{}, ErrCampaignNotFound
 	}
 
+	// Only GMs may see the invite code, otherwise anyone browsing a public campaign could join it
+	member, err := uc.repo.GetCampaignMember(uc.ctx, sqlc.GetCampaignMemberParams{
+		CampaignID: getCampaignParams.ID,
+		UserID:     getCampaignParams.UserID,
+	})
+	if err != nil || member.Role != sqlc.MemberRoleGm {
+		hideInviteCode(&campaign)
+	}
+
+	// Members opening the campaign move it up their list sorted by last access
+	if err == nil {
+		if err := uc.repo.TouchCampaignMember(uc.ctx, sqlc.TouchCampaignMemberParams{
+			CampaignID: member.CampaignID,
+			UserID:     member.UserID,
+		}); err != nil {
+			log.Printf("Error recording campaign access: %v", err)
+		}
+	}
+
 	return campaign, nil
 }
 
-// UpdateCampaign updates a campaign if the user has GM permissions
-func (uc *CampaignUseCase) UpdateCampaign(campaign domain.UpdateCampaignInput) error {
+// UpdateCampaign replaces the details of a campaign if the user has GM permissions
+func (uc *CampaignUseCase) UpdateCampaign(campaign domain.UpdateCampaignInput) (sqlc.Campaign, error) {
+	if err := campaign.Validate(); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
 	updateCampaignParams, err := campaign.ToSqlcParams()
-	_, err = uc.repo.UpdateCampaign(uc.ctx, updateCampaignParams)
-	if err != nil && err.Error() == "no rows in result set" {
-		return ErrCampaignNotFound
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaign.UserId)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, updateCampaignParams.ID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	updatedCampaign, err := uc.repo.UpdateCampaign(uc.ctx, updateCampaignParams)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Campaign{}, ErrCampaignNotFound
+		}
+		return sqlc.Campaign{}, err
 	}
 
-	return err
+	return updatedCampaign, nil
+}
+
+// PatchCampaign applies a merge patch to a campaign if the user has GM permissions.
+// When ifMatch is set, the patch only applies if it holds the ETag of the current version of the campaign.
+// Either way a campaign updated by someone else between reading and saving it is never overwritten.
+func (uc *CampaignUseCase) PatchCampaign(
+	campaignID, requesterID uuid.UUID,
+	patch domain.CampaignPatch,
+	ifMatch string,
+) (sqlc.Campaign, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	campaign, err := uc.repo.GetCampaignByID(uc.ctx, sqlc.GetCampaignByIDParams{
+		ID:     campaignPGUUID,
+		UserID: requesterPGUUID,
+	})
+	if err != nil {
+		return sqlc.Campaign{}, ErrCampaignNotFound
+	}
+	if ifMatch != "" && !domain.MatchesCampaignETag(ifMatch, campaign) {
+		return sqlc.Campaign{}, ErrCampaignVersionMismatch
+	}
+
+	params, err := patch.Apply(campaign)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	patched, err := uc.repo.PatchCampaign(uc.ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Campaign{}, ErrCampaignVersionMismatch
+		}
+		return sqlc.Campaign{}, err
+	}
+
+	return patched, nil
 }
 
 // DeleteCampaign deletes a campaign if the user has GM permissions
 func (uc *CampaignUseCase) DeleteCampaign(input domain.DeleteCampaignInput) error {
-	// Delete the campaign
-	deleteCampaignParams, err := input.ToSqlcParams()
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(input.ID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(input.UserID)
 	if err != nil {
 		return err
 	}
 
-	return uc.repo.DeleteCampaign(uc.ctx, deleteCampaignParams)
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteCampaign(uc.ctx, campaignPGUUID)
 }
 
-// ListUserCampaigns lists all campaigns a user is a member of
-func (uc *CampaignUseCase) ListUserCampaigns(userID uuid.UUID) ([]sqlc.Campaign, error) {
+// SearchPublicCampaigns lists a page of the public campaigns matching the filter
+func (uc *CampaignUseCase) SearchPublicCampaigns(filter domain.PublicCampaignFilter) (domain.PublicCampaignPage, error) {
+	if err := filter.Validate(); err != nil {
+		return domain.PublicCampaignPage{}, err
+	}
+
+	params, err := filter.ToSqlcParams()
+	if err != nil {
+		return domain.PublicCampaignPage{}, err
+	}
+
+	campaigns, err := uc.repo.SearchPublicCampaigns(uc.ctx, params)
+	if err != nil {
+		return domain.PublicCampaignPage{}, err
+	}
+
+	return domain.NewPublicCampaignPage(campaigns, filter), nil
+}
+
+// CloneCampaign copies a public campaign into a new private campaign owned by the user as GM.
+// Its NPCs and public timeline events come along, player characters and GM-secret events never do.
+func (uc *CampaignUseCase) CloneCampaign(campaignID, userID uuid.UUID) (sqlc.Campaign, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
 	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
 	if err != nil {
-		return nil, err
+		return sqlc.Campaign{}, err
+	}
+
+	source, err := uc.repo.GetCampaignByID(uc.ctx, sqlc.GetCampaignByIDParams{
+		ID:     campaignPGUUID,
+		UserID: userPGUUID,
+	})
+	if err != nil || !source.IsPublic {
+		return sqlc.Campaign{}, ErrCampaignNotFound
+	}
+
+	if err := ensureEmailVerified(uc.ctx, uc.repo, userPGUUID, uc.emailVerificationRequired); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+	clone, err := uc.repo.CreateCampaign(uc.ctx, sqlc.CreateCampaignParams{
+		ID:             newUUUIDV7,
+		Title:          source.Title,
+		SettingSummary: source.SettingSummary,
+		Setting:        source.Setting,
+		ImageUrl:       source.ImageUrl,
+		IsPublic:       false,
+		CreatedBy:      userPGUUID,
+		Tags:           source.Tags,
+		GameSystem:     source.GameSystem,
+		ClonedFrom:     source.ID,
+	})
+	if err != nil {
+		log.Printf("Error saving campaign clone: %v", err)
+		return sqlc.Campaign{}, ErrCampaignCreation
+	}
+
+	if err := uc.addCreatorAsGameMaster(clone); err != nil {
+		uc.discardClone(clone)
+		return sqlc.Campaign{}, err
+	}
+	if err := uc.copyCampaignContent(source, clone); err != nil {
+		log.Printf("Error copying campaign content: %v", err)
+		uc.discardClone(clone)
+		return sqlc.Campaign{}, err
+	}
+
+	if err := uc.repo.IncrementCampaignCloneCount(uc.ctx, source.ID); err != nil {
+		log.Printf("Error incrementing the clone count of campaign %s: %v", uuid.UUID(source.ID.Bytes), err)
+	}
+
+	return clone, nil
+}
+
+// copyCampaignContent copies the NPCs and public timeline events of the source campaign into the clone,
+// both now belonging to the creator of the clone
+func (uc *CampaignUseCase) copyCampaignContent(source, clone sqlc.Campaign) error {
+	characters, err := uc.repo.ListCharactersByCampaign(uc.ctx, source.ID)
+	if err != nil {
+		return err
+	}
+	for _, character := range characters {
+		if !character.IsNpc {
+			continue
+		}
+
+		newUUUIDV7, err := utils.GeneratePGUUID()
+		if err != nil {
+			return err
+		}
+		_, err = uc.repo.CreateCharacter(uc.ctx, sqlc.CreateCharacterParams{
+			ID:          newUUUIDV7,
+			Name:        character.Name,
+			Race:        character.Race,
+			Class:       character.Class,
+			Level:       character.Level,
+			Appearance:  character.Appearance,
+			Personality: character.Personality,
+			Backstory:   character.Backstory,
+			ImageUrl:    character.ImageUrl,
+			CampaignID:  clone.ID,
+			UserID:      clone.CreatedBy,
+			IsNpc:       true,
+			Metadata:    character.Metadata,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	events, err := uc.repo.ListTimelineEvents(uc.ctx, sqlc.ListTimelineEventsParams{
+		CampaignID:    source.ID,
+		IncludeSecret: false,
+	})
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		newUUUIDV7, err := utils.GeneratePGUUID()
+		if err != nil {
+			return err
+		}
+		_, err = uc.repo.CreateTimelineEvent(uc.ctx, sqlc.CreateTimelineEventParams{
+			ID:          newUUUIDV7,
+			CampaignID:  clone.ID,
+			Title:       event.Title,
+			Description: event.Description,
+			EventDate:   event.EventDate,
+			IsPublic:    true,
+			CreatedBy:   clone.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// discardClone deletes a clone that couldn't be copied in full, so that no half-copied campaign is left behind
+func (uc *CampaignUseCase) discardClone(clone sqlc.Campaign) {
+	if err := uc.repo.DeleteCampaign(uc.ctx, clone.ID); err != nil {
+		log.Printf("Error discarding campaign clone %s: %v", uuid.UUID(clone.ID.Bytes), err)
+	}
+}
+
+// ListUserCampaigns lists a page of the campaigns a user is a member of, along with their role in each
+func (uc *CampaignUseCase) ListUserCampaigns(filter domain.UserCampaignFilter) (domain.UserCampaignPage, error) {
+	if err := filter.Validate(); err != nil {
+		return domain.UserCampaignPage{}, err
+	}
+
+	params, err := filter.ToSqlcParams()
+	if err != nil {
+		return domain.UserCampaignPage{}, err
+	}
+
+	campaigns, err := uc.repo.ListMemberCampaigns(uc.ctx, params)
+	if err != nil {
+		return domain.UserCampaignPage{}, err
+	}
+
+	return domain.NewUserCampaignPage(campaigns, filter), nil
+}
+
+// ArchiveCampaign archives a campaign, or brings it back when archived is false, if the user has GM permissions
+func (uc *CampaignUseCase) ArchiveCampaign(campaignID, requesterID uuid.UUID, archived bool) (sqlc.Campaign, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.Campaign{}, err
 	}
 
-	return uc.repo.ListCampaignsByUserID(uc.ctx, userPGUUID)
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	if archived {
+		return uc.repo.ArchiveCampaign(uc.ctx, campaignPGUUID)
+	}
+	return uc.repo.UnarchiveCampaign(uc.ctx, campaignPGUUID)
 }
 
 // AddCampaignMember adds a user to a campaign if the requester has GM permissions
 func (uc *CampaignUseCase) AddCampaignMember(campaignID, userID, requesterID uuid.UUID, role string) error {
+	memberRole, err := domain.ParseMemberRole(role)
+	if err != nil {
+		return err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return err
+	}
+
+	if _, err := uc.repo.GetUserByID(uc.ctx, userPGUUID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return err
+	}
+	createCampaignMemberParams := sqlc.CreateCampaignMemberParams{
+		ID:         newUUUIDV7,
+		CampaignID: campaignPGUUID,
+		UserID:     userPGUUID,
+		Role:       memberRole,
+	}
+	if _, err := uc.repo.CreateCampaignMember(uc.ctx, createCampaignMemberParams); err != nil {
+		if utils.IsUniqueViolation(err) {
+			return ErrCampaignMemberAlreadyExists
+		}
+		log.Printf("Error saving campaign member: %v", err)
+		return ErrCampaignMemberCreation
+	}
+
 	return nil
 }
 
 // RemoveCampaignMember removes a user from a campaign if the requester has GM permissions
 func (uc *CampaignUseCase) RemoveCampaignMember(campaignID, userID, requesterID uuid.UUID) error {
-	return nil
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return err
+	}
 
+	return uc.removeMember(campaignPGUUID, userPGUUID)
 }
 
 // LeaveCampaign allows a user to leave a campaign
 func (uc *CampaignUseCase) LeaveCampaign(campaignID, userID uuid.UUID) error {
-	return nil
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, userPGUUID, sqlc.MemberRolePlayer); err != nil {
+		// Only members can leave, so a visible campaign without membership is reported as not found
+		if errors.Is(err, ErrInsufficientPermissions) {
+			return ErrCampaignMemberNotFound
+		}
+		return err
+	}
+
+	return uc.removeMember(campaignPGUUID, userPGUUID)
 }
 
 // GetCampaignMembers lists all members of a campaign if the user has access
-func (uc *CampaignUseCase) GetCampaignMembers(campaignID, userID uuid.UUID) ([]sqlc.CampaignMember, error) {
-	return []sqlc.CampaignMember{}, nil
+func (uc *CampaignUseCase) GetCampaignMembers(campaignID, userID uuid.UUID) ([]sqlc.ListCampaignMembersRow, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, userPGUUID, sqlc.MemberRolePlayer); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListCampaignMembers(uc.ctx, campaignPGUUID)
+}
+
+// removeMember deletes a membership while making sure the campaign keeps at least one GM
+func (uc *CampaignUseCase) removeMember(campaignID, userID pgtype.UUID) error {
+	deleted, err := uc.repo.DeleteCampaignMember(uc.ctx, sqlc.DeleteCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		return nil
+	}
+
+	// Nothing was deleted, either there was no membership or it was the last GM
+	_, err = uc.repo.GetCampaignMember(uc.ctx, sqlc.GetCampaignMemberParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCampaignMemberNotFound
+		}
+		return err
+	}
+
+	return ErrLastGameMaster
+}
+
+// GenerateInviteCode creates or rotates the invite code of a campaign if the requester has GM permissions
+func (uc *CampaignUseCase) GenerateInviteCode(campaignID, requesterID uuid.UUID, input domain.InviteCodeInput) (domain.InviteCodeOutput, error) {
+	if err := input.Validate(); err != nil {
+		return domain.InviteCodeOutput{}, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return domain.InviteCodeOutput{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return domain.InviteCodeOutput{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return domain.InviteCodeOutput{}, err
+	}
+
+	// Codes are short, so retry on the rare collision with another campaign's code
+	for attempt := 0; ; attempt++ {
+		code, err := utils.GenerateRandomString(inviteCodeLength, utils.InviteCodeAlphabet)
+		if err != nil {
+			return domain.InviteCodeOutput{}, err
+		}
+
+		campaign, err := uc.repo.GenerateInviteCode(uc.ctx, input.ToSqlcParams(campaignPGUUID, code))
+		if err == nil {
+			return domain.NewInviteCodeOutput(campaign), nil
+		}
+		if !utils.IsUniqueViolation(err) || attempt >= inviteCodeGenerationRetries {
+			log.Printf("Error generating invite code: %v", err)
+			return domain.InviteCodeOutput{}, err
+		}
+	}
+}
+
+// RevokeInviteCode removes the invite code of a campaign if the requester has GM permissions
+func (uc *CampaignUseCase) RevokeInviteCode(campaignID, requesterID uuid.UUID) error {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return err
+	}
+
+	return uc.repo.RevokeInviteCode(uc.ctx, campaignPGUUID)
+}
+
+// JoinCampaignByInviteCode adds the user as a player of the campaign the invite code belongs to
+func (uc *CampaignUseCase) JoinCampaignByInviteCode(code string, userID uuid.UUID) (sqlc.Campaign, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+	inviteCode := pgtype.Text{
+		String: strings.TrimSpace(code),
+		Valid:  true,
+	}
+
+	if err := ensureEmailVerified(uc.ctx, uc.repo, userPGUUID, uc.emailVerificationRequired); err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	campaign, err := uc.repo.GetCampaignByInviteCode(uc.ctx, inviteCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Campaign{}, ErrInviteCodeNotFound
+		}
+		return sqlc.Campaign{}, err
+	}
+
+	// Check membership first so that members re-using the code don't burn one of its uses
+	_, err = uc.repo.GetCampaignMember(uc.ctx, sqlc.GetCampaignMemberParams{
+		CampaignID: campaign.ID,
+		UserID:     userPGUUID,
+	})
+	if err == nil {
+		return sqlc.Campaign{}, ErrCampaignMemberAlreadyExists
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Campaign{}, err
+	}
+
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.Campaign{}, err
+	}
+
+	campaign, err = uc.repo.RedeemInviteCode(uc.ctx, inviteCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Campaign{}, ErrInviteCodeExpired
+		}
+		return sqlc.Campaign{}, err
+	}
+
+	createCampaignMemberParams := sqlc.CreateCampaignMemberParams{
+		ID:         newUUUIDV7,
+		CampaignID: campaign.ID,
+		UserID:     userPGUUID,
+		Role:       sqlc.MemberRolePlayer,
+	}
+	if _, err := uc.repo.CreateCampaignMember(uc.ctx, createCampaignMemberParams); err != nil {
+		// The user didn't join, so the use redeemed above goes back to the code
+		releaseErr := uc.repo.ReleaseInviteCodeUse(uc.ctx, sqlc.ReleaseInviteCodeUseParams{
+			ID:         campaign.ID,
+			InviteCode: inviteCode,
+		})
+		if releaseErr != nil {
+			log.Printf("Error releasing invite code use: %v", releaseErr)
+		}
+
+		if utils.IsUniqueViolation(err) {
+			return sqlc.Campaign{}, ErrCampaignMemberAlreadyExists
+		}
+		log.Printf("Error saving campaign member: %v", err)
+		return sqlc.Campaign{}, ErrCampaignMemberCreation
+	}
+
+	hideInviteCode(&campaign)
+	return campaign, nil
+}
+
+// hideInviteCode strips the invite code details from a campaign shown to a non-GM
+func hideInviteCode(campaign *sqlc.Campaign) {
+	campaign.InviteCode = pgtype.Text{}
+	campaign.InviteCodeExpiresAt = pgtype.Timestamptz{}
+	campaign.InviteCodeMaxUses = pgtype.Int4{}
+	campaign.InviteCodeUses = 0
 }
@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrCharacterCreation = errors.New("error creating character")
+	ErrCharacterNotFound = errors.New("character not found")
+)
+
+// CharacterUseCase implements the character business logic
+type CharacterUseCase struct {
+	ctx  context.Context
+	repo sqlc.Querier
+}
+
+// NewCharacterUseCase creates a new character use case
+func NewCharacterUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+) *CharacterUseCase {
+	return &CharacterUseCase{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+// CreateCharacter creates a character owned by the requester.
+// Any member can create their own player character, but only GMs can create NPCs.
+func (uc *CharacterUseCase) CreateCharacter(campaignID, requesterID uuid.UUID, input domain.CharacterInput) (sqlc.Character, error) {
+	if err := input.Validate(); err != nil {
+		return sqlc.Character{}, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+
+	member, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	if input.IsNPC && member.Role != sqlc.MemberRoleGm {
+		return sqlc.Character{}, ErrInsufficientPermissions
+	}
+
+	createCharacterParams, err := input.ToSqlcParams(campaignPGUUID, requesterPGUUID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	character, err := uc.repo.CreateCharacter(uc.ctx, createCharacterParams)
+	if err != nil {
+		log.Printf("Error saving character: %v", err)
+		return sqlc.Character{}, ErrCharacterCreation
+	}
+
+	return character, nil
+}
+
+// ListCharacters lists the characters of a campaign if the requester is a member
+func (uc *CharacterUseCase) ListCharacters(campaignID, requesterID uuid.UUID) ([]sqlc.Character, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListCharactersByCampaign(uc.ctx, campaignPGUUID)
+}
+
+// GetCharacter retrieves a character of a campaign if the requester is a member
+func (uc *CharacterUseCase) GetCharacter(campaignID, characterID, requesterID uuid.UUID) (sqlc.Character, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	characterPGUUID, err := utils.GeneratePGUUIDFromCustomId(characterID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer); err != nil {
+		return sqlc.Character{}, err
+	}
+
+	return uc.getCharacter(characterPGUUID, campaignPGUUID)
+}
+
+// UpdateCharacter replaces the details of a character.
+// GMs can edit every character, while players can only edit their own player characters.
+func (uc *CharacterUseCase) UpdateCharacter(campaignID, characterID, requesterID uuid.UUID, input domain.CharacterInput) (sqlc.Character, error) {
+	if err := input.Validate(); err != nil {
+		return sqlc.Character{}, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	characterPGUUID, err := utils.GeneratePGUUIDFromCustomId(characterID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+
+	member, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+	character, err := uc.getCharacter(characterPGUUID, campaignPGUUID)
+	if err != nil {
+		return sqlc.Character{}, err
+	}
+
+	// Players can't turn their characters into NPCs either
+	if !canManageCharacter(member, character) || (input.IsNPC && member.Role != sqlc.MemberRoleGm) {
+		return sqlc.Character{}, ErrInsufficientPermissions
+	}
+
+	character, err = uc.repo.UpdateCharacter(uc.ctx, input.ToSqlcUpdateParams(characterPGUUID, campaignPGUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Character{}, ErrCharacterNotFound
+		}
+		return sqlc.Character{}, err
+	}
+
+	return character, nil
+}
+
+// DeleteCharacter deletes a character.
+// GMs can delete every character, while players can only delete their own player characters.
+func (uc *CharacterUseCase) DeleteCharacter(campaignID, characterID, requesterID uuid.UUID) error {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	characterPGUUID, err := utils.GeneratePGUUIDFromCustomId(characterID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return err
+	}
+
+	member, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer)
+	if err != nil {
+		return err
+	}
+	character, err := uc.getCharacter(characterPGUUID, campaignPGUUID)
+	if err != nil {
+		return err
+	}
+
+	if !canManageCharacter(member, character) {
+		return ErrInsufficientPermissions
+	}
+
+	return uc.repo.DeleteCharacter(uc.ctx, sqlc.DeleteCharacterParams{
+		ID:         characterPGUUID,
+		CampaignID: campaignPGUUID,
+	})
+}
+
+func (uc *CharacterUseCase) getCharacter(characterID, campaignID pgtype.UUID) (sqlc.Character, error) {
+	character, err := uc.repo.GetCharacterByID(uc.ctx, sqlc.GetCharacterByIDParams{
+		ID:         characterID,
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Character{}, ErrCharacterNotFound
+		}
+		return sqlc.Character{}, err
+	}
+
+	return character, nil
+}
+
+// canManageCharacter reports whether a member may edit or delete a character
+func canManageCharacter(member sqlc.CampaignMember, character sqlc.Character) bool {
+	if member.Role == sqlc.MemberRoleGm {
+		return true
+	}
+
+	return !character.IsNpc && character.UserID == member.UserID
+}
@@ -0,0 +1,338 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/interfaces"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationExpired        = errors.New("invitation has expired")
+	ErrInvitationNotPending     = errors.New("invitation was already answered")
+	ErrInvitationAlreadyPending = errors.New("a pending invitation already exists for this email")
+	ErrInvitationCreation       = errors.New("error creating invitation")
+	ErrSendingInvitation        = errors.New("error sending invitation email")
+)
+
+const invitationTokenBytes = 32
+
+// InvitationUseCase implements the email invitation business logic
+type InvitationUseCase struct {
+	ctx              context.Context
+	repo             sqlc.Querier
+	mailer           interfaces.Mailer
+	appBaseURL       string
+	invitationExpiry time.Duration
+}
+
+// NewInvitationUseCase creates a new invitation use case.
+// Invitations are matched by email, so invitees must verify their email before seeing or answering them,
+// whatever the email verification policy for campaigns is.
+func NewInvitationUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+	mailer interfaces.Mailer,
+	appBaseURL string,
+	invitationExpiry time.Duration,
+) *InvitationUseCase {
+	return &InvitationUseCase{
+		ctx:              ctx,
+		repo:             repo,
+		mailer:           mailer,
+		appBaseURL:       strings.TrimRight(appBaseURL, "/"),
+		invitationExpiry: invitationExpiry,
+	}
+}
+
+// InviteByEmail creates an invitation and emails the invite link if the requester has GM permissions
+func (uc *InvitationUseCase) InviteByEmail(campaignID, requesterID uuid.UUID, input domain.InvitationCreationInput) (sqlc.Invitation, error) {
+	if err := input.Validate(); err != nil {
+		return sqlc.Invitation{}, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return sqlc.Invitation{}, err
+	}
+
+	campaign, err := uc.repo.GetCampaignByID(uc.ctx, sqlc.GetCampaignByIDParams{
+		ID:     campaignPGUUID,
+		UserID: requesterPGUUID,
+	})
+	if err != nil {
+		return sqlc.Invitation{}, ErrCampaignNotFound
+	}
+	inviter, err := uc.repo.GetUserByID(uc.ctx, requesterPGUUID)
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+
+	// Don't invite people who already play in the campaign
+	email := domain.NormalizeEmail(input.Email)
+	if invitee, err := uc.repo.GetUserByEmail(uc.ctx, email); err == nil {
+		_, err := uc.repo.GetCampaignMember(uc.ctx, sqlc.GetCampaignMemberParams{
+			CampaignID: campaignPGUUID,
+			UserID:     invitee.ID,
+		})
+		if err == nil {
+			return sqlc.Invitation{}, ErrCampaignMemberAlreadyExists
+		}
+	}
+
+	_, err = uc.repo.GetPendingInvitationByCampaignAndEmail(uc.ctx, sqlc.GetPendingInvitationByCampaignAndEmailParams{
+		CampaignID: campaignPGUUID,
+		Email:      email,
+	})
+	if err == nil {
+		return sqlc.Invitation{}, ErrInvitationAlreadyPending
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Invitation{}, err
+	}
+
+	token, err := utils.GenerateSecureToken(invitationTokenBytes)
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+	createInvitationParams, err := input.ToSqlcParams(campaignPGUUID, requesterPGUUID, token, time.Now().Add(uc.invitationExpiry))
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+	invitation, err := uc.repo.CreateInvitation(uc.ctx, createInvitationParams)
+	if err != nil {
+		log.Printf("Error saving invitation: %v", err)
+		return sqlc.Invitation{}, ErrInvitationCreation
+	}
+
+	if err := uc.mailer.Send(uc.invitationEmail(invitation, campaign, inviter)); err != nil {
+		log.Printf("Error sending invitation email: %v", err)
+
+		// Drop the invitation so the GM can simply try again
+		if _, err := uc.repo.DeleteInvitation(uc.ctx, sqlc.DeleteInvitationParams{
+			ID:         invitation.ID,
+			CampaignID: invitation.CampaignID,
+		}); err != nil {
+			log.Printf("Error deleting unsent invitation: %v", err)
+		}
+		return sqlc.Invitation{}, ErrSendingInvitation
+	}
+
+	return invitation, nil
+}
+
+// ListCampaignInvitations lists every invitation of a campaign if the requester has GM permissions
+func (uc *InvitationUseCase) ListCampaignInvitations(campaignID, requesterID uuid.UUID) ([]sqlc.ListInvitationsByCampaignRow, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.ExpireInvitations(uc.ctx); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListInvitationsByCampaign(uc.ctx, campaignPGUUID)
+}
+
+// CancelInvitation deletes an invitation of a campaign if the requester has GM permissions
+func (uc *InvitationUseCase) CancelInvitation(campaignID, invitationID, requesterID uuid.UUID) error {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	invitationPGUUID, err := utils.GeneratePGUUIDFromCustomId(invitationID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return err
+	}
+
+	deleted, err := uc.repo.DeleteInvitation(uc.ctx, sqlc.DeleteInvitationParams{
+		ID:         invitationPGUUID,
+		CampaignID: campaignPGUUID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// ListUserInvitations lists the pending invitations sent to the email of the user, once they verified it
+func (uc *InvitationUseCase) ListUserInvitations(userID uuid.UUID) ([]sqlc.ListPendingInvitationsByEmailRow, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.repo.GetUserByID(uc.ctx, userPGUUID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, ErrEmailNotVerified
+	}
+
+	if err := uc.repo.ExpireInvitations(uc.ctx); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListPendingInvitationsByEmail(uc.ctx, user.Email)
+}
+
+// AcceptInvitation adds the user to the invited campaign as a player
+func (uc *InvitationUseCase) AcceptInvitation(token string, userID uuid.UUID) (sqlc.Invitation, error) {
+	invitation, user, err := uc.getPendingInvitation(token, userID)
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+
+	newUUUIDV7, err := utils.GeneratePGUUID()
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+	createCampaignMemberParams := sqlc.CreateCampaignMemberParams{
+		ID:         newUUUIDV7,
+		CampaignID: invitation.CampaignID,
+		UserID:     user.ID,
+		Role:       sqlc.MemberRolePlayer,
+	}
+	_, err = uc.repo.CreateCampaignMember(uc.ctx, createCampaignMemberParams)
+	alreadyMember := utils.IsUniqueViolation(err)
+	if err != nil && !alreadyMember {
+		log.Printf("Error saving campaign member: %v", err)
+		return sqlc.Invitation{}, ErrCampaignMemberCreation
+	}
+
+	// A user who joined by other means in the meantime still consumes the invitation
+	invitation, err = uc.repo.UpdateInvitationStatus(uc.ctx, sqlc.UpdateInvitationStatusParams{
+		ID:     invitation.ID,
+		Status: sqlc.InvitationStatusAccepted,
+	})
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+	if alreadyMember {
+		return sqlc.Invitation{}, ErrCampaignMemberAlreadyExists
+	}
+
+	return invitation, nil
+}
+
+// RejectInvitation declines an invitation sent to the user
+func (uc *InvitationUseCase) RejectInvitation(token string, userID uuid.UUID) (sqlc.Invitation, error) {
+	invitation, _, err := uc.getPendingInvitation(token, userID)
+	if err != nil {
+		return sqlc.Invitation{}, err
+	}
+
+	return uc.repo.UpdateInvitationStatus(uc.ctx, sqlc.UpdateInvitationStatusParams{
+		ID:     invitation.ID,
+		Status: sqlc.InvitationStatusRejected,
+	})
+}
+
+// getPendingInvitation loads an invitation addressed to the verified email of the user, expiring it when its deadline has passed
+func (uc *InvitationUseCase) getPendingInvitation(token string, userID uuid.UUID) (sqlc.Invitation, sqlc.User, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return sqlc.Invitation{}, sqlc.User{}, err
+	}
+
+	user, err := uc.repo.GetUserByID(uc.ctx, userPGUUID)
+	if err != nil {
+		return sqlc.Invitation{}, sqlc.User{}, ErrUserNotFound
+	}
+	// Anyone can claim an email address, only its verified owner may answer the invitations sent to it
+	if !user.IsActive {
+		return sqlc.Invitation{}, sqlc.User{}, ErrEmailNotVerified
+	}
+
+	invitation, err := uc.repo.GetInvitationByToken(uc.ctx, token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Invitation{}, sqlc.User{}, ErrInvitationNotFound
+		}
+		return sqlc.Invitation{}, sqlc.User{}, err
+	}
+
+	// Invitations addressed to someone else are reported as missing so tokens can't be probed
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return sqlc.Invitation{}, sqlc.User{}, ErrInvitationNotFound
+	}
+
+	switch invitation.Status {
+	case sqlc.InvitationStatusPending:
+	case sqlc.InvitationStatusExpired:
+		return sqlc.Invitation{}, sqlc.User{}, ErrInvitationExpired
+	default:
+		return sqlc.Invitation{}, sqlc.User{}, ErrInvitationNotPending
+	}
+
+	if !invitation.ExpiresAt.Time.After(time.Now()) {
+		if _, err := uc.repo.UpdateInvitationStatus(uc.ctx, sqlc.UpdateInvitationStatusParams{
+			ID:     invitation.ID,
+			Status: sqlc.InvitationStatusExpired,
+		}); err != nil {
+			return sqlc.Invitation{}, sqlc.User{}, err
+		}
+		return sqlc.Invitation{}, sqlc.User{}, ErrInvitationExpired
+	}
+
+	return invitation, user, nil
+}
+
+// invitationEmail renders the email carrying the invite link
+func (uc *InvitationUseCase) invitationEmail(invitation sqlc.Invitation, campaign sqlc.Campaign, inviter sqlc.User) domain.EmailMessage {
+	link := fmt.Sprintf("%s/invitations/%s", uc.appBaseURL, invitation.Token)
+
+	return domain.EmailMessage{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("%s invited you to %s on LoreCrafter", inviter.Username, campaign.Title),
+		Body: fmt.Sprintf(
+			"%s invited you to join the campaign \"%s\" on LoreCrafter.\n\n"+
+				"Log in with this email address and open the link below to accept or decline:\n%s\n\n"+
+				"This invitation expires on %s.\n",
+			inviter.Username,
+			campaign.Title,
+			link,
+			invitation.ExpiresAt.Time.UTC().Format(time.RFC1123),
+		),
+	}
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// LoginLockedError is returned while an account or an IP address is locked out after too many failed logins
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrLoginLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// LoginThrottleSettings controls the lockout after failed logins. Once an account or an IP address reaches its
+// maximum of failed attempts it is locked for BaseLockout, doubling with each further failure up to MaxLockout.
+// Failures are forgotten after FailureWindow without new ones.
+type LoginThrottleSettings struct {
+	MaxAccountAttempts int
+	MaxIPAttempts      int
+	BaseLockout        time.Duration
+	MaxLockout         time.Duration
+	FailureWindow      time.Duration
+}
+
+// loginThrottleKeys identifies the account and the IP address of a login attempt.
+// Accounts are keyed by username so unknown usernames are throttled the same way as existing ones.
+func loginThrottleKeys(username, ipAddress string) (string, string) {
+	return "account:" + strings.ToLower(username), "ip:" + ipAddress
+}
+
+// checkLoginLock fails while the account or the IP address of a login attempt is locked out
+func (uc *AuthUseCase) checkLoginLock(keys ...string) error {
+	throttles, err := uc.userRepo.ListLoginThrottles(uc.ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	var lockedUntil time.Time
+	for _, throttle := range throttles {
+		if throttle.LockedUntil.Valid && throttle.LockedUntil.Time.After(lockedUntil) {
+			lockedUntil = throttle.LockedUntil.Time
+		}
+	}
+
+	if retryAfter := time.Until(lockedUntil); retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt for the account and the IP address, locking them out past their maximum
+func (uc *AuthUseCase) recordLoginFailure(accountKey, ipKey string) {
+	uc.recordThrottleFailure(accountKey, uc.loginThrottle.MaxAccountAttempts)
+	uc.recordThrottleFailure(ipKey, uc.loginThrottle.MaxIPAttempts)
+}
+
+func (uc *AuthUseCase) recordThrottleFailure(key string, maxAttempts int) {
+	throttle, err := uc.userRepo.RecordLoginFailure(uc.ctx, sqlc.RecordLoginFailureParams{
+		Key: key,
+		ResetBefore: pgtype.Timestamptz{
+			Time:  time.Now().Add(-uc.loginThrottle.FailureWindow),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Error recording failed login: %v", err)
+		return
+	}
+
+	if int(throttle.FailedCount) < maxAttempts {
+		return
+	}
+
+	err = uc.userRepo.LockLogin(uc.ctx, sqlc.LockLoginParams{
+		Key: key,
+		LockedUntil: pgtype.Timestamptz{
+			Time:  time.Now().Add(uc.lockoutDuration(int(throttle.FailedCount) - maxAttempts)),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Error locking login: %v", err)
+	}
+}
+
+// lockoutDuration doubles the base lockout for each failure past the maximum, up to the max lockout
+func (uc *AuthUseCase) lockoutDuration(extraFailures int) time.Duration {
+	lockout := uc.loginThrottle.BaseLockout
+	for i := 0; i < extraFailures && lockout < uc.loginThrottle.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	return min(lockout, uc.loginThrottle.MaxLockout)
+}
+
+// clearLoginFailures forgets the failed attempts of an account after a successful login.
+// The IP address keeps its count so logging into one account doesn't help guessing the password of another.
+func (uc *AuthUseCase) clearLoginFailures(accountKey string) {
+	if err := uc.userRepo.ClearLoginThrottle(uc.ctx, accountKey); err != nil {
+		log.Printf("Error clearing failed logins: %v", err)
+	}
+
+	err := uc.userRepo.DeleteStaleLoginThrottles(uc.ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-uc.loginThrottle.FailureWindow),
+		Valid: true,
+	})
+	if err != nil {
+		log.Printf("Error deleting stale login throttles: %v", err)
+	}
+}
@@ -0,0 +1,391 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/interfaces"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrOIDCProviderNotFound  = errors.New("login provider not found")
+	ErrInvalidOIDCState      = errors.New("login state is invalid, expired or already used")
+	ErrOIDCLoginFailed       = errors.New("login with the provider failed")
+	ErrOIDCUnavailable       = errors.New("login provider is unavailable")
+	ErrOIDCEmailMissing      = errors.New("login provider didn't share an email address")
+	ErrOIDCEmailTaken        = errors.New("an account already uses the email of this provider account")
+	ErrIdentityAlreadyLinked = errors.New("provider account is already linked to another user")
+	ErrIdentityNotFound      = errors.New("linked provider account not found")
+)
+
+const (
+	oidcStateBytes        = 32
+	oidcPasswordBytes     = 32
+	oidcUsernameAttempts  = 5
+	oidcUsernameSuffixLen = 3
+)
+
+// OIDCUseCase logs users in and links accounts through OpenID Connect providers
+type OIDCUseCase struct {
+	ctx         context.Context
+	repo        sqlc.Querier
+	authUseCase *AuthUseCase
+	providers   map[string]interfaces.OIDCProvider
+	stateExpiry time.Duration
+}
+
+// NewOIDCUseCase creates a new OIDC use case, providers are keyed by the name used in the routes
+func NewOIDCUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+	authUseCase *AuthUseCase,
+	providers map[string]interfaces.OIDCProvider,
+	stateExpiry time.Duration,
+) *OIDCUseCase {
+	return &OIDCUseCase{
+		ctx:         ctx,
+		repo:        repo,
+		authUseCase: authUseCase,
+		providers:   providers,
+		stateExpiry: stateExpiry,
+	}
+}
+
+// Providers lists the names of the configured providers
+func (uc *OIDCUseCase) Providers() domain.OIDCProviders {
+	names := make([]string, 0, len(uc.providers))
+	for name := range uc.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return domain.OIDCProviders{Providers: names}
+}
+
+// StartLogin returns the URL of the provider login page, the provider sends the user back to Callback
+func (uc *OIDCUseCase) StartLogin(providerName string) (domain.OIDCAuthorization, error) {
+	return uc.start(providerName, pgtype.UUID{})
+}
+
+// StartLink returns the URL of the provider login page, the provider account is linked to the user on Callback
+func (uc *OIDCUseCase) StartLink(userID uuid.UUID, providerName string) (domain.OIDCAuthorization, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return domain.OIDCAuthorization{}, err
+	}
+
+	return uc.start(providerName, userPGUUID)
+}
+
+// Callback completes the authorization code flow.
+// A login signs in the user linked to the provider account, creating the user on their first login.
+// A link started with StartLink attaches the provider account to the user who started it.
+func (uc *OIDCUseCase) Callback(providerName string, input domain.OIDCCallbackInput, client domain.SessionClient) (domain.OIDCCallbackResult, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return domain.OIDCCallbackResult{}, ErrOIDCProviderNotFound
+	}
+	if err := input.Validate(); err != nil {
+		return domain.OIDCCallbackResult{}, err
+	}
+
+	// The state is single use, so a replayed callback fails even if the code is still valid
+	state, err := uc.repo.ConsumeOIDCLoginState(uc.ctx, utils.HashToken(input.State))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.OIDCCallbackResult{}, ErrInvalidOIDCState
+		}
+		return domain.OIDCCallbackResult{}, err
+	}
+	if state.Provider != providerName {
+		return domain.OIDCCallbackResult{}, ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Exchange(uc.ctx, input.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("Error completing login with %s: %v", providerName, err)
+		return domain.OIDCCallbackResult{}, ErrOIDCLoginFailed
+	}
+
+	if state.UserID.Valid {
+		linked, err := uc.link(state.UserID, providerName, identity)
+		if err != nil {
+			return domain.OIDCCallbackResult{}, err
+		}
+		return domain.OIDCCallbackResult{Identity: &linked}, nil
+	}
+
+	return uc.login(providerName, identity, client)
+}
+
+// ListIdentities lists the provider accounts linked to the user
+func (uc *OIDCUseCase) ListIdentities(userID uuid.UUID) ([]domain.UserIdentity, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := uc.repo.ListUserIdentities(uc.ctx, userPGUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserIdentity, 0, len(identities))
+	for _, identity := range identities {
+		result = append(result, domain.NewUserIdentity(identity))
+	}
+
+	return result, nil
+}
+
+// UnlinkIdentity detaches a provider account from the user, it can no longer be used to log in
+func (uc *OIDCUseCase) UnlinkIdentity(userID, identityID uuid.UUID) error {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+	identityPGUUID, err := utils.GeneratePGUUIDFromCustomId(identityID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := uc.repo.DeleteUserIdentity(uc.ctx, sqlc.DeleteUserIdentityParams{
+		ID:     identityPGUUID,
+		UserID: userPGUUID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrIdentityNotFound
+	}
+
+	return nil
+}
+
+// start saves the state, nonce and PKCE code verifier of a new authorization request
+func (uc *OIDCUseCase) start(providerName string, userID pgtype.UUID) (domain.OIDCAuthorization, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return domain.OIDCAuthorization{}, ErrOIDCProviderNotFound
+	}
+
+	// Requests that were never completed are dropped along the way
+	if err := uc.repo.DeleteExpiredOIDCLoginStates(uc.ctx); err != nil {
+		log.Printf("Error deleting expired login states: %v", err)
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		secret, err := utils.GenerateSecureToken(oidcStateBytes)
+		if err != nil {
+			return domain.OIDCAuthorization{}, fmt.Errorf("error generating login state: %w", err)
+		}
+		secrets[i] = secret
+	}
+	state, nonce, codeVerifier := secrets[0], secrets[1], secrets[2]
+
+	authorizationURL, err := provider.AuthCodeURL(uc.ctx, state, nonce, codeVerifier)
+	if err != nil {
+		log.Printf("Error starting login with %s: %v", providerName, err)
+		return domain.OIDCAuthorization{}, ErrOIDCUnavailable
+	}
+
+	err = uc.repo.CreateOIDCLoginState(uc.ctx, sqlc.CreateOIDCLoginStateParams{
+		StateHash:    utils.HashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(uc.stateExpiry),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return domain.OIDCAuthorization{}, fmt.Errorf("error saving login state: %w", err)
+	}
+
+	return domain.OIDCAuthorization{AuthorizationURL: authorizationURL}, nil
+}
+
+// login signs in the user linked to the provider account, provisioning one on the first login
+func (uc *OIDCUseCase) login(providerName string, identity domain.OIDCIdentity, client domain.SessionClient) (domain.OIDCCallbackResult, error) {
+	var user sqlc.User
+	linked, err := uc.repo.GetUserIdentity(uc.ctx, sqlc.GetUserIdentityParams{
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	})
+	switch {
+	case err == nil:
+		user, err = uc.repo.GetUserByID(uc.ctx, linked.UserID)
+		if err != nil {
+			return domain.OIDCCallbackResult{}, err
+		}
+		if err := uc.repo.TouchUserIdentity(uc.ctx, linked.ID); err != nil {
+			log.Printf("Error updating identity last login: %v", err)
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		user, err = uc.provisionUser(providerName, identity)
+		if err != nil {
+			return domain.OIDCCallbackResult{}, err
+		}
+	default:
+		return domain.OIDCCallbackResult{}, err
+	}
+
+	// The provider stands in for the password, the second factor is still required
+	if user.MfaEnabled {
+		challenge, err := uc.authUseCase.createMFAChallenge(user, domain.AllScopes)
+		if err != nil {
+			return domain.OIDCCallbackResult{}, err
+		}
+		return domain.OIDCCallbackResult{Challenge: challenge}, nil
+	}
+
+	accountKey, _ := loginThrottleKeys(user.Username, "")
+	output, err := uc.authUseCase.completeLogin(user, accountKey, domain.AllScopes, client)
+	if err != nil {
+		return domain.OIDCCallbackResult{}, err
+	}
+
+	return domain.OIDCCallbackResult{Auth: output}, nil
+}
+
+// provisionUser creates a user for a provider account logging in for the first time.
+// Accounts aren't merged by email, the owner of an existing account has to log in and link the provider.
+func (uc *OIDCUseCase) provisionUser(providerName string, identity domain.OIDCIdentity) (sqlc.User, error) {
+	if identity.Email == "" {
+		return sqlc.User{}, ErrOIDCEmailMissing
+	}
+
+	_, err := uc.repo.GetUserByEmail(uc.ctx, identity.Email)
+	if err == nil {
+		return sqlc.User{}, ErrOIDCEmailTaken
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.User{}, err
+	}
+
+	username, err := uc.availableUsername(identity.Username())
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	// The user has no password to log in with until they reset it
+	password, err := utils.GenerateSecureToken(oidcPasswordBytes)
+	if err != nil {
+		return sqlc.User{}, fmt.Errorf("error generating password: %w", err)
+	}
+	input := domain.UserCreationInput{
+		Username: username,
+		Email:    identity.Email,
+		Password: password,
+	}
+	hashedPassword, err := uc.authUseCase.argon2Hash.HashPassword(input.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %v", err)
+		return sqlc.User{}, ErrHashPassword
+	}
+	createUserParams, err := input.ToSqlcParams(hashedPassword)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	user, err := uc.repo.CreateUser(uc.ctx, createUserParams)
+	if err != nil {
+		log.Printf("Error creating user: %v", err)
+		return sqlc.User{}, ErrCreateUser
+	}
+
+	if err := uc.linkIdentity(user, providerName, identity); err != nil {
+		// Without the identity nobody could log into the account, and its email would stay taken
+		if deleteErr := uc.repo.DeleteUser(uc.ctx, user.ID); deleteErr != nil {
+			log.Printf("Error deleting unlinked user: %v", deleteErr)
+		}
+		return sqlc.User{}, err
+	}
+
+	// An address the provider verified doesn't need to be verified again
+	if identity.EmailVerified {
+		return uc.repo.ActivateUser(uc.ctx, user.ID)
+	}
+	if err := uc.authUseCase.sendVerificationEmail(user); err != nil {
+		log.Printf("Error sending verification email: %v", err)
+	}
+
+	return user, nil
+}
+
+// linkIdentity records the provider account a user logs in with
+func (uc *OIDCUseCase) linkIdentity(user sqlc.User, providerName string, identity domain.OIDCIdentity) error {
+	createIdentityParams, err := identity.ToSqlcParams(user.ID, providerName)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.repo.CreateUserIdentity(uc.ctx, createIdentityParams); err != nil {
+		return fmt.Errorf("error linking identity: %w", err)
+	}
+
+	return nil
+}
+
+// availableUsername returns the username, or the username with a random suffix when it is taken
+func (uc *OIDCUseCase) availableUsername(username string) (string, error) {
+	candidate := username
+	for range oidcUsernameAttempts {
+		_, err := uc.repo.GetUserByUsername(uc.ctx, candidate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix, err := utils.GenerateSecureToken(oidcUsernameSuffixLen)
+		if err != nil {
+			return "", err
+		}
+		candidate = username + "_" + suffix
+	}
+
+	return "", ErrUsernameTaken
+}
+
+// link attaches the provider account to the user, linking it again to the same user is a no-op
+func (uc *OIDCUseCase) link(userID pgtype.UUID, providerName string, identity domain.OIDCIdentity) (domain.UserIdentity, error) {
+	existing, err := uc.repo.GetUserIdentity(uc.ctx, sqlc.GetUserIdentityParams{
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	})
+	if err == nil {
+		if existing.UserID != userID {
+			return domain.UserIdentity{}, ErrIdentityAlreadyLinked
+		}
+		return domain.NewUserIdentity(existing), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.UserIdentity{}, err
+	}
+
+	createIdentityParams, err := identity.ToSqlcParams(userID, providerName)
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+	created, err := uc.repo.CreateUserIdentity(uc.ctx, createIdentityParams)
+	if err != nil {
+		return domain.UserIdentity{}, fmt.Errorf("error linking identity: %w", err)
+	}
+
+	return domain.NewUserIdentity(created), nil
+}
@@ -0,0 +1,299 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/interfaces"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrInvalidPasskeySession    = errors.New("passkey session is invalid, expired or already used")
+	ErrPasskeyRegistration      = errors.New("passkey registration failed")
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+	ErrPasskeyLoginFailed       = errors.New("passkey login failed")
+	ErrPasskeyNotFound          = errors.New("passkey not found")
+)
+
+// WebAuthn ceremonies a session can be consumed by
+const (
+	passkeyCeremonyRegistration = "registration"
+	passkeyCeremonyLogin        = "login"
+	passkeySessionBytes         = 32
+)
+
+// PasskeyUseCase implements passkey registration and passwordless login with WebAuthn
+type PasskeyUseCase struct {
+	ctx           context.Context
+	repo          sqlc.Querier
+	authUseCase   *AuthUseCase
+	webAuthn      interfaces.WebAuthn
+	sessionExpiry time.Duration
+}
+
+// NewPasskeyUseCase creates a new passkey use case
+func NewPasskeyUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+	authUseCase *AuthUseCase,
+	webAuthn interfaces.WebAuthn,
+	sessionExpiry time.Duration,
+) *PasskeyUseCase {
+	return &PasskeyUseCase{
+		ctx:           ctx,
+		repo:          repo,
+		authUseCase:   authUseCase,
+		webAuthn:      webAuthn,
+		sessionExpiry: sessionExpiry,
+	}
+}
+
+// BeginRegistration starts registering a passkey for the user
+func (uc *PasskeyUseCase) BeginRegistration(userID uuid.UUID) (domain.PasskeyOptions, error) {
+	user, err := uc.passkeyUser(userID)
+	if err != nil {
+		return domain.PasskeyOptions{}, err
+	}
+
+	options, session, err := uc.webAuthn.BeginRegistration(user)
+	if err != nil {
+		return domain.PasskeyOptions{}, fmt.Errorf("error starting passkey registration: %w", err)
+	}
+
+	return uc.saveSession(passkeyCeremonyRegistration, pgtype.UUID{Bytes: userID, Valid: true}, options, session)
+}
+
+// FinishRegistration saves the passkey created by the authenticator, it can be used to log in right away
+func (uc *PasskeyUseCase) FinishRegistration(userID uuid.UUID, input domain.PasskeyRegistrationInput) (domain.Passkey, error) {
+	if err := input.Validate(); err != nil {
+		return domain.Passkey{}, err
+	}
+
+	session, err := uc.consumeSession(passkeyCeremonyRegistration, input.SessionID)
+	if err != nil {
+		return domain.Passkey{}, err
+	}
+	// The session must have been started by the same user
+	if session.UserID.Bytes != userID {
+		return domain.Passkey{}, ErrInvalidPasskeySession
+	}
+
+	user, err := uc.passkeyUser(userID)
+	if err != nil {
+		return domain.Passkey{}, err
+	}
+
+	credential, err := uc.webAuthn.FinishRegistration(user, session.Data, input.Credential)
+	if err != nil {
+		log.Printf("Error registering passkey: %v", err)
+		return domain.Passkey{}, ErrPasskeyRegistration
+	}
+
+	createPasskeyParams, err := input.ToSqlcParams(session.UserID, credential)
+	if err != nil {
+		return domain.Passkey{}, err
+	}
+	passkey, err := uc.repo.CreatePasskey(uc.ctx, createPasskeyParams)
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return domain.Passkey{}, ErrPasskeyAlreadyRegistered
+		}
+		return domain.Passkey{}, err
+	}
+
+	return domain.NewPasskey(passkey), nil
+}
+
+// ListPasskeys lists the passkeys of the user
+func (uc *PasskeyUseCase) ListPasskeys(userID uuid.UUID) ([]domain.Passkey, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := uc.repo.ListPasskeysByUser(uc.ctx, userPGUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Passkey, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		result = append(result, domain.NewPasskey(passkey))
+	}
+
+	return result, nil
+}
+
+// DeletePasskey removes a passkey of the user, it can no longer be used to log in
+func (uc *PasskeyUseCase) DeletePasskey(userID, passkeyID uuid.UUID) error {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+	passkeyPGUUID, err := utils.GeneratePGUUIDFromCustomId(passkeyID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := uc.repo.DeletePasskey(uc.ctx, sqlc.DeletePasskeyParams{
+		ID:     passkeyPGUUID,
+		UserID: userPGUUID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+// BeginLogin starts a passwordless login, the authenticator tells which user is logging in
+func (uc *PasskeyUseCase) BeginLogin() (domain.PasskeyOptions, error) {
+	options, session, err := uc.webAuthn.BeginLogin()
+	if err != nil {
+		return domain.PasskeyOptions{}, fmt.Errorf("error starting passkey login: %w", err)
+	}
+
+	return uc.saveSession(passkeyCeremonyLogin, pgtype.UUID{}, options, session)
+}
+
+// FinishLogin checks the assertion of the authenticator and starts a new session for its user.
+// Passkeys verify the user on the authenticator, so no second factor is asked for.
+func (uc *PasskeyUseCase) FinishLogin(input domain.PasskeyLoginInput, client domain.SessionClient) (*domain.AuthOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	session, err := uc.consumeSession(passkeyCeremonyLogin, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var user sqlc.User
+	credential, err := uc.webAuthn.FinishLogin(session.Data, input.Credential, func(handle []byte) (domain.PasskeyUser, error) {
+		userID, err := uuid.FromBytes(handle)
+		if err != nil {
+			return domain.PasskeyUser{}, err
+		}
+		user, err = uc.repo.GetUserByID(uc.ctx, pgtype.UUID{Bytes: userID, Valid: true})
+		if err != nil {
+			return domain.PasskeyUser{}, err
+		}
+		passkeys, err := uc.repo.ListPasskeysByUser(uc.ctx, user.ID)
+		if err != nil {
+			return domain.PasskeyUser{}, err
+		}
+		return domain.NewPasskeyUser(user, passkeys), nil
+	})
+	if err != nil {
+		log.Printf("Error verifying passkey login: %v", err)
+		return nil, ErrPasskeyLoginFailed
+	}
+
+	passkey, err := uc.repo.GetPasskeyByCredentialID(uc.ctx, credential.CredentialID)
+	if err != nil {
+		return nil, ErrPasskeyLoginFailed
+	}
+
+	// A signature counter that didn't move forward means the assertion was replayed or the authenticator cloned
+	if credential.CloneWarning {
+		log.Printf("Passkey %s presented a signature counter that didn't increase", uuid.UUID(passkey.ID.Bytes))
+		return nil, ErrPasskeyLoginFailed
+	}
+	recorded, err := uc.repo.RecordPasskeyUse(uc.ctx, sqlc.RecordPasskeyUseParams{
+		ID:          passkey.ID,
+		SignCount:   int64(credential.SignCount),
+		BackupState: credential.BackupState,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if recorded == 0 {
+		return nil, ErrPasskeyLoginFailed
+	}
+
+	accountKey, _ := loginThrottleKeys(user.Username, "")
+	return uc.authUseCase.completeLogin(user, accountKey, domain.AllScopes, client)
+}
+
+// passkeyUser loads the user along with their passkeys
+func (uc *PasskeyUseCase) passkeyUser(userID uuid.UUID) (domain.PasskeyUser, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return domain.PasskeyUser{}, err
+	}
+
+	user, err := uc.repo.GetUserByID(uc.ctx, userPGUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PasskeyUser{}, ErrUserNotFound
+		}
+		return domain.PasskeyUser{}, err
+	}
+
+	passkeys, err := uc.repo.ListPasskeysByUser(uc.ctx, user.ID)
+	if err != nil {
+		return domain.PasskeyUser{}, err
+	}
+
+	return domain.NewPasskeyUser(user, passkeys), nil
+}
+
+// saveSession keeps the state of a ceremony until the client sends back the result of the authenticator
+func (uc *PasskeyUseCase) saveSession(ceremony string, userID pgtype.UUID, options, session []byte) (domain.PasskeyOptions, error) {
+	// Ceremonies that were never completed are dropped along the way
+	if err := uc.repo.DeleteExpiredWebAuthnSessions(uc.ctx); err != nil {
+		log.Printf("Error deleting expired passkey sessions: %v", err)
+	}
+
+	sessionID, err := utils.GenerateSecureToken(passkeySessionBytes)
+	if err != nil {
+		return domain.PasskeyOptions{}, fmt.Errorf("error generating passkey session: %w", err)
+	}
+
+	err = uc.repo.CreateWebAuthnSession(uc.ctx, sqlc.CreateWebAuthnSessionParams{
+		SessionHash: utils.HashToken(sessionID),
+		Ceremony:    ceremony,
+		UserID:      userID,
+		Data:        session,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(uc.sessionExpiry),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return domain.PasskeyOptions{}, fmt.Errorf("error saving passkey session: %w", err)
+	}
+
+	return domain.PasskeyOptions{
+		SessionID: sessionID,
+		Options:   options,
+	}, nil
+}
+
+// consumeSession returns the state of a ceremony, a session can only be used once
+func (uc *PasskeyUseCase) consumeSession(ceremony, sessionID string) (sqlc.WebauthnSession, error) {
+	session, err := uc.repo.ConsumeWebAuthnSession(uc.ctx, sqlc.ConsumeWebAuthnSessionParams{
+		SessionHash: utils.HashToken(sessionID),
+		Ceremony:    ceremony,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.WebauthnSession{}, ErrInvalidPasskeySession
+		}
+		return sqlc.WebauthnSession{}, err
+	}
+
+	return session, nil
+}
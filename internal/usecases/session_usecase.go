@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// SessionUseCase lets users see where they are logged in and end those sessions remotely
+type SessionUseCase struct {
+	ctx         context.Context
+	repo        sqlc.Querier
+	authUseCase *AuthUseCase
+}
+
+// NewSessionUseCase creates a new session use case
+func NewSessionUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+	authUseCase *AuthUseCase,
+) *SessionUseCase {
+	return &SessionUseCase{
+		ctx:         ctx,
+		repo:        repo,
+		authUseCase: authUseCase,
+	}
+}
+
+// ListSessions lists the active sessions of the user of the token, flagging the one the token belongs to
+func (uc *SessionUseCase) ListSessions(payload *domain.TokenPayload) ([]domain.Session, error) {
+	userID, err := uc.authUseCase.tokenMaker.ParseUserID(payload)
+	if err != nil {
+		return nil, err
+	}
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := uc.repo.ListActiveSessionsByUser(uc.ctx, userPGUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentSessionID := tokenSessionID(payload)
+	result := make([]domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, domain.NewSession(session, currentSessionID.Valid && session.ID == currentSessionID))
+	}
+
+	return result, nil
+}
+
+// RevokeSession ends a session of the user, its tokens stop working right away
+func (uc *SessionUseCase) RevokeSession(userID, sessionID uuid.UUID) error {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+	sessionPGUUID, err := utils.GeneratePGUUIDFromCustomId(sessionID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := uc.repo.RevokeUserSession(uc.ctx, sqlc.RevokeUserSessionParams{
+		ID:     sessionPGUUID,
+		UserID: userPGUUID,
+	})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrSessionNotFound
+	}
+
+	return uc.authUseCase.revokeFamily(sessionPGUUID)
+}
+
+// RevokeOtherSessions ends every session of the user of the token except the one the token belongs to
+func (uc *SessionUseCase) RevokeOtherSessions(payload *domain.TokenPayload) error {
+	userID, err := uc.authUseCase.tokenMaker.ParseUserID(payload)
+	if err != nil {
+		return err
+	}
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return err
+	}
+
+	return uc.authUseCase.revokeUserSessions(userPGUUID, tokenSessionID(payload))
+}
+
+// tokenSessionID returns the session of an access token, API keys and older tokens have none
+func tokenSessionID(payload *domain.TokenPayload) pgtype.UUID {
+	sessionID, err := uuid.Parse(payload.SessionID)
+	if err != nil {
+		return pgtype.UUID{}
+	}
+
+	return pgtype.UUID{
+		Bytes: sessionID,
+		Valid: true,
+	}
+}
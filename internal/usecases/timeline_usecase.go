@@ -0,0 +1,188 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrTimelineEventCreation = errors.New("error creating timeline event")
+	ErrTimelineEventNotFound = errors.New("timeline event not found")
+)
+
+// TimelineUseCase implements the campaign timeline business logic
+type TimelineUseCase struct {
+	ctx  context.Context
+	repo sqlc.Querier
+}
+
+// NewTimelineUseCase creates a new timeline use case
+func NewTimelineUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+) *TimelineUseCase {
+	return &TimelineUseCase{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+// CreateTimelineEvent creates a timeline event if the requester has GM permissions
+func (uc *TimelineUseCase) CreateTimelineEvent(campaignID, requesterID uuid.UUID, input domain.TimelineEventInput) (sqlc.TimelineEvent, error) {
+	if err := input.Validate(); err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	createTimelineEventParams, err := input.ToSqlcParams(campaignPGUUID, requesterPGUUID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+	event, err := uc.repo.CreateTimelineEvent(uc.ctx, createTimelineEventParams)
+	if err != nil {
+		log.Printf("Error saving timeline event: %v", err)
+		return sqlc.TimelineEvent{}, ErrTimelineEventCreation
+	}
+
+	return event, nil
+}
+
+// ListTimelineEvents lists the timeline of a campaign in chronological order.
+// GMs see every event while players only see the public ones.
+func (uc *TimelineUseCase) ListTimelineEvents(campaignID, requesterID uuid.UUID, filter domain.TimelineEventFilter) ([]sqlc.TimelineEvent, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListTimelineEvents(uc.ctx, filter.ToSqlcParams(campaignPGUUID, member.Role == sqlc.MemberRoleGm))
+}
+
+// GetTimelineEvent retrieves a timeline event. Secret events are reported as missing to players.
+func (uc *TimelineUseCase) GetTimelineEvent(campaignID, eventID, requesterID uuid.UUID) (sqlc.TimelineEvent, error) {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+	eventPGUUID, err := utils.GeneratePGUUIDFromCustomId(eventID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	member, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRolePlayer)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	event, err := uc.repo.GetTimelineEventByID(uc.ctx, sqlc.GetTimelineEventByIDParams{
+		ID:         eventPGUUID,
+		CampaignID: campaignPGUUID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.TimelineEvent{}, ErrTimelineEventNotFound
+		}
+		return sqlc.TimelineEvent{}, err
+	}
+
+	if !event.IsPublic && member.Role != sqlc.MemberRoleGm {
+		return sqlc.TimelineEvent{}, ErrTimelineEventNotFound
+	}
+
+	return event, nil
+}
+
+// UpdateTimelineEvent replaces the details of a timeline event if the requester has GM permissions
+func (uc *TimelineUseCase) UpdateTimelineEvent(campaignID, eventID, requesterID uuid.UUID, input domain.TimelineEventInput) (sqlc.TimelineEvent, error) {
+	if err := input.Validate(); err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+	eventPGUUID, err := utils.GeneratePGUUIDFromCustomId(eventID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return sqlc.TimelineEvent{}, err
+	}
+
+	event, err := uc.repo.UpdateTimelineEvent(uc.ctx, input.ToSqlcUpdateParams(eventPGUUID, campaignPGUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.TimelineEvent{}, ErrTimelineEventNotFound
+		}
+		return sqlc.TimelineEvent{}, err
+	}
+
+	return event, nil
+}
+
+// DeleteTimelineEvent deletes a timeline event if the requester has GM permissions
+func (uc *TimelineUseCase) DeleteTimelineEvent(campaignID, eventID, requesterID uuid.UUID) error {
+	campaignPGUUID, err := utils.GeneratePGUUIDFromCustomId(campaignID)
+	if err != nil {
+		return err
+	}
+	eventPGUUID, err := utils.GeneratePGUUIDFromCustomId(eventID)
+	if err != nil {
+		return err
+	}
+	requesterPGUUID, err := utils.GeneratePGUUIDFromCustomId(requesterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := loadCampaignMember(uc.ctx, uc.repo, campaignPGUUID, requesterPGUUID, sqlc.MemberRoleGm); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteTimelineEvent(uc.ctx, sqlc.DeleteTimelineEventParams{
+		ID:         eventPGUUID,
+		CampaignID: campaignPGUUID,
+	})
+}
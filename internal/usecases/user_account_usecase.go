@@ -0,0 +1,195 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+// ExportAccount gathers everything the user owns: their profile, the campaigns they created, their characters,
+// the timeline events they wrote, the invitations they sent and, once their email is verified, the ones they received
+func (uc *UserUseCase) ExportAccount(userID uuid.UUID) (domain.AccountExport, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+
+	user, err := uc.repo.GetUserByID(uc.ctx, userPGUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.AccountExport{}, ErrUserNotFound
+		}
+		return domain.AccountExport{}, err
+	}
+
+	campaigns, err := uc.repo.ListCampaignsCreatedByUser(uc.ctx, user.ID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+	characters, err := uc.repo.ListCharactersByUser(uc.ctx, user.ID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+	timelineEvents, err := uc.repo.ListTimelineEventsByCreator(uc.ctx, user.ID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+	invitationsSent, err := uc.repo.ListInvitationsSentByUser(uc.ctx, user.ID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+	// Invitations are only tied to the user once they proved they own the address
+	invitationsReceived := []sqlc.ListInvitationsReceivedByEmailRow{}
+	if user.IsActive {
+		invitationsReceived, err = uc.repo.ListInvitationsReceivedByEmail(uc.ctx, user.Email)
+		if err != nil {
+			return domain.AccountExport{}, err
+		}
+	}
+
+	return domain.AccountExport{
+		ExportedAt:          time.Now().UTC(),
+		Profile:             domain.NewUserProfile(user),
+		Campaigns:           campaigns,
+		Characters:          characters,
+		TimelineEvents:      timelineEvents,
+		InvitationsSent:     invitationsSent,
+		InvitationsReceived: invitationsReceived,
+	}, nil
+}
+
+// DeleteAccount schedules the deletion of the account once the password is confirmed.
+// Every session and API key of the user is revoked right away, logging in again during the grace period
+// cancels the deletion.
+func (uc *UserUseCase) DeleteAccount(userID uuid.UUID, input domain.DeleteAccountInput) (domain.AccountDeletion, error) {
+	if err := input.Validate(); err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	user, err := uc.authUseCase.getUser(userID)
+	if err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	if err := uc.authUseCase.verifyAccountPassword(user, input.Password); err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	user, err = uc.repo.ScheduleUserDeletion(uc.ctx, sqlc.ScheduleUserDeletionParams{
+		ID: user.ID,
+		DeletionScheduledAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(uc.deletionGracePeriod),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	if err := uc.authUseCase.revokeUserSessions(user.ID, pgtype.UUID{}); err != nil {
+		return domain.AccountDeletion{}, err
+	}
+	if err := uc.repo.RevokeUserAPIKeys(uc.ctx, user.ID); err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	if err := uc.sendDeletionEmail(user); err != nil {
+		log.Printf("Error sending account deletion email: %v", err)
+	}
+
+	return domain.AccountDeletion{DeletionScheduledAt: user.DeletionScheduledAt.Time}, nil
+}
+
+// PurgeDeletedAccounts deletes the accounts whose grace period is over and returns how many were deleted
+func (uc *UserUseCase) PurgeDeletedAccounts() (int, error) {
+	userIDs, err := uc.repo.ListUsersDueForDeletion(uc.ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		if err := uc.purgeAccount(userID); err != nil {
+			return purged, fmt.Errorf("error purging account %s: %w", uuid.UUID(userID.Bytes), err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purgeAccount deletes an account along with what it owns. Campaigns other members play in are handed over
+// to one of them instead, so are the timeline events and invitations the user left in them.
+// Each step can be run again, so an interrupted purge is finished by the next one.
+func (uc *UserUseCase) purgeAccount(userID pgtype.UUID) error {
+	campaigns, err := uc.repo.ListCampaignsCreatedByUser(uc.ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, campaign := range campaigns {
+		successor, err := uc.repo.GetCampaignSuccessor(uc.ctx, sqlc.GetCampaignSuccessorParams{
+			CampaignID: campaign.ID,
+			UserID:     userID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Nobody else plays in it, it is deleted along with the account
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if successor.Role != sqlc.MemberRoleGm {
+			_, err = uc.repo.UpdateCampaignMember(uc.ctx, sqlc.UpdateCampaignMemberParams{
+				CampaignID: campaign.ID,
+				UserID:     successor.UserID,
+				Role:       sqlc.MemberRoleGm,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = uc.repo.TransferCampaign(uc.ctx, sqlc.TransferCampaignParams{
+			ID:        campaign.ID,
+			CreatedBy: successor.UserID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := uc.repo.HandOverTimelineEvents(uc.ctx, userID); err != nil {
+		return err
+	}
+	if err := uc.repo.HandOverInvitations(uc.ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteUser(uc.ctx, userID)
+}
+
+// sendDeletionEmail tells the user when their account will be deleted and how to keep it
+func (uc *UserUseCase) sendDeletionEmail(user sqlc.User) error {
+	return uc.authUseCase.mailer.Send(domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Your LoreCrafter account will be deleted",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n"+
+				"Your LoreCrafter account and everything it owns will be deleted on %s.\n"+
+				"Campaigns other members play in are handed over to one of them.\n\n"+
+				"Changed your mind? Log in before then to keep your account.\n",
+			user.Username,
+			user.DeletionScheduledAt.Time.UTC().Format(time.RFC1123),
+		),
+	})
+}
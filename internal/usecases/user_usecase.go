@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/knands42/lorecrafter/internal/utils"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+)
+
+var (
+	ErrUsernameTaken = errors.New("username is already taken")
+	ErrEmailTaken    = errors.New("email is already taken")
+)
+
+// UserUseCase implements the user profile business logic
+type UserUseCase struct {
+	ctx         context.Context
+	repo        sqlc.Querier
+	authUseCase *AuthUseCase
+	// How long deleted accounts are kept before they are purged
+	deletionGracePeriod time.Duration
+}
+
+// NewUserUseCase creates a new user use case
+func NewUserUseCase(
+	ctx context.Context,
+	repo sqlc.Querier,
+	authUseCase *AuthUseCase,
+	deletionGracePeriod time.Duration,
+) *UserUseCase {
+	return &UserUseCase{
+		ctx:                 ctx,
+		repo:                repo,
+		authUseCase:         authUseCase,
+		deletionGracePeriod: deletionGracePeriod,
+	}
+}
+
+// GetProfile retrieves the profile of the logged user
+func (uc *UserUseCase) GetProfile(userID uuid.UUID) (domain.UserProfile, error) {
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return domain.UserProfile{}, err
+	}
+
+	user, err := uc.repo.GetUserByID(uc.ctx, userPGUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserProfile{}, ErrUserNotFound
+		}
+		return domain.UserProfile{}, err
+	}
+
+	return domain.NewUserProfile(user), nil
+}
+
+// UpdateProfile changes the username, email or avatar of the logged user.
+// A new email address has to be verified again, so a verification email is sent to it.
+func (uc *UserUseCase) UpdateProfile(userID uuid.UUID, input domain.UpdateProfileInput) (domain.UserProfile, error) {
+	if err := input.Validate(); err != nil {
+		return domain.UserProfile{}, err
+	}
+
+	userPGUUID, err := utils.GeneratePGUUIDFromCustomId(userID)
+	if err != nil {
+		return domain.UserProfile{}, err
+	}
+
+	user, err := uc.repo.GetUserByID(uc.ctx, userPGUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserProfile{}, ErrUserNotFound
+		}
+		return domain.UserProfile{}, err
+	}
+
+	params := input.ToSqlcParams(user)
+	if params.Username != user.Username {
+		if _, err := uc.repo.GetUserByUsername(uc.ctx, params.Username); err == nil {
+			return domain.UserProfile{}, ErrUsernameTaken
+		}
+	}
+	emailChanged := params.Email != user.Email
+	if emailChanged {
+		if _, err := uc.repo.GetUserByEmail(uc.ctx, params.Email); err == nil {
+			return domain.UserProfile{}, ErrEmailTaken
+		}
+	}
+
+	updatedUser, err := uc.repo.UpdateUserProfile(uc.ctx, params)
+	if err != nil {
+		// Another account may have claimed the username or email since the checks above
+		if utils.IsUniqueViolation(err) {
+			if emailChanged {
+				return domain.UserProfile{}, ErrEmailTaken
+			}
+			return domain.UserProfile{}, ErrUsernameTaken
+		}
+		return domain.UserProfile{}, err
+	}
+
+	if emailChanged {
+		// The user can ask for another verification email if this one gets lost
+		if err := uc.authUseCase.sendVerificationEmail(updatedUser); err != nil {
+			log.Printf("Error sending verification email: %v", err)
+		}
+	}
+
+	return domain.NewUserProfile(updatedUser), nil
+}
+
+// GetPublicProfile retrieves the public profile of a user along with the public campaigns they are a member of
+func (uc *UserUseCase) GetPublicProfile(username string) (domain.PublicUserProfile, error) {
+	user, err := uc.repo.GetUserByUsername(uc.ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PublicUserProfile{}, ErrUserNotFound
+		}
+		return domain.PublicUserProfile{}, err
+	}
+
+	campaigns, err := uc.repo.ListPublicCampaignsByUserID(uc.ctx, user.ID)
+	if err != nil {
+		return domain.PublicUserProfile{}, err
+	}
+
+	return domain.NewPublicUserProfile(user, campaigns), nil
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"errors"
 	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type ValidationError struct {
@@ -18,3 +20,9 @@ func (e *ValidationError) Is(target error) bool {
 	ok := errors.As(target, &validationError)
 	return ok
 }
+
+// IsUniqueViolation reports whether err was raised by a Postgres unique constraint
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+)
+
+// InviteCodeAlphabet leaves out characters that are easily confused when read aloud or typed (0/O, 1/I/L)
+const InviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// GenerateRandomString returns a cryptographically random string of the given length drawn from alphabet
+func GenerateRandomString(length int, alphabet string) (string, error) {
+	maxIndex := big.NewInt(int64(len(alphabet)))
+	result := make([]byte, length)
+	for i := range result {
+		index, err := rand.Int(rand.Reader, maxIndex)
+		if err != nil {
+			return "", err
+		}
+		result[i] = alphabet[index.Int64()]
+	}
+
+	return string(result), nil
+}
+
+// GenerateSecureToken returns a hex encoded random token built from the given number of bytes
+func GenerateSecureToken(byteLength int) (string, error) {
+	token := make([]byte, byteLength)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
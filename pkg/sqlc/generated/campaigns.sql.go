@@ -11,6 +11,41 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archiveCampaign = `-- name: ArchiveCampaign :one
+UPDATE campaigns
+SET archived_at = coalesce(archived_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
+`
+
+func (q *Queries) ArchiveCampaign(ctx context.Context, id pgtype.UUID) (Campaign, error) {
+	row := q.db.QueryRow(ctx, archiveCampaign, id)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.SettingSummary,
+		&i.Setting,
+		&i.ImageUrl,
+		&i.IsPublic,
+		&i.InviteCode,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
 const createCampaign = `-- name: CreateCampaign :one
 INSERT INTO campaigns (
     id,
@@ -19,10 +54,13 @@ INSERT INTO campaigns (
     setting,
     image_url,
     is_public,
-    created_by
+    created_by,
+    tags,
+    game_system,
+    cloned_from
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
 `
 
 type CreateCampaignParams struct {
@@ -33,6 +71,9 @@ type CreateCampaignParams struct {
 	ImageUrl       pgtype.Text `json:"image_url"`
 	IsPublic       bool        `json:"is_public"`
 	CreatedBy      pgtype.UUID `json:"created_by"`
+	Tags           []string    `json:"tags"`
+	GameSystem     pgtype.Text `json:"game_system"`
+	ClonedFrom     pgtype.UUID `json:"cloned_from"`
 }
 
 func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams) (Campaign, error) {
@@ -44,6 +85,9 @@ func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams)
 		arg.ImageUrl,
 		arg.IsPublic,
 		arg.CreatedBy,
+		arg.Tags,
+		arg.GameSystem,
+		arg.ClonedFrom,
 	)
 	var i Campaign
 	err := row.Scan(
@@ -57,6 +101,15 @@ func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams)
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -99,29 +152,29 @@ func (q *Queries) CreateCampaignMember(ctx context.Context, arg CreateCampaignMe
 }
 
 const deleteCampaign = `-- name: DeleteCampaign :exec
-DELETE FROM campaigns AS c
-USING campaign_members AS cm
-WHERE cm.campaign_id = c.id
-  AND c.id = $1
-  AND (
-     (cm.user_id = $2 AND cm.role = 'gm'::member_role)
-     OR c.created_by = $2
-     )
+DELETE FROM campaigns
+WHERE id = $1
 `
 
-type DeleteCampaignParams struct {
-	ID     pgtype.UUID `json:"id"`
-	UserID pgtype.UUID `json:"user_id"`
-}
-
-func (q *Queries) DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) error {
-	_, err := q.db.Exec(ctx, deleteCampaign, arg.ID, arg.UserID)
+func (q *Queries) DeleteCampaign(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteCampaign, id)
 	return err
 }
 
-const deleteCampaignMember = `-- name: DeleteCampaignMember :exec
+const deleteCampaignMember = `-- name: DeleteCampaignMember :execrows
 DELETE FROM campaign_members
-WHERE campaign_id = $1 AND user_id = $2
+WHERE campaign_members.campaign_id = $1
+  AND campaign_members.user_id = $2
+  AND (
+    campaign_members.role <> 'gm'
+    OR (
+        SELECT COUNT(*) FROM (
+            SELECT 1 FROM campaign_members gms
+            WHERE gms.campaign_id = $1 AND gms.role = 'gm'
+            FOR UPDATE
+        ) locked_gms
+    ) > 1
+  )
 `
 
 type DeleteCampaignMemberParams struct {
@@ -129,25 +182,41 @@ type DeleteCampaignMemberParams struct {
 	UserID     pgtype.UUID `json:"user_id"`
 }
 
-func (q *Queries) DeleteCampaignMember(ctx context.Context, arg DeleteCampaignMemberParams) error {
-	_, err := q.db.Exec(ctx, deleteCampaignMember, arg.CampaignID, arg.UserID)
-	return err
+// Deletes a membership unless it is the last GM of the campaign. The GM rows are locked so two GMs removed
+// at the same time can't both see the other one as remaining.
+func (q *Queries) DeleteCampaignMember(ctx context.Context, arg DeleteCampaignMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCampaignMember, arg.CampaignID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const generateInviteCode = `-- name: GenerateInviteCode :one
 UPDATE campaigns
-SET invite_code = $2
+SET
+    invite_code = $2,
+    invite_code_expires_at = $3,
+    invite_code_max_uses = $4,
+    invite_code_uses = 0
 WHERE id = $1
-RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at
+RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
 `
 
 type GenerateInviteCodeParams struct {
-	ID         pgtype.UUID `json:"id"`
-	InviteCode pgtype.Text `json:"invite_code"`
+	ID                  pgtype.UUID        `json:"id"`
+	InviteCode          pgtype.Text        `json:"invite_code"`
+	InviteCodeExpiresAt pgtype.Timestamptz `json:"invite_code_expires_at"`
+	InviteCodeMaxUses   pgtype.Int4        `json:"invite_code_max_uses"`
 }
 
 func (q *Queries) GenerateInviteCode(ctx context.Context, arg GenerateInviteCodeParams) (Campaign, error) {
-	row := q.db.QueryRow(ctx, generateInviteCode, arg.ID, arg.InviteCode)
+	row := q.db.QueryRow(ctx, generateInviteCode,
+		arg.ID,
+		arg.InviteCode,
+		arg.InviteCodeExpiresAt,
+		arg.InviteCodeMaxUses,
+	)
 	var i Campaign
 	err := row.Scan(
 		&i.ID,
@@ -160,22 +229,21 @@ func (q *Queries) GenerateInviteCode(ctx context.Context, arg GenerateInviteCode
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getCampaignByID = `-- name: GetCampaignByID :one
-SELECT
-    c.id,
-    c.title,
-    c.setting_summary,
-    c.setting,
-    c.image_url,
-    c.is_public,
-    c.invite_code,
-    c.created_by,
-    c.created_at,
-    c.updated_at
+SELECT c.id, c.title, c.setting_summary, c.setting, c.image_url, c.is_public, c.invite_code, c.created_by, c.created_at, c.updated_at, c.invite_code_expires_at, c.invite_code_max_uses, c.invite_code_uses, c.tags, c.game_system, c.clone_count, c.cloned_from, c.archived_at, c.version
     FROM campaigns as c
                   LEFT JOIN campaign_members as cm
                             ON c.id = cm.campaign_id AND cm.user_id = $2
@@ -204,12 +272,21 @@ func (q *Queries) GetCampaignByID(ctx context.Context, arg GetCampaignByIDParams
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getCampaignByInviteCode = `-- name: GetCampaignByInviteCode :one
-SELECT id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at FROM campaigns
+SELECT id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version FROM campaigns
 WHERE invite_code = $1
 LIMIT 1
 `
@@ -228,6 +305,15 @@ func (q *Queries) GetCampaignByInviteCode(ctx context.Context, inviteCode pgtype
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -257,20 +343,80 @@ func (q *Queries) GetCampaignMember(ctx context.Context, arg GetCampaignMemberPa
 	return i, err
 }
 
-const listCampaignMembers = `-- name: ListCampaignMembers :many
+const getCampaignSuccessor = `-- name: GetCampaignSuccessor :one
 SELECT id, campaign_id, user_id, role, joined_at, last_accessed FROM campaign_members
-WHERE campaign_id = $1
+WHERE campaign_id = $1 AND user_id <> $2
+ORDER BY (role = 'gm'::member_role) DESC, joined_at
+LIMIT 1
+`
+
+type GetCampaignSuccessorParams struct {
+	CampaignID pgtype.UUID `json:"campaign_id"`
+	UserID     pgtype.UUID `json:"user_id"`
+}
+
+// The member taking over a campaign from its creator, game masters first then whoever joined earliest
+func (q *Queries) GetCampaignSuccessor(ctx context.Context, arg GetCampaignSuccessorParams) (CampaignMember, error) {
+	row := q.db.QueryRow(ctx, getCampaignSuccessor, arg.CampaignID, arg.UserID)
+	var i CampaignMember
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.UserID,
+		&i.Role,
+		&i.JoinedAt,
+		&i.LastAccessed,
+	)
+	return i, err
+}
+
+const incrementCampaignCloneCount = `-- name: IncrementCampaignCloneCount :exec
+UPDATE campaigns
+SET clone_count = clone_count + 1
+WHERE id = $1
 `
 
-func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID pgtype.UUID) ([]CampaignMember, error) {
+func (q *Queries) IncrementCampaignCloneCount(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, incrementCampaignCloneCount, id)
+	return err
+}
+
+const listCampaignMembers = `-- name: ListCampaignMembers :many
+SELECT
+    cm.id,
+    cm.campaign_id,
+    cm.user_id,
+    cm.role,
+    cm.joined_at,
+    cm.last_accessed,
+    u.username,
+    u.avatar_url
+FROM campaign_members AS cm
+JOIN users AS u ON u.id = cm.user_id
+WHERE cm.campaign_id = $1
+ORDER BY cm.joined_at
+`
+
+type ListCampaignMembersRow struct {
+	ID           pgtype.UUID        `json:"id"`
+	CampaignID   pgtype.UUID        `json:"campaign_id"`
+	UserID       pgtype.UUID        `json:"user_id"`
+	Role         MemberRole         `json:"role"`
+	JoinedAt     pgtype.Timestamptz `json:"joined_at"`
+	LastAccessed pgtype.Timestamptz `json:"last_accessed"`
+	Username     string             `json:"username"`
+	AvatarUrl    pgtype.Text        `json:"avatar_url"`
+}
+
+func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID pgtype.UUID) ([]ListCampaignMembersRow, error) {
 	rows, err := q.db.Query(ctx, listCampaignMembers, campaignID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []CampaignMember{}
+	items := []ListCampaignMembersRow{}
 	for rows.Next() {
-		var i CampaignMember
+		var i ListCampaignMembersRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CampaignID,
@@ -278,6 +424,8 @@ func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID pgtype.UUI
 			&i.Role,
 			&i.JoinedAt,
 			&i.LastAccessed,
+			&i.Username,
+			&i.AvatarUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -289,14 +437,14 @@ func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID pgtype.UUI
 	return items, nil
 }
 
-const listCampaignsByUserID = `-- name: ListCampaignsByUserID :many
-SELECT c.id, c.title, c.setting_summary, c.setting, c.image_url, c.is_public, c.invite_code, c.created_by, c.created_at, c.updated_at FROM campaigns c
-JOIN campaign_members cm ON c.id = cm.campaign_id
-WHERE cm.user_id = $1
+const listCampaignsCreatedByUser = `-- name: ListCampaignsCreatedByUser :many
+SELECT id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version FROM campaigns
+WHERE created_by = $1
+ORDER BY created_at
 `
 
-func (q *Queries) ListCampaignsByUserID(ctx context.Context, userID pgtype.UUID) ([]Campaign, error) {
-	rows, err := q.db.Query(ctx, listCampaignsByUserID, userID)
+func (q *Queries) ListCampaignsCreatedByUser(ctx context.Context, createdBy pgtype.UUID) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, listCampaignsCreatedByUser, createdBy)
 	if err != nil {
 		return nil, err
 	}
@@ -315,6 +463,15 @@ func (q *Queries) ListCampaignsByUserID(ctx context.Context, userID pgtype.UUID)
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.InviteCodeExpiresAt,
+			&i.InviteCodeMaxUses,
+			&i.InviteCodeUses,
+			&i.Tags,
+			&i.GameSystem,
+			&i.CloneCount,
+			&i.ClonedFrom,
+			&i.ArchivedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -326,54 +483,533 @@ func (q *Queries) ListCampaignsByUserID(ctx context.Context, userID pgtype.UUID)
 	return items, nil
 }
 
-const updateCampaign = `-- name: UpdateCampaign :one
-UPDATE campaigns as c
-SET 
-    title = $3,
-    setting_summary = $4,
-    setting = $5,
-    image_url = $6,
-    is_public = $7,
-    updated_at = CURRENT_TIMESTAMP
-FROM campaign_members AS cm
-WHERE cm.campaign_id = c.id
-  AND c.id = $1
-  AND (
-      c.is_public = true OR
-      cm.user_id = $2
-    )
-RETURNING
+const listMemberCampaigns = `-- name: ListMemberCampaigns :many
+WITH matches AS (
+    SELECT
+        c.id,
+        c.title,
+        c.setting_summary,
+        c.image_url,
+        c.is_public,
+        c.tags,
+        c.game_system,
+        c.created_by,
+        c.created_at,
+        c.updated_at,
+        c.archived_at,
+        cm.role,
+        cm.joined_at,
+        cm.last_accessed
+    FROM campaigns AS c
+    JOIN campaign_members AS cm ON cm.campaign_id = c.id
+    WHERE cm.user_id = $4
+      AND ($5::member_role IS NULL OR cm.role = $5::member_role)
+      AND (c.archived_at IS NOT NULL) = $6::boolean
+      AND ($7::text IS NULL
+        OR position(lower($7::text) IN lower(c.title || ' ' || coalesce(c.setting_summary, ''))) > 0)
+), keyed AS (
+    SELECT
+        matches.id, matches.title, matches.setting_summary, matches.image_url, matches.is_public, matches.tags, matches.game_system, matches.created_by, matches.created_at, matches.updated_at, matches.archived_at, matches.role, matches.joined_at, matches.last_accessed,
+        (CASE $8::text
+            WHEN 'last_accessed' THEN extract(epoch FROM coalesce(matches.last_accessed, matches.joined_at)) * 1000000
+            ELSE extract(epoch FROM matches.updated_at) * 1000000
+        END)::bigint AS sort_key
+    FROM matches
+)
+SELECT id, title, setting_summary, image_url, is_public, tags, game_system, created_by, created_at, updated_at, archived_at, role, joined_at, last_accessed, sort_key FROM keyed
+WHERE $1::uuid IS NULL
+   OR (keyed.sort_key, keyed.id) < ($2::bigint, $1::uuid)
+ORDER BY keyed.sort_key DESC, keyed.id DESC
+LIMIT $3
+`
+
+type ListMemberCampaignsParams struct {
+	CursorID  pgtype.UUID    `json:"cursor_id"`
+	CursorKey pgtype.Int8    `json:"cursor_key"`
+	PageSize  int32          `json:"page_size"`
+	UserID    pgtype.UUID    `json:"user_id"`
+	Role      NullMemberRole `json:"role"`
+	Archived  bool           `json:"archived"`
+	Query     pgtype.Text    `json:"query"`
+	Sort      string         `json:"sort"`
+}
+
+type ListMemberCampaignsRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	Title          string             `json:"title"`
+	SettingSummary pgtype.Text        `json:"setting_summary"`
+	ImageUrl       pgtype.Text        `json:"image_url"`
+	IsPublic       bool               `json:"is_public"`
+	Tags           []string           `json:"tags"`
+	GameSystem     pgtype.Text        `json:"game_system"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ArchivedAt     pgtype.Timestamptz `json:"archived_at"`
+	Role           MemberRole         `json:"role"`
+	JoinedAt       pgtype.Timestamptz `json:"joined_at"`
+	LastAccessed   pgtype.Timestamptz `json:"last_accessed"`
+	SortKey        int64              `json:"sort_key"`
+}
+
+// Lists the campaigns a user is a member of along with their role, sorted by sort_key then ID, both descending.
+// The sort key and ID of the last campaign of a page are the cursor of the next one.
+func (q *Queries) ListMemberCampaigns(ctx context.Context, arg ListMemberCampaignsParams) ([]ListMemberCampaignsRow, error) {
+	rows, err := q.db.Query(ctx, listMemberCampaigns,
+		arg.CursorID,
+		arg.CursorKey,
+		arg.PageSize,
+		arg.UserID,
+		arg.Role,
+		arg.Archived,
+		arg.Query,
+		arg.Sort,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMemberCampaignsRow{}
+	for rows.Next() {
+		var i ListMemberCampaignsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.SettingSummary,
+			&i.ImageUrl,
+			&i.IsPublic,
+			&i.Tags,
+			&i.GameSystem,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.Role,
+			&i.JoinedAt,
+			&i.LastAccessed,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicCampaignsByUserID = `-- name: ListPublicCampaignsByUserID :many
+SELECT
     c.id,
     c.title,
     c.setting_summary,
-    c.setting,
     c.image_url,
-    c.is_public,
-    c.invite_code,
+    c.tags,
+    c.game_system,
     c.created_by,
     c.created_at,
     c.updated_at
+FROM campaigns c
+JOIN campaign_members cm ON c.id = cm.campaign_id
+WHERE cm.user_id = $1 AND c.is_public = true
+ORDER BY c.created_at DESC
+`
+
+type ListPublicCampaignsByUserIDRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	Title          string             `json:"title"`
+	SettingSummary pgtype.Text        `json:"setting_summary"`
+	ImageUrl       pgtype.Text        `json:"image_url"`
+	Tags           []string           `json:"tags"`
+	GameSystem     pgtype.Text        `json:"game_system"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+// The setting and invite code are left out, they are only shown to members
+func (q *Queries) ListPublicCampaignsByUserID(ctx context.Context, userID pgtype.UUID) ([]ListPublicCampaignsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, listPublicCampaignsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPublicCampaignsByUserIDRow{}
+	for rows.Next() {
+		var i ListPublicCampaignsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.SettingSummary,
+			&i.ImageUrl,
+			&i.Tags,
+			&i.GameSystem,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const patchCampaign = `-- name: PatchCampaign :one
+UPDATE campaigns
+SET title = $1,
+    setting_summary = $2,
+    setting = $3,
+    image_url = $4,
+    is_public = $5,
+    tags = $6,
+    game_system = $7,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $8 AND version = $9
+RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
+`
+
+type PatchCampaignParams struct {
+	Title          string      `json:"title"`
+	SettingSummary pgtype.Text `json:"setting_summary"`
+	Setting        pgtype.Text `json:"setting"`
+	ImageUrl       pgtype.Text `json:"image_url"`
+	IsPublic       bool        `json:"is_public"`
+	Tags           []string    `json:"tags"`
+	GameSystem     pgtype.Text `json:"game_system"`
+	ID             pgtype.UUID `json:"id"`
+	Version        int32       `json:"version"`
+}
+
+// Only applies when the campaign is still at the version the patch was computed from
+func (q *Queries) PatchCampaign(ctx context.Context, arg PatchCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, patchCampaign,
+		arg.Title,
+		arg.SettingSummary,
+		arg.Setting,
+		arg.ImageUrl,
+		arg.IsPublic,
+		arg.Tags,
+		arg.GameSystem,
+		arg.ID,
+		arg.Version,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.SettingSummary,
+		&i.Setting,
+		&i.ImageUrl,
+		&i.IsPublic,
+		&i.InviteCode,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const redeemInviteCode = `-- name: RedeemInviteCode :one
+UPDATE campaigns
+SET invite_code_uses = invite_code_uses + 1
+WHERE invite_code = $1
+  AND (invite_code_expires_at IS NULL OR invite_code_expires_at > CURRENT_TIMESTAMP)
+  AND (invite_code_max_uses IS NULL OR invite_code_uses < invite_code_max_uses)
+RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
+`
+
+func (q *Queries) RedeemInviteCode(ctx context.Context, inviteCode pgtype.Text) (Campaign, error) {
+	row := q.db.QueryRow(ctx, redeemInviteCode, inviteCode)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.SettingSummary,
+		&i.Setting,
+		&i.ImageUrl,
+		&i.IsPublic,
+		&i.InviteCode,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const releaseInviteCodeUse = `-- name: ReleaseInviteCodeUse :exec
+UPDATE campaigns
+SET invite_code_uses = invite_code_uses - 1
+WHERE id = $1 AND invite_code = $2 AND invite_code_uses > 0
+`
+
+type ReleaseInviteCodeUseParams struct {
+	ID         pgtype.UUID `json:"id"`
+	InviteCode pgtype.Text `json:"invite_code"`
+}
+
+// Gives back a use redeemed by a join that failed, unless the code was rotated since
+func (q *Queries) ReleaseInviteCodeUse(ctx context.Context, arg ReleaseInviteCodeUseParams) error {
+	_, err := q.db.Exec(ctx, releaseInviteCodeUse, arg.ID, arg.InviteCode)
+	return err
+}
+
+const revokeInviteCode = `-- name: RevokeInviteCode :exec
+UPDATE campaigns
+SET
+    invite_code = NULL,
+    invite_code_expires_at = NULL,
+    invite_code_max_uses = NULL,
+    invite_code_uses = 0
+WHERE id = $1
+`
+
+func (q *Queries) RevokeInviteCode(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeInviteCode, id)
+	return err
+}
+
+const searchPublicCampaigns = `-- name: SearchPublicCampaigns :many
+WITH matches AS (
+    SELECT
+        c.id,
+        c.title,
+        c.setting_summary,
+        c.image_url,
+        c.tags,
+        c.game_system,
+        c.clone_count,
+        c.created_by,
+        u.username AS creator_username,
+        (SELECT COUNT(*) FROM campaign_members AS cm WHERE cm.campaign_id = c.id) AS member_count,
+        c.created_at,
+        c.updated_at
+    FROM campaigns AS c
+    JOIN users AS u ON u.id = c.created_by
+    WHERE c.is_public
+      AND ($4::text IS NULL
+        OR to_tsvector('english', c.title || ' ' || coalesce(c.setting_summary, '') || ' ' || coalesce(c.setting, ''))
+           @@ websearch_to_tsquery('english', $4::text))
+      AND c.tags @> $5::text[]
+      AND ($6::text IS NULL OR lower(c.game_system) = lower($6::text))
+      AND ($7::text IS NULL OR u.username = $7::text)
+), keyed AS (
+    SELECT
+        matches.id, matches.title, matches.setting_summary, matches.image_url, matches.tags, matches.game_system, matches.clone_count, matches.created_by, matches.creator_username, matches.member_count, matches.created_at, matches.updated_at,
+        (CASE $8::text
+            WHEN 'most_cloned' THEN matches.clone_count::bigint
+            WHEN 'most_members' THEN matches.member_count
+            ELSE (extract(epoch FROM matches.created_at) * 1000000)::bigint
+        END)::bigint AS sort_key
+    FROM matches
+)
+SELECT id, title, setting_summary, image_url, tags, game_system, clone_count, created_by, creator_username, member_count, created_at, updated_at, sort_key FROM keyed
+WHERE $1::uuid IS NULL
+   OR (keyed.sort_key, keyed.id) < ($2::bigint, $1::uuid)
+ORDER BY keyed.sort_key DESC, keyed.id DESC
+LIMIT $3
+`
+
+type SearchPublicCampaignsParams struct {
+	CursorID   pgtype.UUID `json:"cursor_id"`
+	CursorKey  pgtype.Int8 `json:"cursor_key"`
+	PageSize   int32       `json:"page_size"`
+	Query      pgtype.Text `json:"query"`
+	Tags       []string    `json:"tags"`
+	GameSystem pgtype.Text `json:"game_system"`
+	Creator    pgtype.Text `json:"creator"`
+	Sort       string      `json:"sort"`
+}
+
+type SearchPublicCampaignsRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	Title           string             `json:"title"`
+	SettingSummary  pgtype.Text        `json:"setting_summary"`
+	ImageUrl        pgtype.Text        `json:"image_url"`
+	Tags            []string           `json:"tags"`
+	GameSystem      pgtype.Text        `json:"game_system"`
+	CloneCount      int32              `json:"clone_count"`
+	CreatedBy       pgtype.UUID        `json:"created_by"`
+	CreatorUsername string             `json:"creator_username"`
+	MemberCount     int64              `json:"member_count"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	SortKey         int64              `json:"sort_key"`
+}
+
+// Lists public campaigns matching the filters, sorted by sort_key then ID, both descending.
+// The sort key and ID of the last campaign of a page are the cursor of the next one.
+func (q *Queries) SearchPublicCampaigns(ctx context.Context, arg SearchPublicCampaignsParams) ([]SearchPublicCampaignsRow, error) {
+	rows, err := q.db.Query(ctx, searchPublicCampaigns,
+		arg.CursorID,
+		arg.CursorKey,
+		arg.PageSize,
+		arg.Query,
+		arg.Tags,
+		arg.GameSystem,
+		arg.Creator,
+		arg.Sort,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchPublicCampaignsRow{}
+	for rows.Next() {
+		var i SearchPublicCampaignsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.SettingSummary,
+			&i.ImageUrl,
+			&i.Tags,
+			&i.GameSystem,
+			&i.CloneCount,
+			&i.CreatedBy,
+			&i.CreatorUsername,
+			&i.MemberCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchCampaignMember = `-- name: TouchCampaignMember :exec
+UPDATE campaign_members
+SET last_accessed = CURRENT_TIMESTAMP
+WHERE campaign_id = $1 AND user_id = $2
+`
+
+type TouchCampaignMemberParams struct {
+	CampaignID pgtype.UUID `json:"campaign_id"`
+	UserID     pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) TouchCampaignMember(ctx context.Context, arg TouchCampaignMemberParams) error {
+	_, err := q.db.Exec(ctx, touchCampaignMember, arg.CampaignID, arg.UserID)
+	return err
+}
+
+const transferCampaign = `-- name: TransferCampaign :exec
+UPDATE campaigns
+SET created_by = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+`
+
+type TransferCampaignParams struct {
+	CreatedBy pgtype.UUID `json:"created_by"`
+	ID        pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) TransferCampaign(ctx context.Context, arg TransferCampaignParams) error {
+	_, err := q.db.Exec(ctx, transferCampaign, arg.CreatedBy, arg.ID)
+	return err
+}
+
+const unarchiveCampaign = `-- name: UnarchiveCampaign :one
+UPDATE campaigns
+SET archived_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
+`
+
+func (q *Queries) UnarchiveCampaign(ctx context.Context, id pgtype.UUID) (Campaign, error) {
+	row := q.db.QueryRow(ctx, unarchiveCampaign, id)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.SettingSummary,
+		&i.Setting,
+		&i.ImageUrl,
+		&i.IsPublic,
+		&i.InviteCode,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateCampaign = `-- name: UpdateCampaign :one
+UPDATE campaigns
+SET
+    title = $2,
+    setting_summary = $3,
+    setting = $4,
+    image_url = $5,
+    is_public = $6,
+    tags = $7,
+    game_system = $8,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, title, setting_summary, setting, image_url, is_public, invite_code, created_by, created_at, updated_at, invite_code_expires_at, invite_code_max_uses, invite_code_uses, tags, game_system, clone_count, cloned_from, archived_at, version
 `
 
 type UpdateCampaignParams struct {
 	ID             pgtype.UUID `json:"id"`
-	UserID         pgtype.UUID `json:"user_id"`
 	Title          string      `json:"title"`
 	SettingSummary pgtype.Text `json:"setting_summary"`
 	Setting        pgtype.Text `json:"setting"`
 	ImageUrl       pgtype.Text `json:"image_url"`
 	IsPublic       bool        `json:"is_public"`
+	Tags           []string    `json:"tags"`
+	GameSystem     pgtype.Text `json:"game_system"`
 }
 
 func (q *Queries) UpdateCampaign(ctx context.Context, arg UpdateCampaignParams) (Campaign, error) {
 	row := q.db.QueryRow(ctx, updateCampaign,
 		arg.ID,
-		arg.UserID,
 		arg.Title,
 		arg.SettingSummary,
 		arg.Setting,
 		arg.ImageUrl,
 		arg.IsPublic,
+		arg.Tags,
+		arg.GameSystem,
 	)
 	var i Campaign
 	err := row.Scan(
@@ -387,6 +1023,15 @@ func (q *Queries) UpdateCampaign(ctx context.Context, arg UpdateCampaignParams)
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.InviteCodeExpiresAt,
+		&i.InviteCodeMaxUses,
+		&i.InviteCodeUses,
+		&i.Tags,
+		&i.GameSystem,
+		&i.CloneCount,
+		&i.ClonedFrom,
+		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
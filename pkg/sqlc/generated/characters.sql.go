@@ -0,0 +1,275 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: characters.sql
+
+package sqlc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCharacter = `-- name: CreateCharacter :one
+INSERT INTO characters (
+    id, name, race, class, level, appearance, personality, backstory, image_url,
+    campaign_id, user_id, is_npc, metadata
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING id, name, race, class, level, appearance, personality, backstory, image_url, campaign_id, user_id, is_npc, metadata, created_at, updated_at
+`
+
+type CreateCharacterParams struct {
+	ID          pgtype.UUID     `json:"id"`
+	Name        string          `json:"name"`
+	Race        pgtype.Text     `json:"race"`
+	Class       pgtype.Text     `json:"class"`
+	Level       int32           `json:"level"`
+	Appearance  pgtype.Text     `json:"appearance"`
+	Personality pgtype.Text     `json:"personality"`
+	Backstory   pgtype.Text     `json:"backstory"`
+	ImageUrl    pgtype.Text     `json:"image_url"`
+	CampaignID  pgtype.UUID     `json:"campaign_id"`
+	UserID      pgtype.UUID     `json:"user_id"`
+	IsNpc       bool            `json:"is_npc"`
+	Metadata    json.RawMessage `json:"metadata" swaggertype:"object"`
+}
+
+func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams) (Character, error) {
+	row := q.db.QueryRow(ctx, createCharacter,
+		arg.ID,
+		arg.Name,
+		arg.Race,
+		arg.Class,
+		arg.Level,
+		arg.Appearance,
+		arg.Personality,
+		arg.Backstory,
+		arg.ImageUrl,
+		arg.CampaignID,
+		arg.UserID,
+		arg.IsNpc,
+		arg.Metadata,
+	)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Race,
+		&i.Class,
+		&i.Level,
+		&i.Appearance,
+		&i.Personality,
+		&i.Backstory,
+		&i.ImageUrl,
+		&i.CampaignID,
+		&i.UserID,
+		&i.IsNpc,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCharacter = `-- name: DeleteCharacter :exec
+DELETE FROM characters
+WHERE id = $1 AND campaign_id = $2
+`
+
+type DeleteCharacterParams struct {
+	ID         pgtype.UUID `json:"id"`
+	CampaignID pgtype.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) DeleteCharacter(ctx context.Context, arg DeleteCharacterParams) error {
+	_, err := q.db.Exec(ctx, deleteCharacter, arg.ID, arg.CampaignID)
+	return err
+}
+
+const getCharacterByID = `-- name: GetCharacterByID :one
+SELECT id, name, race, class, level, appearance, personality, backstory, image_url, campaign_id, user_id, is_npc, metadata, created_at, updated_at FROM characters
+WHERE id = $1 AND campaign_id = $2
+`
+
+type GetCharacterByIDParams struct {
+	ID         pgtype.UUID `json:"id"`
+	CampaignID pgtype.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) GetCharacterByID(ctx context.Context, arg GetCharacterByIDParams) (Character, error) {
+	row := q.db.QueryRow(ctx, getCharacterByID, arg.ID, arg.CampaignID)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Race,
+		&i.Class,
+		&i.Level,
+		&i.Appearance,
+		&i.Personality,
+		&i.Backstory,
+		&i.ImageUrl,
+		&i.CampaignID,
+		&i.UserID,
+		&i.IsNpc,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCharactersByCampaign = `-- name: ListCharactersByCampaign :many
+SELECT id, name, race, class, level, appearance, personality, backstory, image_url, campaign_id, user_id, is_npc, metadata, created_at, updated_at FROM characters
+WHERE campaign_id = $1
+ORDER BY is_npc, name
+`
+
+func (q *Queries) ListCharactersByCampaign(ctx context.Context, campaignID pgtype.UUID) ([]Character, error) {
+	rows, err := q.db.Query(ctx, listCharactersByCampaign, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Character{}
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Race,
+			&i.Class,
+			&i.Level,
+			&i.Appearance,
+			&i.Personality,
+			&i.Backstory,
+			&i.ImageUrl,
+			&i.CampaignID,
+			&i.UserID,
+			&i.IsNpc,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharactersByUser = `-- name: ListCharactersByUser :many
+SELECT id, name, race, class, level, appearance, personality, backstory, image_url, campaign_id, user_id, is_npc, metadata, created_at, updated_at FROM characters
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListCharactersByUser(ctx context.Context, userID pgtype.UUID) ([]Character, error) {
+	rows, err := q.db.Query(ctx, listCharactersByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Character{}
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Race,
+			&i.Class,
+			&i.Level,
+			&i.Appearance,
+			&i.Personality,
+			&i.Backstory,
+			&i.ImageUrl,
+			&i.CampaignID,
+			&i.UserID,
+			&i.IsNpc,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCharacter = `-- name: UpdateCharacter :one
+UPDATE characters
+SET name = $3,
+    race = $4,
+    class = $5,
+    level = $6,
+    appearance = $7,
+    personality = $8,
+    backstory = $9,
+    image_url = $10,
+    is_npc = $11,
+    metadata = $12,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND campaign_id = $2
+RETURNING id, name, race, class, level, appearance, personality, backstory, image_url, campaign_id, user_id, is_npc, metadata, created_at, updated_at
+`
+
+type UpdateCharacterParams struct {
+	ID          pgtype.UUID     `json:"id"`
+	CampaignID  pgtype.UUID     `json:"campaign_id"`
+	Name        string          `json:"name"`
+	Race        pgtype.Text     `json:"race"`
+	Class       pgtype.Text     `json:"class"`
+	Level       int32           `json:"level"`
+	Appearance  pgtype.Text     `json:"appearance"`
+	Personality pgtype.Text     `json:"personality"`
+	Backstory   pgtype.Text     `json:"backstory"`
+	ImageUrl    pgtype.Text     `json:"image_url"`
+	IsNpc       bool            `json:"is_npc"`
+	Metadata    json.RawMessage `json:"metadata" swaggertype:"object"`
+}
+
+func (q *Queries) UpdateCharacter(ctx context.Context, arg UpdateCharacterParams) (Character, error) {
+	row := q.db.QueryRow(ctx, updateCharacter,
+		arg.ID,
+		arg.CampaignID,
+		arg.Name,
+		arg.Race,
+		arg.Class,
+		arg.Level,
+		arg.Appearance,
+		arg.Personality,
+		arg.Backstory,
+		arg.ImageUrl,
+		arg.IsNpc,
+		arg.Metadata,
+	)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Race,
+		&i.Class,
+		&i.Level,
+		&i.Appearance,
+		&i.Personality,
+		&i.Backstory,
+		&i.ImageUrl,
+		&i.CampaignID,
+		&i.UserID,
+		&i.IsNpc,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,421 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: invitations.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createInvitation = `-- name: CreateInvitation :one
+INSERT INTO invitations (
+    id,
+    campaign_id,
+    email,
+    invited_by,
+    token,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, campaign_id, email, invited_by, token, status, expires_at, created_at, updated_at
+`
+
+type CreateInvitationParams struct {
+	ID         pgtype.UUID        `json:"id"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+	Email      string             `json:"email"`
+	InvitedBy  pgtype.UUID        `json:"invited_by"`
+	Token      string             `json:"token"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateInvitation(ctx context.Context, arg CreateInvitationParams) (Invitation, error) {
+	row := q.db.QueryRow(ctx, createInvitation,
+		arg.ID,
+		arg.CampaignID,
+		arg.Email,
+		arg.InvitedBy,
+		arg.Token,
+		arg.ExpiresAt,
+	)
+	var i Invitation
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Email,
+		&i.InvitedBy,
+		&i.Token,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteInvitation = `-- name: DeleteInvitation :execrows
+DELETE FROM invitations
+WHERE id = $1 AND campaign_id = $2
+`
+
+type DeleteInvitationParams struct {
+	ID         pgtype.UUID `json:"id"`
+	CampaignID pgtype.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) DeleteInvitation(ctx context.Context, arg DeleteInvitationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteInvitation, arg.ID, arg.CampaignID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const expireInvitations = `-- name: ExpireInvitations :exec
+UPDATE invitations
+SET
+    status = 'expired'::invitation_status,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'pending'::invitation_status
+  AND expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) ExpireInvitations(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, expireInvitations)
+	return err
+}
+
+const getInvitationByToken = `-- name: GetInvitationByToken :one
+SELECT id, campaign_id, email, invited_by, token, status, expires_at, created_at, updated_at FROM invitations
+WHERE token = $1
+LIMIT 1
+`
+
+func (q *Queries) GetInvitationByToken(ctx context.Context, token string) (Invitation, error) {
+	row := q.db.QueryRow(ctx, getInvitationByToken, token)
+	var i Invitation
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Email,
+		&i.InvitedBy,
+		&i.Token,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPendingInvitationByCampaignAndEmail = `-- name: GetPendingInvitationByCampaignAndEmail :one
+SELECT id, campaign_id, email, invited_by, token, status, expires_at, created_at, updated_at FROM invitations
+WHERE campaign_id = $1
+  AND lower(email) = lower($2)
+  AND status = 'pending'::invitation_status
+  AND expires_at > CURRENT_TIMESTAMP
+LIMIT 1
+`
+
+type GetPendingInvitationByCampaignAndEmailParams struct {
+	CampaignID pgtype.UUID `json:"campaign_id"`
+	Email      string      `json:"email"`
+}
+
+func (q *Queries) GetPendingInvitationByCampaignAndEmail(ctx context.Context, arg GetPendingInvitationByCampaignAndEmailParams) (Invitation, error) {
+	row := q.db.QueryRow(ctx, getPendingInvitationByCampaignAndEmail, arg.CampaignID, arg.Email)
+	var i Invitation
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Email,
+		&i.InvitedBy,
+		&i.Token,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const handOverInvitations = `-- name: HandOverInvitations :exec
+UPDATE invitations AS i
+SET invited_by = c.created_by,
+    updated_at = CURRENT_TIMESTAMP
+FROM campaigns AS c
+WHERE c.id = i.campaign_id
+  AND i.invited_by = $1
+  AND c.created_by <> $1
+`
+
+// Hands the invitations a user sent to campaigns created by someone else over to the campaign creator
+func (q *Queries) HandOverInvitations(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, handOverInvitations, userID)
+	return err
+}
+
+const listInvitationsByCampaign = `-- name: ListInvitationsByCampaign :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at
+FROM invitations AS i
+WHERE i.campaign_id = $1
+ORDER BY i.created_at DESC
+`
+
+type ListInvitationsByCampaignRow struct {
+	ID         pgtype.UUID        `json:"id"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+	Email      string             `json:"email"`
+	InvitedBy  pgtype.UUID        `json:"invited_by"`
+	Status     InvitationStatus   `json:"status"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) ListInvitationsByCampaign(ctx context.Context, campaignID pgtype.UUID) ([]ListInvitationsByCampaignRow, error) {
+	rows, err := q.db.Query(ctx, listInvitationsByCampaign, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInvitationsByCampaignRow{}
+	for rows.Next() {
+		var i ListInvitationsByCampaignRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Email,
+			&i.InvitedBy,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvitationsReceivedByEmail = `-- name: ListInvitationsReceivedByEmail :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at
+FROM invitations AS i
+WHERE lower(i.email) = lower($1)
+ORDER BY i.created_at
+`
+
+type ListInvitationsReceivedByEmailRow struct {
+	ID         pgtype.UUID        `json:"id"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+	Email      string             `json:"email"`
+	InvitedBy  pgtype.UUID        `json:"invited_by"`
+	Status     InvitationStatus   `json:"status"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) ListInvitationsReceivedByEmail(ctx context.Context, email string) ([]ListInvitationsReceivedByEmailRow, error) {
+	rows, err := q.db.Query(ctx, listInvitationsReceivedByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInvitationsReceivedByEmailRow{}
+	for rows.Next() {
+		var i ListInvitationsReceivedByEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Email,
+			&i.InvitedBy,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvitationsSentByUser = `-- name: ListInvitationsSentByUser :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at
+FROM invitations AS i
+WHERE i.invited_by = $1
+ORDER BY i.created_at
+`
+
+type ListInvitationsSentByUserRow struct {
+	ID         pgtype.UUID        `json:"id"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+	Email      string             `json:"email"`
+	InvitedBy  pgtype.UUID        `json:"invited_by"`
+	Status     InvitationStatus   `json:"status"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) ListInvitationsSentByUser(ctx context.Context, invitedBy pgtype.UUID) ([]ListInvitationsSentByUserRow, error) {
+	rows, err := q.db.Query(ctx, listInvitationsSentByUser, invitedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInvitationsSentByUserRow{}
+	for rows.Next() {
+		var i ListInvitationsSentByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Email,
+			&i.InvitedBy,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingInvitationsByEmail = `-- name: ListPendingInvitationsByEmail :many
+SELECT
+    i.id,
+    i.campaign_id,
+    i.email,
+    i.invited_by,
+    i.status,
+    i.expires_at,
+    i.created_at,
+    i.updated_at,
+    c.title AS campaign_title,
+    u.username AS invited_by_username
+FROM invitations AS i
+JOIN campaigns AS c ON c.id = i.campaign_id
+JOIN users AS u ON u.id = i.invited_by
+WHERE lower(i.email) = lower($1)
+  AND i.status = 'pending'::invitation_status
+ORDER BY i.created_at DESC
+`
+
+type ListPendingInvitationsByEmailRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	CampaignID        pgtype.UUID        `json:"campaign_id"`
+	Email             string             `json:"email"`
+	InvitedBy         pgtype.UUID        `json:"invited_by"`
+	Status            InvitationStatus   `json:"status"`
+	ExpiresAt         pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CampaignTitle     string             `json:"campaign_title"`
+	InvitedByUsername string             `json:"invited_by_username"`
+}
+
+// The token is left out, invitees open the link of the invitation email to answer it
+func (q *Queries) ListPendingInvitationsByEmail(ctx context.Context, email string) ([]ListPendingInvitationsByEmailRow, error) {
+	rows, err := q.db.Query(ctx, listPendingInvitationsByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPendingInvitationsByEmailRow{}
+	for rows.Next() {
+		var i ListPendingInvitationsByEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Email,
+			&i.InvitedBy,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CampaignTitle,
+			&i.InvitedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateInvitationStatus = `-- name: UpdateInvitationStatus :one
+UPDATE invitations
+SET
+    status = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, campaign_id, email, invited_by, token, status, expires_at, created_at, updated_at
+`
+
+type UpdateInvitationStatusParams struct {
+	ID     pgtype.UUID      `json:"id"`
+	Status InvitationStatus `json:"status"`
+}
+
+func (q *Queries) UpdateInvitationStatus(ctx context.Context, arg UpdateInvitationStatusParams) (Invitation, error) {
+	row := q.db.QueryRow(ctx, updateInvitationStatus, arg.ID, arg.Status)
+	var i Invitation
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Email,
+		&i.InvitedBy,
+		&i.Token,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: login_throttles.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearLoginThrottle = `-- name: ClearLoginThrottle :exec
+DELETE FROM login_throttles
+WHERE key = $1
+`
+
+func (q *Queries) ClearLoginThrottle(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, clearLoginThrottle, key)
+	return err
+}
+
+const deleteStaleLoginThrottles = `-- name: DeleteStaleLoginThrottles :exec
+DELETE FROM login_throttles
+WHERE GREATEST(updated_at, locked_until) < $1::timestamptz
+`
+
+func (q *Queries) DeleteStaleLoginThrottles(ctx context.Context, resetBefore pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteStaleLoginThrottles, resetBefore)
+	return err
+}
+
+const listLoginThrottles = `-- name: ListLoginThrottles :many
+SELECT key, failed_count, locked_until, updated_at FROM login_throttles
+WHERE key = ANY($1::text[])
+`
+
+func (q *Queries) ListLoginThrottles(ctx context.Context, keys []string) ([]LoginThrottle, error) {
+	rows, err := q.db.Query(ctx, listLoginThrottles, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LoginThrottle{}
+	for rows.Next() {
+		var i LoginThrottle
+		if err := rows.Scan(
+			&i.Key,
+			&i.FailedCount,
+			&i.LockedUntil,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLogin = `-- name: LockLogin :exec
+UPDATE login_throttles
+SET locked_until = $2
+WHERE key = $1
+`
+
+type LockLoginParams struct {
+	Key         string             `json:"key"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+func (q *Queries) LockLogin(ctx context.Context, arg LockLoginParams) error {
+	_, err := q.db.Exec(ctx, lockLogin, arg.Key, arg.LockedUntil)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+INSERT INTO login_throttles (
+    key,
+    failed_count
+) VALUES (
+    $1, 1
+)
+ON CONFLICT (key) DO UPDATE
+SET failed_count = CASE
+        WHEN GREATEST(login_throttles.updated_at, login_throttles.locked_until) < $2::timestamptz THEN 1
+        ELSE login_throttles.failed_count + 1
+    END,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING key, failed_count, locked_until, updated_at
+`
+
+type RecordLoginFailureParams struct {
+	Key         string             `json:"key"`
+	ResetBefore pgtype.Timestamptz `json:"reset_before"`
+}
+
+// Counts a failed attempt, starting over once the last failure and lockout are older than reset_before
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginThrottle, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.Key, arg.ResetBefore)
+	var i LoginThrottle
+	err := row.Scan(
+		&i.Key,
+		&i.FailedCount,
+		&i.LockedUntil,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
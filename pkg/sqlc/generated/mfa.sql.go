@@ -0,0 +1,160 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: mfa.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimMFAStep = `-- name: ClaimMFAStep :execrows
+UPDATE users
+SET mfa_last_used_step = $1::bigint
+WHERE id = $2
+  AND (mfa_last_used_step IS NULL OR mfa_last_used_step < $1::bigint)
+`
+
+type ClaimMFAStepParams struct {
+	Step int64       `json:"step"`
+	ID   pgtype.UUID `json:"id"`
+}
+
+// Records the time step of an accepted TOTP code, failing if it or a later one was already used
+func (q *Queries) ClaimMFAStep(ctx context.Context, arg ClaimMFAStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimMFAStep, arg.Step, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createMFARecoveryCode = `-- name: CreateMFARecoveryCode :exec
+INSERT INTO mfa_recovery_codes (
+    id,
+    user_id,
+    code_hash
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type CreateMFARecoveryCodeParams struct {
+	ID       pgtype.UUID `json:"id"`
+	UserID   pgtype.UUID `json:"user_id"`
+	CodeHash string      `json:"code_hash"`
+}
+
+func (q *Queries) CreateMFARecoveryCode(ctx context.Context, arg CreateMFARecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createMFARecoveryCode, arg.ID, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteMFARecoveryCodes = `-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteMFARecoveryCodes(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteMFARecoveryCodes, userID)
+	return err
+}
+
+const disableUserMFA = `-- name: DisableUserMFA :exec
+UPDATE users
+SET mfa_secret = NULL,
+    mfa_enabled = false,
+    mfa_last_used_step = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) DisableUserMFA(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, disableUserMFA, id)
+	return err
+}
+
+const enableUserMFA = `-- name: EnableUserMFA :execrows
+UPDATE users
+SET mfa_enabled = true,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND mfa_secret IS NOT NULL AND NOT mfa_enabled
+`
+
+func (q *Queries) EnableUserMFA(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, enableUserMFA, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listUnusedMFARecoveryCodes = `-- name: ListUnusedMFARecoveryCodes :many
+SELECT id, user_id, code_hash, used_at, created_at FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) ListUnusedMFARecoveryCodes(ctx context.Context, userID pgtype.UUID) ([]MfaRecoveryCode, error) {
+	rows, err := q.db.Query(ctx, listUnusedMFARecoveryCodes, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MfaRecoveryCode{}
+	for rows.Next() {
+		var i MfaRecoveryCode
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CodeHash,
+			&i.UsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserMFASecret = `-- name: SetUserMFASecret :execrows
+UPDATE users
+SET mfa_secret = $2,
+    mfa_last_used_step = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND NOT mfa_enabled
+`
+
+type SetUserMFASecretParams struct {
+	ID        pgtype.UUID `json:"id"`
+	MfaSecret pgtype.Text `json:"mfa_secret"`
+}
+
+// Starts a new enrollment, unless two-factor authentication is already enabled
+func (q *Queries) SetUserMFASecret(ctx context.Context, arg SetUserMFASecretParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserMFASecret, arg.ID, arg.MfaSecret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useMFARecoveryCode = `-- name: UseMFARecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) UseMFARecoveryCode(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, useMFARecoveryCode, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -6,6 +6,7 @@ package sqlc
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -97,17 +98,39 @@ func (ns NullMemberRole) Value() (driver.Value, error) {
 	return string(ns.MemberRole), nil
 }
 
+type ApiKey struct {
+	ID         pgtype.UUID        `json:"id"`
+	UserID     pgtype.UUID        `json:"user_id"`
+	Name       string             `json:"name"`
+	KeyPrefix  string             `json:"key_prefix"`
+	KeyHash    string             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type Campaign struct {
-	ID             pgtype.UUID        `json:"id"`
-	Title          string             `json:"title"`
-	SettingSummary pgtype.Text        `json:"setting_summary"`
-	Setting        pgtype.Text        `json:"setting"`
-	ImageUrl       pgtype.Text        `json:"image_url"`
-	IsPublic       bool               `json:"is_public"`
-	InviteCode     pgtype.Text        `json:"invite_code"`
-	CreatedBy      pgtype.UUID        `json:"created_by"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ID                  pgtype.UUID        `json:"id"`
+	Title               string             `json:"title"`
+	SettingSummary      pgtype.Text        `json:"setting_summary"`
+	Setting             pgtype.Text        `json:"setting"`
+	ImageUrl            pgtype.Text        `json:"image_url"`
+	IsPublic            bool               `json:"is_public"`
+	InviteCode          pgtype.Text        `json:"invite_code"`
+	CreatedBy           pgtype.UUID        `json:"created_by"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+	InviteCodeExpiresAt pgtype.Timestamptz `json:"invite_code_expires_at"`
+	InviteCodeMaxUses   pgtype.Int4        `json:"invite_code_max_uses"`
+	InviteCodeUses      int32              `json:"invite_code_uses"`
+	Tags                []string           `json:"tags"`
+	GameSystem          pgtype.Text        `json:"game_system"`
+	CloneCount          int32              `json:"clone_count"`
+	ClonedFrom          pgtype.UUID        `json:"cloned_from"`
+	ArchivedAt          pgtype.Timestamptz `json:"archived_at"`
+	Version             int32              `json:"version"`
 }
 
 type CampaignMember struct {
@@ -132,7 +155,7 @@ type Character struct {
 	CampaignID  pgtype.UUID        `json:"campaign_id"`
 	UserID      pgtype.UUID        `json:"user_id"`
 	IsNpc       bool               `json:"is_npc"`
-	Metadata    []byte             `json:"metadata"`
+	Metadata    json.RawMessage    `json:"metadata" swaggertype:"object"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 }
@@ -149,6 +172,87 @@ type Invitation struct {
 	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
+type LoginThrottle struct {
+	Key         string             `json:"key"`
+	FailedCount int32              `json:"failed_count"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type MfaRecoveryCode struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type OidcLoginState struct {
+	StateHash    string             `json:"state_hash"`
+	Provider     string             `json:"provider"`
+	Nonce        string             `json:"nonce"`
+	CodeVerifier string             `json:"code_verifier"`
+	UserID       pgtype.UUID        `json:"user_id"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type Passkey struct {
+	ID              pgtype.UUID        `json:"id"`
+	UserID          pgtype.UUID        `json:"user_id"`
+	Name            string             `json:"name"`
+	CredentialID    []byte             `json:"credential_id"`
+	PublicKey       []byte             `json:"public_key"`
+	AttestationType string             `json:"attestation_type"`
+	Transports      []string           `json:"transports"`
+	Aaguid          []byte             `json:"aaguid"`
+	SignCount       int64              `json:"sign_count"`
+	BackupEligible  bool               `json:"backup_eligible"`
+	BackupState     bool               `json:"backup_state"`
+	LastUsedAt      pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID                   pgtype.UUID        `json:"id"`
+	UserID               pgtype.UUID        `json:"user_id"`
+	FamilyID             pgtype.UUID        `json:"family_id"`
+	TokenHash            string             `json:"token_hash"`
+	AccessTokenID        pgtype.UUID        `json:"access_token_id"`
+	AccessTokenExpiresAt pgtype.Timestamptz `json:"access_token_expires_at"`
+	ExpiresAt            pgtype.Timestamptz `json:"expires_at"`
+	UsedAt               pgtype.Timestamptz `json:"used_at"`
+	RevokedAt            pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	Scopes               []string           `json:"scopes"`
+}
+
+type RevokedToken struct {
+	TokenID   pgtype.UUID        `json:"token_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type Session struct {
+	ID         pgtype.UUID        `json:"id"`
+	UserID     pgtype.UUID        `json:"user_id"`
+	UserAgent  string             `json:"user_agent"`
+	IpAddress  string             `json:"ip_address"`
+	LastSeenAt pgtype.Timestamptz `json:"last_seen_at"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type TimelineEvent struct {
 	ID          pgtype.UUID        `json:"id"`
 	CampaignID  pgtype.UUID        `json:"campaign_id"`
@@ -162,13 +266,39 @@ type TimelineEvent struct {
 }
 
 type User struct {
-	ID             pgtype.UUID        `json:"id"`
-	Username       string             `json:"username"`
-	Email          string             `json:"email"`
-	HashedPassword string             `json:"hashed_password"`
-	IsActive       bool               `json:"is_active"`
-	AvatarUrl      pgtype.Text        `json:"avatar_url"`
-	LastLoginAt    pgtype.Timestamptz `json:"last_login_at"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ID                  pgtype.UUID        `json:"id"`
+	Username            string             `json:"username"`
+	Email               string             `json:"email"`
+	HashedPassword      string             `json:"hashed_password"`
+	IsActive            bool               `json:"is_active"`
+	AvatarUrl           pgtype.Text        `json:"avatar_url"`
+	LastLoginAt         pgtype.Timestamptz `json:"last_login_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+	VerificationSentAt  pgtype.Timestamptz `json:"verification_sent_at"`
+	MfaSecret           pgtype.Text        `json:"mfa_secret"`
+	MfaEnabled          bool               `json:"mfa_enabled"`
+	MfaLastUsedStep     pgtype.Int8        `json:"mfa_last_used_step"`
+	DeletionScheduledAt pgtype.Timestamptz `json:"deletion_scheduled_at"`
+	PasswordResetSentAt pgtype.Timestamptz `json:"password_reset_sent_at"`
+}
+
+type UserIdentity struct {
+	ID          pgtype.UUID        `json:"id"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Provider    string             `json:"provider"`
+	Issuer      string             `json:"issuer"`
+	Subject     string             `json:"subject"`
+	Email       pgtype.Text        `json:"email"`
+	LastLoginAt pgtype.Timestamptz `json:"last_login_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type WebauthnSession struct {
+	SessionHash string             `json:"session_hash"`
+	Ceremony    string             `json:"ceremony"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Data        []byte             `json:"data"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
@@ -0,0 +1,248 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: passkeys.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const consumeWebAuthnSession = `-- name: ConsumeWebAuthnSession :one
+DELETE FROM webauthn_sessions
+WHERE session_hash = $1 AND ceremony = $2 AND expires_at > CURRENT_TIMESTAMP
+RETURNING session_hash, ceremony, user_id, data, expires_at, created_at
+`
+
+type ConsumeWebAuthnSessionParams struct {
+	SessionHash string `json:"session_hash"`
+	Ceremony    string `json:"ceremony"`
+}
+
+// A session can only be used once, returning nothing if it is unknown, expired or of another ceremony
+func (q *Queries) ConsumeWebAuthnSession(ctx context.Context, arg ConsumeWebAuthnSessionParams) (WebauthnSession, error) {
+	row := q.db.QueryRow(ctx, consumeWebAuthnSession, arg.SessionHash, arg.Ceremony)
+	var i WebauthnSession
+	err := row.Scan(
+		&i.SessionHash,
+		&i.Ceremony,
+		&i.UserID,
+		&i.Data,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPasskey = `-- name: CreatePasskey :one
+INSERT INTO passkeys (
+    id,
+    user_id,
+    name,
+    credential_id,
+    public_key,
+    attestation_type,
+    transports,
+    aaguid,
+    sign_count,
+    backup_eligible,
+    backup_state
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, last_used_at, created_at
+`
+
+type CreatePasskeyParams struct {
+	ID              pgtype.UUID `json:"id"`
+	UserID          pgtype.UUID `json:"user_id"`
+	Name            string      `json:"name"`
+	CredentialID    []byte      `json:"credential_id"`
+	PublicKey       []byte      `json:"public_key"`
+	AttestationType string      `json:"attestation_type"`
+	Transports      []string    `json:"transports"`
+	Aaguid          []byte      `json:"aaguid"`
+	SignCount       int64       `json:"sign_count"`
+	BackupEligible  bool        `json:"backup_eligible"`
+	BackupState     bool        `json:"backup_state"`
+}
+
+func (q *Queries) CreatePasskey(ctx context.Context, arg CreatePasskeyParams) (Passkey, error) {
+	row := q.db.QueryRow(ctx, createPasskey,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.AttestationType,
+		arg.Transports,
+		arg.Aaguid,
+		arg.SignCount,
+		arg.BackupEligible,
+		arg.BackupState,
+	)
+	var i Passkey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.AttestationType,
+		&i.Transports,
+		&i.Aaguid,
+		&i.SignCount,
+		&i.BackupEligible,
+		&i.BackupState,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebAuthnSession = `-- name: CreateWebAuthnSession :exec
+INSERT INTO webauthn_sessions (
+    session_hash, ceremony, user_id, data, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+`
+
+type CreateWebAuthnSessionParams struct {
+	SessionHash string             `json:"session_hash"`
+	Ceremony    string             `json:"ceremony"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Data        []byte             `json:"data"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateWebAuthnSession(ctx context.Context, arg CreateWebAuthnSessionParams) error {
+	_, err := q.db.Exec(ctx, createWebAuthnSession,
+		arg.SessionHash,
+		arg.Ceremony,
+		arg.UserID,
+		arg.Data,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const deleteExpiredWebAuthnSessions = `-- name: DeleteExpiredWebAuthnSessions :exec
+DELETE FROM webauthn_sessions
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredWebAuthnSessions(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredWebAuthnSessions)
+	return err
+}
+
+const deletePasskey = `-- name: DeletePasskey :execrows
+DELETE FROM passkeys
+WHERE id = $1 AND user_id = $2
+`
+
+type DeletePasskeyParams struct {
+	ID     pgtype.UUID `json:"id"`
+	UserID pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeletePasskey(ctx context.Context, arg DeletePasskeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePasskey, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getPasskeyByCredentialID = `-- name: GetPasskeyByCredentialID :one
+SELECT id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, last_used_at, created_at FROM passkeys
+WHERE credential_id = $1
+`
+
+func (q *Queries) GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (Passkey, error) {
+	row := q.db.QueryRow(ctx, getPasskeyByCredentialID, credentialID)
+	var i Passkey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.AttestationType,
+		&i.Transports,
+		&i.Aaguid,
+		&i.SignCount,
+		&i.BackupEligible,
+		&i.BackupState,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPasskeysByUser = `-- name: ListPasskeysByUser :many
+SELECT id, user_id, name, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, last_used_at, created_at FROM passkeys
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListPasskeysByUser(ctx context.Context, userID pgtype.UUID) ([]Passkey, error) {
+	rows, err := q.db.Query(ctx, listPasskeysByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Passkey{}
+	for rows.Next() {
+		var i Passkey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.AttestationType,
+			&i.Transports,
+			&i.Aaguid,
+			&i.SignCount,
+			&i.BackupEligible,
+			&i.BackupState,
+			&i.LastUsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordPasskeyUse = `-- name: RecordPasskeyUse :execrows
+UPDATE passkeys
+SET sign_count = $1,
+    backup_state = $2,
+    last_used_at = CURRENT_TIMESTAMP
+WHERE id = $3
+  AND (sign_count < $1 OR (sign_count = 0 AND $1::bigint = 0))
+`
+
+type RecordPasskeyUseParams struct {
+	SignCount   int64       `json:"sign_count"`
+	BackupState bool        `json:"backup_state"`
+	ID          pgtype.UUID `json:"id"`
+}
+
+// Only moves the signature counter forward, so a concurrent login replaying the same counter fails
+func (q *Queries) RecordPasskeyUse(ctx context.Context, arg RecordPasskeyUseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, recordPasskeyUse, arg.SignCount, arg.BackupState, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
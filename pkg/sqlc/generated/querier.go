@@ -11,23 +11,162 @@ import (
 )
 
 type Querier interface {
+	ActivateUser(ctx context.Context, id pgtype.UUID) (User, error)
+	ArchiveCampaign(ctx context.Context, id pgtype.UUID) (Campaign, error)
+	CancelUserDeletion(ctx context.Context, id pgtype.UUID) error
+	// Records the time step of an accepted TOTP code, failing if it or a later one was already used
+	ClaimMFAStep(ctx context.Context, arg ClaimMFAStepParams) (int64, error)
+	ClearLoginThrottle(ctx context.Context, key string) error
+	// A state can only be used once, returning nothing if it is unknown or expired
+	ConsumeOIDCLoginState(ctx context.Context, stateHash string) (OidcLoginState, error)
+	// Marks a reset token as used, returning nothing if it is unknown, expired or already used
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	// A session can only be used once, returning nothing if it is unknown, expired or of another ceremony
+	ConsumeWebAuthnSession(ctx context.Context, arg ConsumeWebAuthnSessionParams) (WebauthnSession, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateCampaign(ctx context.Context, arg CreateCampaignParams) (Campaign, error)
 	CreateCampaignMember(ctx context.Context, arg CreateCampaignMemberParams) (CampaignMember, error)
+	CreateCharacter(ctx context.Context, arg CreateCharacterParams) (Character, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (Invitation, error)
+	CreateMFARecoveryCode(ctx context.Context, arg CreateMFARecoveryCodeParams) error
+	CreateOIDCLoginState(ctx context.Context, arg CreateOIDCLoginStateParams) error
+	CreatePasskey(ctx context.Context, arg CreatePasskeyParams) (Passkey, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTimelineEvent(ctx context.Context, arg CreateTimelineEventParams) (TimelineEvent, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) error
-	DeleteCampaignMember(ctx context.Context, arg DeleteCampaignMemberParams) error
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	CreateWebAuthnSession(ctx context.Context, arg CreateWebAuthnSessionParams) error
+	DeleteCampaign(ctx context.Context, id pgtype.UUID) error
+	// Deletes a membership unless it is the last GM of the campaign. The GM rows are locked so two GMs removed
+	// at the same time can't both see the other one as remaining.
+	DeleteCampaignMember(ctx context.Context, arg DeleteCampaignMemberParams) (int64, error)
+	DeleteCharacter(ctx context.Context, arg DeleteCharacterParams) error
+	DeleteExpiredOIDCLoginStates(ctx context.Context) error
+	DeleteExpiredSessions(ctx context.Context) error
+	DeleteExpiredTokens(ctx context.Context) error
+	DeleteExpiredWebAuthnSessions(ctx context.Context) error
+	DeleteInvitation(ctx context.Context, arg DeleteInvitationParams) (int64, error)
+	DeleteMFARecoveryCodes(ctx context.Context, userID pgtype.UUID) error
+	DeletePasskey(ctx context.Context, arg DeletePasskeyParams) (int64, error)
+	DeletePasswordResetTokensByUser(ctx context.Context, userID pgtype.UUID) error
+	DeleteStaleLoginThrottles(ctx context.Context, resetBefore pgtype.Timestamptz) error
+	DeleteTimelineEvent(ctx context.Context, arg DeleteTimelineEventParams) error
+	// Cascades to everything the user still owns
+	DeleteUser(ctx context.Context, id pgtype.UUID) error
+	DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error)
+	DisableUserMFA(ctx context.Context, id pgtype.UUID) error
+	EnableUserMFA(ctx context.Context, id pgtype.UUID) (int64, error)
+	ExpireInvitations(ctx context.Context) error
+	// A refresh keeps the session alive as long as its new refresh token
+	ExtendSession(ctx context.Context, arg ExtendSessionParams) error
 	GenerateInviteCode(ctx context.Context, arg GenerateInviteCodeParams) (Campaign, error)
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (GetActiveAPIKeyByHashRow, error)
+	GetActiveSession(ctx context.Context, id pgtype.UUID) (Session, error)
 	GetCampaignByID(ctx context.Context, arg GetCampaignByIDParams) (Campaign, error)
 	GetCampaignByInviteCode(ctx context.Context, inviteCode pgtype.Text) (Campaign, error)
 	GetCampaignMember(ctx context.Context, arg GetCampaignMemberParams) (CampaignMember, error)
+	// The member taking over a campaign from its creator, game masters first then whoever joined earliest
+	GetCampaignSuccessor(ctx context.Context, arg GetCampaignSuccessorParams) (CampaignMember, error)
+	GetCharacterByID(ctx context.Context, arg GetCharacterByIDParams) (Character, error)
+	GetInvitationByToken(ctx context.Context, token string) (Invitation, error)
+	GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (Passkey, error)
+	GetPendingInvitationByCampaignAndEmail(ctx context.Context, arg GetPendingInvitationByCampaignAndEmailParams) (Invitation, error)
+	GetRefreshTokenByAccessTokenID(ctx context.Context, accessTokenID pgtype.UUID) (RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetTimelineEventByID(ctx context.Context, arg GetTimelineEventByIDParams) (TimelineEvent, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
 	GetUserByUsernameOrEmail(ctx context.Context, arg GetUserByUsernameOrEmailParams) (User, error)
-	ListCampaignMembers(ctx context.Context, campaignID pgtype.UUID) ([]CampaignMember, error)
-	ListCampaignsByUserID(ctx context.Context, userID pgtype.UUID) ([]Campaign, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	// Hands the invitations a user sent to campaigns created by someone else over to the campaign creator
+	HandOverInvitations(ctx context.Context, userID pgtype.UUID) error
+	// Hands the events a user wrote in campaigns created by someone else over to the campaign creator
+	HandOverTimelineEvents(ctx context.Context, userID pgtype.UUID) error
+	IncrementCampaignCloneCount(ctx context.Context, id pgtype.UUID) error
+	IsTokenRevoked(ctx context.Context, tokenID pgtype.UUID) (bool, error)
+	ListAPIKeysByUser(ctx context.Context, userID pgtype.UUID) ([]ApiKey, error)
+	ListActiveSessionsByUser(ctx context.Context, userID pgtype.UUID) ([]Session, error)
+	ListCampaignMembers(ctx context.Context, campaignID pgtype.UUID) ([]ListCampaignMembersRow, error)
+	ListCampaignsCreatedByUser(ctx context.Context, createdBy pgtype.UUID) ([]Campaign, error)
+	ListCharactersByCampaign(ctx context.Context, campaignID pgtype.UUID) ([]Character, error)
+	ListCharactersByUser(ctx context.Context, userID pgtype.UUID) ([]Character, error)
+	ListInvitationsByCampaign(ctx context.Context, campaignID pgtype.UUID) ([]ListInvitationsByCampaignRow, error)
+	ListInvitationsReceivedByEmail(ctx context.Context, email string) ([]ListInvitationsReceivedByEmailRow, error)
+	ListInvitationsSentByUser(ctx context.Context, invitedBy pgtype.UUID) ([]ListInvitationsSentByUserRow, error)
+	ListLoginThrottles(ctx context.Context, keys []string) ([]LoginThrottle, error)
+	// Lists the campaigns a user is a member of along with their role, sorted by sort_key then ID, both descending.
+	// The sort key and ID of the last campaign of a page are the cursor of the next one.
+	ListMemberCampaigns(ctx context.Context, arg ListMemberCampaignsParams) ([]ListMemberCampaignsRow, error)
+	ListPasskeysByUser(ctx context.Context, userID pgtype.UUID) ([]Passkey, error)
+	// The token is left out, invitees open the link of the invitation email to answer it
+	ListPendingInvitationsByEmail(ctx context.Context, email string) ([]ListPendingInvitationsByEmailRow, error)
+	// The setting and invite code are left out, they are only shown to members
+	ListPublicCampaignsByUserID(ctx context.Context, userID pgtype.UUID) ([]ListPublicCampaignsByUserIDRow, error)
+	// Events without a date are listed last and excluded whenever a range filter is applied
+	ListTimelineEvents(ctx context.Context, arg ListTimelineEventsParams) ([]TimelineEvent, error)
+	ListTimelineEventsByCreator(ctx context.Context, createdBy pgtype.UUID) ([]TimelineEvent, error)
+	ListUnusedMFARecoveryCodes(ctx context.Context, userID pgtype.UUID) ([]MfaRecoveryCode, error)
+	ListUserIdentities(ctx context.Context, userID pgtype.UUID) ([]UserIdentity, error)
+	ListUsersDueForDeletion(ctx context.Context) ([]pgtype.UUID, error)
+	LockLogin(ctx context.Context, arg LockLoginParams) error
+	// Claims the right to send a password reset email, unless one was sent after throttle_before
+	MarkPasswordResetSent(ctx context.Context, arg MarkPasswordResetSentParams) (int64, error)
+	// Only one request can rotate a refresh token, any other sees zero affected rows
+	MarkRefreshTokenUsed(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Claims the right to send a verification email, unless one was sent after throttle_before
+	MarkVerificationSent(ctx context.Context, arg MarkVerificationSentParams) (int64, error)
+	// Only applies when the campaign is still at the version the patch was computed from
+	PatchCampaign(ctx context.Context, arg PatchCampaignParams) (Campaign, error)
+	// Counts a failed attempt, starting over once the last failure and lockout are older than reset_before
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginThrottle, error)
+	// Only moves the signature counter forward, so a concurrent login replaying the same counter fails
+	RecordPasskeyUse(ctx context.Context, arg RecordPasskeyUseParams) (int64, error)
+	RedeemInviteCode(ctx context.Context, inviteCode pgtype.Text) (Campaign, error)
+	// Only replaces the hash it was computed from, so a password changed meanwhile is kept
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) error
+	// Gives back a use redeemed by a join that failed, unless the code was rotated since
+	ReleaseInviteCodeUse(ctx context.Context, arg ReleaseInviteCodeUseParams) error
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeInviteCode(ctx context.Context, id pgtype.UUID) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) error
+	// Blocks every access token issued alongside a refresh token of the family that is still alive
+	RevokeRefreshTokenFamilyAccessTokens(ctx context.Context, familyID pgtype.UUID) error
+	RevokeSession(ctx context.Context, id pgtype.UUID) error
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	RevokeUserAPIKeys(ctx context.Context, userID pgtype.UUID) error
+	// Blocks the live access tokens of every session of a user except the one of keep_family_id, when given
+	RevokeUserAccessTokens(ctx context.Context, arg RevokeUserAccessTokensParams) error
+	RevokeUserRefreshTokens(ctx context.Context, arg RevokeUserRefreshTokensParams) error
+	RevokeUserSession(ctx context.Context, arg RevokeUserSessionParams) (int64, error)
+	// Ends every session of a user except keep_id, when given
+	RevokeUserSessions(ctx context.Context, arg RevokeUserSessionsParams) error
+	ScheduleUserDeletion(ctx context.Context, arg ScheduleUserDeletionParams) (User, error)
+	// Lists public campaigns matching the filters, sorted by sort_key then ID, both descending.
+	// The sort key and ID of the last campaign of a page are the cursor of the next one.
+	SearchPublicCampaigns(ctx context.Context, arg SearchPublicCampaignsParams) ([]SearchPublicCampaignsRow, error)
+	// Starts a new enrollment, unless two-factor authentication is already enabled
+	SetUserMFASecret(ctx context.Context, arg SetUserMFASecretParams) (int64, error)
+	// Records the use of a key, at most once per interval to spare a write on every request
+	TouchAPIKey(ctx context.Context, arg TouchAPIKeyParams) error
+	TouchCampaignMember(ctx context.Context, arg TouchCampaignMemberParams) error
+	// Records the last request of a session, at most once per interval to spare a write on every request
+	TouchSession(ctx context.Context, arg TouchSessionParams) error
+	TouchUserIdentity(ctx context.Context, id pgtype.UUID) error
+	TransferCampaign(ctx context.Context, arg TransferCampaignParams) error
+	UnarchiveCampaign(ctx context.Context, id pgtype.UUID) (Campaign, error)
 	UpdateCampaign(ctx context.Context, arg UpdateCampaignParams) (Campaign, error)
 	UpdateCampaignMember(ctx context.Context, arg UpdateCampaignMemberParams) (CampaignMember, error)
+	UpdateCharacter(ctx context.Context, arg UpdateCharacterParams) (Character, error)
+	UpdateInvitationStatus(ctx context.Context, arg UpdateInvitationStatusParams) (Invitation, error)
+	UpdateTimelineEvent(ctx context.Context, arg UpdateTimelineEventParams) (TimelineEvent, error)
+	UpdateUserLastLogin(ctx context.Context, id pgtype.UUID) error
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	// Changing the email address marks it as unverified again
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UseMFARecoveryCode(ctx context.Context, id pgtype.UUID) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: timeline_events.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createTimelineEvent = `-- name: CreateTimelineEvent :one
+INSERT INTO timeline_events (
+    id, campaign_id, title, description, event_date, is_public, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, campaign_id, title, description, event_date, is_public, created_by, created_at, updated_at
+`
+
+type CreateTimelineEventParams struct {
+	ID          pgtype.UUID        `json:"id"`
+	CampaignID  pgtype.UUID        `json:"campaign_id"`
+	Title       string             `json:"title"`
+	Description pgtype.Text        `json:"description"`
+	EventDate   pgtype.Timestamptz `json:"event_date"`
+	IsPublic    bool               `json:"is_public"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+}
+
+func (q *Queries) CreateTimelineEvent(ctx context.Context, arg CreateTimelineEventParams) (TimelineEvent, error) {
+	row := q.db.QueryRow(ctx, createTimelineEvent,
+		arg.ID,
+		arg.CampaignID,
+		arg.Title,
+		arg.Description,
+		arg.EventDate,
+		arg.IsPublic,
+		arg.CreatedBy,
+	)
+	var i TimelineEvent
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Title,
+		&i.Description,
+		&i.EventDate,
+		&i.IsPublic,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTimelineEvent = `-- name: DeleteTimelineEvent :exec
+DELETE FROM timeline_events
+WHERE id = $1 AND campaign_id = $2
+`
+
+type DeleteTimelineEventParams struct {
+	ID         pgtype.UUID `json:"id"`
+	CampaignID pgtype.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) DeleteTimelineEvent(ctx context.Context, arg DeleteTimelineEventParams) error {
+	_, err := q.db.Exec(ctx, deleteTimelineEvent, arg.ID, arg.CampaignID)
+	return err
+}
+
+const getTimelineEventByID = `-- name: GetTimelineEventByID :one
+SELECT id, campaign_id, title, description, event_date, is_public, created_by, created_at, updated_at FROM timeline_events
+WHERE id = $1 AND campaign_id = $2
+`
+
+type GetTimelineEventByIDParams struct {
+	ID         pgtype.UUID `json:"id"`
+	CampaignID pgtype.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) GetTimelineEventByID(ctx context.Context, arg GetTimelineEventByIDParams) (TimelineEvent, error) {
+	row := q.db.QueryRow(ctx, getTimelineEventByID, arg.ID, arg.CampaignID)
+	var i TimelineEvent
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Title,
+		&i.Description,
+		&i.EventDate,
+		&i.IsPublic,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const handOverTimelineEvents = `-- name: HandOverTimelineEvents :exec
+UPDATE timeline_events AS te
+SET created_by = c.created_by,
+    updated_at = CURRENT_TIMESTAMP
+FROM campaigns AS c
+WHERE c.id = te.campaign_id
+  AND te.created_by = $1
+  AND c.created_by <> $1
+`
+
+// Hands the events a user wrote in campaigns created by someone else over to the campaign creator
+func (q *Queries) HandOverTimelineEvents(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, handOverTimelineEvents, userID)
+	return err
+}
+
+const listTimelineEvents = `-- name: ListTimelineEvents :many
+SELECT id, campaign_id, title, description, event_date, is_public, created_by, created_at, updated_at FROM timeline_events
+WHERE campaign_id = $1
+  AND ($2::boolean OR is_public)
+  AND ($3::timestamptz IS NULL OR event_date >= $3)
+  AND ($4::timestamptz IS NULL OR event_date <= $4)
+ORDER BY event_date NULLS LAST, created_at
+`
+
+type ListTimelineEventsParams struct {
+	CampaignID    pgtype.UUID        `json:"campaign_id"`
+	IncludeSecret bool               `json:"include_secret"`
+	FromDate      pgtype.Timestamptz `json:"from_date"`
+	ToDate        pgtype.Timestamptz `json:"to_date"`
+}
+
+// Events without a date are listed last and excluded whenever a range filter is applied
+func (q *Queries) ListTimelineEvents(ctx context.Context, arg ListTimelineEventsParams) ([]TimelineEvent, error) {
+	rows, err := q.db.Query(ctx, listTimelineEvents,
+		arg.CampaignID,
+		arg.IncludeSecret,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TimelineEvent{}
+	for rows.Next() {
+		var i TimelineEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Title,
+			&i.Description,
+			&i.EventDate,
+			&i.IsPublic,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimelineEventsByCreator = `-- name: ListTimelineEventsByCreator :many
+SELECT id, campaign_id, title, description, event_date, is_public, created_by, created_at, updated_at FROM timeline_events
+WHERE created_by = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListTimelineEventsByCreator(ctx context.Context, createdBy pgtype.UUID) ([]TimelineEvent, error) {
+	rows, err := q.db.Query(ctx, listTimelineEventsByCreator, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TimelineEvent{}
+	for rows.Next() {
+		var i TimelineEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Title,
+			&i.Description,
+			&i.EventDate,
+			&i.IsPublic,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTimelineEvent = `-- name: UpdateTimelineEvent :one
+UPDATE timeline_events
+SET title = $3,
+    description = $4,
+    event_date = $5,
+    is_public = $6,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND campaign_id = $2
+RETURNING id, campaign_id, title, description, event_date, is_public, created_by, created_at, updated_at
+`
+
+type UpdateTimelineEventParams struct {
+	ID          pgtype.UUID        `json:"id"`
+	CampaignID  pgtype.UUID        `json:"campaign_id"`
+	Title       string             `json:"title"`
+	Description pgtype.Text        `json:"description"`
+	EventDate   pgtype.Timestamptz `json:"event_date"`
+	IsPublic    bool               `json:"is_public"`
+}
+
+func (q *Queries) UpdateTimelineEvent(ctx context.Context, arg UpdateTimelineEventParams) (TimelineEvent, error) {
+	row := q.db.QueryRow(ctx, updateTimelineEvent,
+		arg.ID,
+		arg.CampaignID,
+		arg.Title,
+		arg.Description,
+		arg.EventDate,
+		arg.IsPublic,
+	)
+	var i TimelineEvent
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Title,
+		&i.Description,
+		&i.EventDate,
+		&i.IsPublic,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,230 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: tokens.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createRefreshToken = `-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (
+    id, user_id, family_id, token_hash, access_token_id, access_token_expires_at, expires_at, scopes
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, user_id, family_id, token_hash, access_token_id, access_token_expires_at, expires_at, used_at, revoked_at, created_at, scopes
+`
+
+type CreateRefreshTokenParams struct {
+	ID                   pgtype.UUID        `json:"id"`
+	UserID               pgtype.UUID        `json:"user_id"`
+	FamilyID             pgtype.UUID        `json:"family_id"`
+	TokenHash            string             `json:"token_hash"`
+	AccessTokenID        pgtype.UUID        `json:"access_token_id"`
+	AccessTokenExpiresAt pgtype.Timestamptz `json:"access_token_expires_at"`
+	ExpiresAt            pgtype.Timestamptz `json:"expires_at"`
+	Scopes               []string           `json:"scopes"`
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, createRefreshToken,
+		arg.ID,
+		arg.UserID,
+		arg.FamilyID,
+		arg.TokenHash,
+		arg.AccessTokenID,
+		arg.AccessTokenExpiresAt,
+		arg.ExpiresAt,
+		arg.Scopes,
+	)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.TokenHash,
+		&i.AccessTokenID,
+		&i.AccessTokenExpiresAt,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.Scopes,
+	)
+	return i, err
+}
+
+const deleteExpiredTokens = `-- name: DeleteExpiredTokens :exec
+WITH expired_revoked_tokens AS (
+    DELETE FROM revoked_tokens
+    WHERE revoked_tokens.expires_at < CURRENT_TIMESTAMP
+)
+DELETE FROM refresh_tokens
+WHERE refresh_tokens.expires_at < CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredTokens(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredTokens)
+	return err
+}
+
+const getRefreshTokenByAccessTokenID = `-- name: GetRefreshTokenByAccessTokenID :one
+SELECT id, user_id, family_id, token_hash, access_token_id, access_token_expires_at, expires_at, used_at, revoked_at, created_at, scopes FROM refresh_tokens
+WHERE access_token_id = $1
+`
+
+func (q *Queries) GetRefreshTokenByAccessTokenID(ctx context.Context, accessTokenID pgtype.UUID) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByAccessTokenID, accessTokenID)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.TokenHash,
+		&i.AccessTokenID,
+		&i.AccessTokenExpiresAt,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.Scopes,
+	)
+	return i, err
+}
+
+const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, family_id, token_hash, access_token_id, access_token_expires_at, expires_at, used_at, revoked_at, created_at, scopes FROM refresh_tokens
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByHash, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.TokenHash,
+		&i.AccessTokenID,
+		&i.AccessTokenExpiresAt,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.Scopes,
+	)
+	return i, err
+}
+
+const isTokenRevoked = `-- name: IsTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_tokens
+    WHERE token_id = $1
+)
+`
+
+func (q *Queries) IsTokenRevoked(ctx context.Context, tokenID pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isTokenRevoked, tokenID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const markRefreshTokenUsed = `-- name: MarkRefreshTokenUsed :execrows
+UPDATE refresh_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+`
+
+// Only one request can rotate a refresh token, any other sees zero affected rows
+func (q *Queries) MarkRefreshTokenUsed(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markRefreshTokenUsed, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamily, familyID)
+	return err
+}
+
+const revokeRefreshTokenFamilyAccessTokens = `-- name: RevokeRefreshTokenFamilyAccessTokens :exec
+INSERT INTO revoked_tokens (token_id, expires_at)
+SELECT access_token_id, access_token_expires_at
+FROM refresh_tokens
+WHERE family_id = $1 AND access_token_expires_at > CURRENT_TIMESTAMP
+ON CONFLICT (token_id) DO NOTHING
+`
+
+// Blocks every access token issued alongside a refresh token of the family that is still alive
+func (q *Queries) RevokeRefreshTokenFamilyAccessTokens(ctx context.Context, familyID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamilyAccessTokens, familyID)
+	return err
+}
+
+const revokeToken = `-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (token_id, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (token_id) DO NOTHING
+`
+
+type RevokeTokenParams struct {
+	TokenID   pgtype.UUID        `json:"token_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) RevokeToken(ctx context.Context, arg RevokeTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeToken, arg.TokenID, arg.ExpiresAt)
+	return err
+}
+
+const revokeUserAccessTokens = `-- name: RevokeUserAccessTokens :exec
+INSERT INTO revoked_tokens (token_id, expires_at)
+SELECT access_token_id, access_token_expires_at
+FROM refresh_tokens
+WHERE user_id = $1
+  AND access_token_expires_at > CURRENT_TIMESTAMP
+  AND ($2::uuid IS NULL OR family_id <> $2)
+ON CONFLICT (token_id) DO NOTHING
+`
+
+type RevokeUserAccessTokensParams struct {
+	UserID       pgtype.UUID `json:"user_id"`
+	KeepFamilyID pgtype.UUID `json:"keep_family_id"`
+}
+
+// Blocks the live access tokens of every session of a user except the one of keep_family_id, when given
+func (q *Queries) RevokeUserAccessTokens(ctx context.Context, arg RevokeUserAccessTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeUserAccessTokens, arg.UserID, arg.KeepFamilyID)
+	return err
+}
+
+const revokeUserRefreshTokens = `-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND ($2::uuid IS NULL OR family_id <> $2)
+`
+
+type RevokeUserRefreshTokensParams struct {
+	UserID       pgtype.UUID `json:"user_id"`
+	KeepFamilyID pgtype.UUID `json:"keep_family_id"`
+}
+
+func (q *Queries) RevokeUserRefreshTokens(ctx context.Context, arg RevokeUserRefreshTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeUserRefreshTokens, arg.UserID, arg.KeepFamilyID)
+	return err
+}
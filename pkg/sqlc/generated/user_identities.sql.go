@@ -0,0 +1,203 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: user_identities.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const consumeOIDCLoginState = `-- name: ConsumeOIDCLoginState :one
+DELETE FROM oidc_login_states
+WHERE state_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING state_hash, provider, nonce, code_verifier, user_id, expires_at, created_at
+`
+
+// A state can only be used once, returning nothing if it is unknown or expired
+func (q *Queries) ConsumeOIDCLoginState(ctx context.Context, stateHash string) (OidcLoginState, error) {
+	row := q.db.QueryRow(ctx, consumeOIDCLoginState, stateHash)
+	var i OidcLoginState
+	err := row.Scan(
+		&i.StateHash,
+		&i.Provider,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createOIDCLoginState = `-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (
+    state_hash, provider, nonce, code_verifier, user_id, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+`
+
+type CreateOIDCLoginStateParams struct {
+	StateHash    string             `json:"state_hash"`
+	Provider     string             `json:"provider"`
+	Nonce        string             `json:"nonce"`
+	CodeVerifier string             `json:"code_verifier"`
+	UserID       pgtype.UUID        `json:"user_id"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateOIDCLoginState(ctx context.Context, arg CreateOIDCLoginStateParams) error {
+	_, err := q.db.Exec(ctx, createOIDCLoginState,
+		arg.StateHash,
+		arg.Provider,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.UserID,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+    id, user_id, provider, issuer, subject, email
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, user_id, provider, issuer, subject, email, last_login_at, created_at
+`
+
+type CreateUserIdentityParams struct {
+	ID       pgtype.UUID `json:"id"`
+	UserID   pgtype.UUID `json:"user_id"`
+	Provider string      `json:"provider"`
+	Issuer   string      `json:"issuer"`
+	Subject  string      `json:"subject"`
+	Email    pgtype.Text `json:"email"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, createUserIdentity,
+		arg.ID,
+		arg.UserID,
+		arg.Provider,
+		arg.Issuer,
+		arg.Subject,
+		arg.Email,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredOIDCLoginStates = `-- name: DeleteExpiredOIDCLoginStates :exec
+DELETE FROM oidc_login_states
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredOIDCLoginStates(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredOIDCLoginStates)
+	return err
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteUserIdentityParams struct {
+	ID     pgtype.UUID `json:"id"`
+	UserID pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserIdentity, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT id, user_id, provider, issuer, subject, email, last_login_at, created_at FROM user_identities
+WHERE issuer = $1 AND subject = $2
+`
+
+type GetUserIdentityParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, getUserIdentity, arg.Issuer, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listUserIdentities = `-- name: ListUserIdentities :many
+SELECT id, user_id, provider, issuer, subject, email, last_login_at, created_at FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListUserIdentities(ctx context.Context, userID pgtype.UUID) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, listUserIdentities, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserIdentity{}
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.Issuer,
+			&i.Subject,
+			&i.Email,
+			&i.LastLoginAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchUserIdentity = `-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) TouchUserIdentity(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchUserIdentity, id)
+	return err
+}
@@ -11,6 +11,49 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const activateUser = `-- name: ActivateUser :one
+UPDATE users
+SET is_active = true,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at
+`
+
+func (q *Queries) ActivateUser(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, activateUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsActive,
+		&i.AvatarUrl,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const cancelUserDeletion = `-- name: CancelUserDeletion :exec
+UPDATE users
+SET deletion_scheduled_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deletion_scheduled_at IS NOT NULL
+`
+
+func (q *Queries) CancelUserDeletion(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, cancelUserDeletion, id)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     id,
@@ -19,7 +62,7 @@ INSERT INTO users (
     hashed_password
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at
+) RETURNING id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at
 `
 
 type CreateUserParams struct {
@@ -47,12 +90,29 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.LastLoginAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
 
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+// Cascades to everything the user still owns
+func (q *Queries) DeleteUser(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUser, id)
+	return err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at FROM users
+SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at FROM users
 WHERE email = $1
 LIMIT 1
 `
@@ -70,12 +130,18 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.LastLoginAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at FROM users
+SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at FROM users
 WHERE id = $1
 LIMIT 1
 `
@@ -93,12 +159,18 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.LastLoginAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at FROM users
+SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at FROM users
 WHERE username = $1
 LIMIT 1
 `
@@ -116,12 +188,18 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.LastLoginAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
 
 const getUserByUsernameOrEmail = `-- name: GetUserByUsernameOrEmail :one
-SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at FROM users
+SELECT id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at FROM users
 WHERE username = $1 OR email = $2
 LIMIT 1
 `
@@ -144,6 +222,211 @@ func (q *Queries) GetUserByUsernameOrEmail(ctx context.Context, arg GetUserByUse
 		&i.LastLoginAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const listUsersDueForDeletion = `-- name: ListUsersDueForDeletion :many
+SELECT id FROM users
+WHERE deletion_scheduled_at <= CURRENT_TIMESTAMP
+ORDER BY deletion_scheduled_at
+`
+
+func (q *Queries) ListUsersDueForDeletion(ctx context.Context) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listUsersDueForDeletion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.UUID{}
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markPasswordResetSent = `-- name: MarkPasswordResetSent :execrows
+UPDATE users
+SET password_reset_sent_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND (password_reset_sent_at IS NULL OR password_reset_sent_at <= $2::timestamptz)
+`
+
+type MarkPasswordResetSentParams struct {
+	ID             pgtype.UUID        `json:"id"`
+	ThrottleBefore pgtype.Timestamptz `json:"throttle_before"`
+}
+
+// Claims the right to send a password reset email, unless one was sent after throttle_before
+func (q *Queries) MarkPasswordResetSent(ctx context.Context, arg MarkPasswordResetSentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markPasswordResetSent, arg.ID, arg.ThrottleBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markVerificationSent = `-- name: MarkVerificationSent :execrows
+UPDATE users
+SET verification_sent_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND NOT is_active
+  AND (verification_sent_at IS NULL OR verification_sent_at <= $2::timestamptz)
+`
+
+type MarkVerificationSentParams struct {
+	ID             pgtype.UUID        `json:"id"`
+	ThrottleBefore pgtype.Timestamptz `json:"throttle_before"`
+}
+
+// Claims the right to send a verification email, unless one was sent after throttle_before
+func (q *Queries) MarkVerificationSent(ctx context.Context, arg MarkVerificationSentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markVerificationSent, arg.ID, arg.ThrottleBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :exec
+UPDATE users
+SET hashed_password = $1
+WHERE id = $2 AND hashed_password = $3
+`
+
+type RehashUserPasswordParams struct {
+	HashedPassword         string      `json:"hashed_password"`
+	ID                     pgtype.UUID `json:"id"`
+	PreviousHashedPassword string      `json:"previous_hashed_password"`
+}
+
+// Only replaces the hash it was computed from, so a password changed meanwhile is kept
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, rehashUserPassword, arg.HashedPassword, arg.ID, arg.PreviousHashedPassword)
+	return err
+}
+
+const scheduleUserDeletion = `-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deletion_scheduled_at = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+RETURNING id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at
+`
+
+type ScheduleUserDeletionParams struct {
+	DeletionScheduledAt pgtype.Timestamptz `json:"deletion_scheduled_at"`
+	ID                  pgtype.UUID        `json:"id"`
+}
+
+func (q *Queries) ScheduleUserDeletion(ctx context.Context, arg ScheduleUserDeletionParams) (User, error) {
+	row := q.db.QueryRow(ctx, scheduleUserDeletion, arg.DeletionScheduledAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsActive,
+		&i.AvatarUrl,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const updateUserLastLogin = `-- name: UpdateUserLastLogin :exec
+UPDATE users
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) UpdateUserLastLogin(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, updateUserLastLogin, id)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET hashed_password = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID             pgtype.UUID `json:"id"`
+	HashedPassword string      `json:"hashed_password"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET username = $1,
+    email = $2,
+    avatar_url = $3,
+    is_active = CASE WHEN email = $2 THEN is_active ELSE false END,
+    verification_sent_at = CASE WHEN email = $2 THEN verification_sent_at ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $4
+RETURNING id, username, email, hashed_password, is_active, avatar_url, last_login_at, created_at, updated_at, verification_sent_at, mfa_secret, mfa_enabled, mfa_last_used_step, deletion_scheduled_at, password_reset_sent_at
+`
+
+type UpdateUserProfileParams struct {
+	Username  string      `json:"username"`
+	Email     string      `json:"email"`
+	AvatarUrl pgtype.Text `json:"avatar_url"`
+	ID        pgtype.UUID `json:"id"`
+}
+
+// Changing the email address marks it as unverified again
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.Username,
+		arg.Email,
+		arg.AvatarUrl,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsActive,
+		&i.AvatarUrl,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VerificationSentAt,
+		&i.MfaSecret,
+		&i.MfaEnabled,
+		&i.MfaLastUsedStep,
+		&i.DeletionScheduledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
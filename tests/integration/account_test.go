@@ -0,0 +1,221 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAccount_Success(t *testing.T) {
+	// Given a user with a campaign, a character, a timeline event and an invitation
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, false)
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, user.Token, campaign.ID.Bytes, domain.CharacterInput{Name: "Elara"}, nil))
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, user.Token, campaign.ID.Bytes, domain.TimelineEventInput{Title: "The fall of Greyhold"}, nil))
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, user.Token, campaign.ID.Bytes, "guest@example.com", nil))
+
+	// And an invitation to another user's campaign
+	otherUser := CreateTestUser(t)
+	otherCampaign := CreateTestCampaign(t, otherUser.Token, false)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, otherUser.Token, otherCampaign.ID.Bytes, user.Email, nil))
+
+	// When exporting their data
+	var export domain.AccountExport
+	statusCode := ExportMe(t, user.Token, &export)
+
+	// Then everything they own should be in it
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, user.User.ID, export.Profile.ID)
+	require.Len(t, export.Campaigns, 1)
+	assert.Equal(t, campaign.ID, export.Campaigns[0].ID)
+	require.Len(t, export.Characters, 1)
+	assert.Equal(t, "Elara", export.Characters[0].Name)
+	require.Len(t, export.TimelineEvents, 1)
+	assert.Equal(t, "The fall of Greyhold", export.TimelineEvents[0].Title)
+	require.Len(t, export.InvitationsSent, 1)
+	assert.Equal(t, "guest@example.com", export.InvitationsSent[0].Email)
+	require.Len(t, export.InvitationsReceived, 1)
+	assert.Equal(t, otherCampaign.ID, export.InvitationsReceived[0].CampaignID)
+
+	// And nothing owned by the other user
+	var otherExport domain.AccountExport
+	require.Equal(t, http.StatusOK, ExportMe(t, otherUser.Token, &otherExport))
+	require.Len(t, otherExport.Campaigns, 1)
+	assert.Equal(t, otherCampaign.ID, otherExport.Campaigns[0].ID)
+	assert.Empty(t, otherExport.Characters)
+}
+
+func TestExportAccount_Success_UnverifiedEmail(t *testing.T) {
+	// Given an unverified user whose email address was invited to a campaign
+	user := CreateUnverifiedTestUser(t)
+	otherUser := CreateTestUser(t)
+	otherCampaign := CreateTestCampaign(t, otherUser.Token, false)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, otherUser.Token, otherCampaign.ID.Bytes, user.Email, nil))
+
+	// When exporting their data
+	var export domain.AccountExport
+	statusCode := ExportMe(t, user.Token, &export)
+
+	// Then the invitation should be left out until the address is verified
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotNil(t, export.InvitationsReceived)
+	assert.Empty(t, export.InvitationsReceived)
+}
+
+func TestExportAccount_Success_Zip(t *testing.T) {
+	// Given a user with a campaign
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, true)
+
+	// When exporting their data as a ZIP archive
+	archive := ExportMeZip(t, user.Token)
+
+	// Then it should hold one JSON file per section
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"profile.json",
+		"campaigns.json",
+		"characters.json",
+		"timeline_events.json",
+		"invitations_sent.json",
+		"invitations_received.json",
+	}, names)
+
+	file, err := archive.Open("campaigns.json")
+	require.NoError(t, err)
+	defer file.Close()
+	var campaigns []sqlc.Campaign
+	require.NoError(t, json.NewDecoder(file).Decode(&campaigns))
+	require.Len(t, campaigns, 1)
+	assert.Equal(t, campaign.ID, campaigns[0].ID)
+}
+
+func TestDeleteAccount_Failure_IncorrectPassword(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When deleting their account with a wrong password
+	statusCode := DeleteMe(t, user.Token, "WrongPassword123!", nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+
+	// When deleting it without a password
+	statusCode = DeleteMe(t, user.Token, "", nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestDeleteAccount_Success(t *testing.T) {
+	// Given a user with an API key
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+
+	// When deleting their account
+	var deletion domain.AccountDeletion
+	statusCode := DeleteMe(t, user.Token, user.Password, &deletion)
+
+	// Then the deletion should be scheduled after the grace period
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.True(t, deletion.DeletionScheduledAt.After(time.Now()))
+	assert.Contains(t, ReadLatestEmail(t, user.Email), "will be deleted")
+
+	// And their tokens and API keys should stop working right away
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, user.Token))
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, user.RefreshToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, apiKey.Key))
+
+	// And the account should be kept until the grace period is over
+	_, err := TestAPI.PurgeDeletedAccounts()
+	require.NoError(t, err)
+	assert.True(t, UserExists(t, user.User.ID))
+}
+
+func TestDeleteAccount_Success_LoginCancelsDeletion(t *testing.T) {
+	// Given a user whose account is scheduled for deletion
+	user := CreateTestUser(t)
+	require.Equal(t, http.StatusAccepted, DeleteMe(t, user.Token, user.Password, nil))
+
+	// When they log in again during the grace period
+	var authOutput domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	statusCode := LoginUser(t, input, &authOutput)
+
+	// Then the deletion should be cancelled
+	assert.Equal(t, http.StatusOK, statusCode)
+	EndDeletionGracePeriod(t, user.User.ID)
+	assert.True(t, UserExists(t, user.User.ID))
+	assert.Equal(t, http.StatusOK, GetMe(t, authOutput.Token))
+}
+
+func TestDeleteAccount_Success_Purge(t *testing.T) {
+	// Given a user with a campaign of their own and a campaign another member plays in
+	user := CreateTestUser(t)
+	soloCampaign := CreateTestCampaign(t, user.Token, true)
+	sharedCampaign := CreateTestCampaign(t, user.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, user.Token, sharedCampaign.ID.Bytes, player.User.ID.Bytes, "player"))
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, user.Token, sharedCampaign.ID.Bytes, domain.CharacterInput{Name: "Villain", IsNPC: true}, nil))
+
+	// And a timeline event they wrote as a game master of someone else's campaign
+	owner := CreateTestUser(t)
+	ownerCampaign := CreateTestCampaign(t, owner.Token, false)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, owner.Token, ownerCampaign.ID.Bytes, user.User.ID.Bytes, "gm"))
+	var event sqlc.TimelineEvent
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, user.Token, ownerCampaign.ID.Bytes, domain.TimelineEventInput{Title: "Coronation"}, &event))
+
+	// When their account is deleted and the grace period is over
+	require.Equal(t, http.StatusAccepted, DeleteMe(t, user.Token, user.Password, nil))
+	EndDeletionGracePeriod(t, user.User.ID)
+
+	// Then the account should be gone
+	assert.False(t, UserExists(t, user.User.ID))
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	statusCode, _ := LoginUserFromIP(t, input, RandomTestIP(t), nil)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// And the public campaign nobody else plays in should be deleted with it
+	assert.Equal(t, http.StatusNotFound, GetCampaign(t, owner.Token, soloCampaign.ID.Bytes, nil))
+
+	// And the other campaign should be handed over to its remaining member, as game master
+	var campaign sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, player.Token, sharedCampaign.ID.Bytes, &campaign))
+	assert.Equal(t, player.User.ID, campaign.CreatedBy)
+	var members []sqlc.ListCampaignMembersRow
+	require.Equal(t, http.StatusOK, ListCampaignMembers(t, player.Token, sharedCampaign.ID.Bytes, &members))
+	require.Len(t, members, 1)
+	assert.Equal(t, sqlc.MemberRoleGm, members[0].Role)
+
+	// And the timeline event should be kept, now written by the campaign owner
+	var keptEvent sqlc.TimelineEvent
+	require.Equal(t, http.StatusOK, GetTimelineEvent(t, owner.Token, ownerCampaign.ID.Bytes, event.ID.Bytes, &keptEvent))
+	assert.Equal(t, owner.User.ID, keptEvent.CreatedBy)
+}
+
+func TestDeleteAccount_Failure_MissingAccountScope(t *testing.T) {
+	// Given a user logged in with the read scope only
+	user := CreateTestUser(t)
+	var authOutput domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{domain.ScopeRead}}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &authOutput))
+
+	// When deleting their account or exporting their data
+	deleteStatusCode := DeleteMe(t, authOutput.Token, user.Password, nil)
+	exportStatusCode := ExportMe(t, authOutput.Token, nil)
+
+	// Then both should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, deleteStatusCode)
+	assert.Equal(t, http.StatusForbidden, exportStatusCode)
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+}
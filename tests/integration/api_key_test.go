@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAPIKey_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When creating an API key
+	var apiKey domain.CreatedAPIKey
+	input := domain.APIKeyInput{Name: "Discord bot", Scopes: []string{domain.ScopeRead}}
+	statusCode := CreateAPIKey(t, user.Token, input, &apiKey)
+
+	// Then the key should be returned once
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.True(t, strings.HasPrefix(apiKey.Key, domain.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(apiKey.Key, apiKey.KeyPrefix))
+	assert.Equal(t, "Discord bot", apiKey.Name)
+	assert.Equal(t, []string{domain.ScopeRead}, apiKey.Scopes)
+	assert.False(t, apiKey.LastUsedAt.Valid)
+
+	// And the listing should not expose the key
+	var apiKeys []map[string]interface{}
+	require.Equal(t, http.StatusOK, ListAPIKeys(t, user.Token, &apiKeys))
+	require.Len(t, apiKeys, 1)
+	assert.Equal(t, apiKey.KeyPrefix, apiKeys[0]["key_prefix"])
+	assert.NotContains(t, apiKeys[0], "key")
+	assert.NotContains(t, apiKeys[0], "key_hash")
+}
+
+func TestCreateAPIKey_Failure_InvalidInput(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When creating an API key without a name
+	statusCode := CreateAPIKey(t, user.Token, domain.APIKeyInput{Scopes: []string{domain.ScopeRead}}, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// When creating an API key with an unknown scope
+	statusCode = CreateAPIKey(t, user.Token, domain.APIKeyInput{Name: "Bot", Scopes: []string{"admin"}}, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestAPIKey_Success_Authenticates(t *testing.T) {
+	// Given a user with a read API key
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+
+	// When using the key as a bearer token
+	var profile domain.UserProfile
+	statusCode := SendAuthenticatedRequest(t, "GET", "/api/me", apiKey.Key, nil, &profile)
+
+	// Then the request should be made as the user
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, user.Username, profile.Username)
+
+	// And the key should be marked as used
+	var apiKeys []domain.APIKey
+	require.Equal(t, http.StatusOK, ListAPIKeys(t, user.Token, &apiKeys))
+	require.Len(t, apiKeys, 1)
+	assert.True(t, apiKeys[0].LastUsedAt.Valid)
+}
+
+func TestAPIKey_Failure_MissingScope(t *testing.T) {
+	// Given a user with a read API key and a campaigns:write API key
+	user := CreateTestUser(t)
+	readKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+	writeKey := CreateTestAPIKey(t, user.Token, domain.ScopeCampaignsWrite)
+	input := domain.CampaignCreationInput{
+		Title:          "Bot Campaign",
+		SettingSummary: "Created by a script",
+		IsPublic:       true,
+	}
+
+	// When creating a campaign with the read key
+	statusCode := CreateCampaign(t, readKey.Key, input, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// When creating a campaign with the campaigns:write key
+	statusCode = CreateCampaign(t, writeKey.Key, input, nil)
+
+	// Then the campaign should be created
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	// And the campaigns:write key should not be able to read
+	assert.Equal(t, http.StatusForbidden, GetMe(t, writeKey.Key))
+
+	// And no key should be able to create other keys
+	assert.Equal(t, http.StatusForbidden, CreateAPIKey(t, writeKey.Key, domain.APIKeyInput{Name: "Bot", Scopes: []string{domain.ScopeRead}}, nil))
+}
+
+func TestRevokeAPIKey_Success(t *testing.T) {
+	// Given a user with an API key
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+	require.Equal(t, http.StatusOK, GetMe(t, apiKey.Key))
+
+	// When revoking the key
+	statusCode := RevokeAPIKey(t, user.Token, apiKey.ID.Bytes)
+
+	// Then it should be revoked and no longer listed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	var apiKeys []domain.APIKey
+	require.Equal(t, http.StatusOK, ListAPIKeys(t, user.Token, &apiKeys))
+	assert.Empty(t, apiKeys)
+
+	// And requests made with it should be unauthorized
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, apiKey.Key))
+
+	// And revoking it again should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, RevokeAPIKey(t, user.Token, apiKey.ID.Bytes))
+}
+
+func TestRevokeAPIKey_Failure_OtherUser(t *testing.T) {
+	// Given an API key of another user
+	owner := CreateTestUser(t)
+	otherUser := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, owner.Token, domain.ScopeRead)
+
+	// When the other user tries to revoke it
+	statusCode := RevokeAPIKey(t, otherUser.Token, apiKey.ID.Bytes)
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// And the key should still work
+	assert.Equal(t, http.StatusOK, GetMe(t, apiKey.Key))
+}
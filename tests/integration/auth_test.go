@@ -247,10 +247,16 @@ func TestMe_Success(t *testing.T) {
 	user := CreateTestUser(t)
 
 	// When getting the user's profile
-	statusCode := SendAuthenticatedRequest(t, "GET", "/api/me", user.Token, nil, nil)
+	var profile map[string]interface{}
+	statusCode := SendAuthenticatedRequest(t, "GET", "/api/me", user.Token, nil, &profile)
 
 	// Then the profile should be retrieved successfully
 	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, user.Username, profile["username"])
+	assert.Equal(t, user.Email, profile["email"])
+
+	// And the password hash should never be exposed
+	assert.NotContains(t, profile, "hashed_password")
 }
 
 func TestMe_Failure_Unauthorized(t *testing.T) {
@@ -268,3 +274,94 @@ func TestMe_Failure_Unauthorized(t *testing.T) {
 	// Then it should fail with an unauthorized status
 	assert.Equal(t, http.StatusUnauthorized, statusCode)
 }
+
+func TestRefresh_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When exchanging the refresh token
+	var authOutput domain.AuthOutput
+	statusCode := RefreshTokens(t, user.RefreshToken, &authOutput)
+
+	// Then a new pair of tokens should be issued
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, authOutput.Token)
+	assert.NotEqual(t, user.Token, authOutput.Token)
+	assert.NotEqual(t, user.RefreshToken, authOutput.RefreshToken)
+	assert.Equal(t, user.Username, authOutput.User.Username)
+
+	// And the new access token should be accepted
+	assert.Equal(t, http.StatusOK, GetMe(t, authOutput.Token))
+}
+
+func TestRefresh_Failure_ReusedTokenRevokesFamily(t *testing.T) {
+	// Given a user who already rotated their refresh token
+	user := CreateTestUser(t)
+	var rotated domain.AuthOutput
+	require.Equal(t, http.StatusOK, RefreshTokens(t, user.RefreshToken, &rotated))
+
+	// When the old refresh token is presented again
+	statusCode := RefreshTokens(t, user.RefreshToken, nil)
+
+	// Then it should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// And the whole session should be revoked
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, rotated.RefreshToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, rotated.Token))
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, user.Token))
+}
+
+func TestRefresh_Failure_InvalidToken(t *testing.T) {
+	// When exchanging an unknown refresh token
+	statusCode := RefreshTokens(t, "not-a-refresh-token", nil)
+
+	// Then it should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestLogout_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+	require.Equal(t, http.StatusOK, GetMe(t, user.Token))
+
+	// When the user logs out
+	statusCode := Logout(t, user.Token, user.RefreshToken)
+
+	// Then the logout should succeed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And neither the access token nor the refresh token should be accepted anymore
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, user.Token))
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, user.RefreshToken, nil))
+}
+
+func TestLogout_Success_KeepsOtherSessions(t *testing.T) {
+	// Given a user logged in on two devices
+	user := CreateTestUser(t)
+	var otherSession domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, &otherSession))
+
+	// When the user logs out of the first one
+	require.Equal(t, http.StatusNoContent, Logout(t, user.Token, user.RefreshToken))
+
+	// Then the other session should still work
+	assert.Equal(t, http.StatusOK, GetMe(t, otherSession.Token))
+	assert.Equal(t, http.StatusOK, RefreshTokens(t, otherSession.RefreshToken, nil))
+}
+
+func TestLogout_Success_APIKey(t *testing.T) {
+	// Given a user with an API key
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+
+	// When logging out with the key as the bearer token
+	statusCode := Logout(t, apiKey.Key, "")
+
+	// Then the logout should succeed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the key should be rejected from then on, while the session keeps working
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, apiKey.Key))
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+}
@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCampaign_Failure_NonMemberOfPublicCampaign(t *testing.T) {
+	// Given a public campaign
+	owner := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, owner.Token, true)
+
+	// When a user who isn't a member of it updates it
+	stranger := CreateTestUser(t)
+	updateInput := domain.UpdateCampaignInput{Title: "Hijacked campaign", IsPublic: true}
+	statusCode := UpdateCampaign(t, stranger.Token, campaign.ID.Bytes, updateInput, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// And the campaign should be left untouched
+	var current sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, owner.Token, campaign.ID.Bytes, &current))
+	assert.Equal(t, campaign.Title, current.Title)
+}
+
+func TestUpdateCampaign_Failure_NonMemberOfPrivateCampaign(t *testing.T) {
+	// Given a private campaign
+	owner := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, owner.Token, false)
+
+	// When a user who isn't a member of it updates or deletes it
+	stranger := CreateTestUser(t)
+	updateInput := domain.UpdateCampaignInput{Title: "Hijacked campaign"}
+	updateStatusCode := UpdateCampaign(t, stranger.Token, campaign.ID.Bytes, updateInput, nil)
+	deleteStatusCode := DeleteCampaign(t, stranger.Token, campaign.ID.Bytes)
+
+	// Then both should fail with a not found status, without revealing the campaign exists
+	assert.Equal(t, http.StatusNotFound, updateStatusCode)
+	assert.Equal(t, http.StatusNotFound, deleteStatusCode)
+}
+
+func TestUpdateCampaign_Failure_Player(t *testing.T) {
+	// Given a public campaign with a player
+	owner := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, owner.Token, true)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, owner.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player updates or deletes it
+	updateInput := domain.UpdateCampaignInput{Title: "Player's campaign", IsPublic: true}
+	updateStatusCode := UpdateCampaign(t, player.Token, campaign.ID.Bytes, updateInput, nil)
+	deleteStatusCode := DeleteCampaign(t, player.Token, campaign.ID.Bytes)
+
+	// Then both should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, updateStatusCode)
+	assert.Equal(t, http.StatusForbidden, deleteStatusCode)
+
+	// And the campaign should be left untouched
+	var current sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, owner.Token, campaign.ID.Bytes, &current))
+	assert.Equal(t, campaign.Title, current.Title)
+}
+
+func TestDeleteCampaign_Failure_NonMemberOfPublicCampaign(t *testing.T) {
+	// Given a public campaign
+	owner := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, owner.Token, true)
+
+	// When a user who isn't a member of it deletes it
+	statusCode := DeleteCampaign(t, CreateTestUser(t).Token, campaign.ID.Bytes)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, http.StatusOK, GetCampaign(t, owner.Token, campaign.ID.Bytes, nil))
+}
+
+func TestUpdateCampaign_Success_OtherGameMaster(t *testing.T) {
+	// Given a campaign with a second game master
+	owner := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, owner.Token, false)
+	gm := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, owner.Token, campaign.ID.Bytes, gm.User.ID.Bytes, "gm"))
+
+	// When the second game master updates it
+	var updated sqlc.Campaign
+	updateInput := domain.UpdateCampaignInput{Title: "Co-written campaign"}
+	statusCode := UpdateCampaign(t, gm.Token, campaign.ID.Bytes, updateInput, &updated)
+
+	// Then it should be updated
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Co-written campaign", updated.Title)
+	assert.Equal(t, campaign.Version+1, updated.Version)
+}
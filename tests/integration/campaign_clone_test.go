@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneCampaign_Success(t *testing.T) {
+	// Given a public campaign with an NPC, a player character, a public and a secret timeline event
+	owner := CreateTestUser(t)
+	input := domain.CampaignCreationInput{
+		Title:          "The Shattered Crown",
+		SettingSummary: "A kingdom torn apart",
+		IsPublic:       true,
+		Tags:           []string{"intrigue"},
+		GameSystem:     "D&D 5e",
+	}
+	var source sqlc.Campaign
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, owner.Token, input, &source))
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, owner.Token, source.ID.Bytes, domain.CharacterInput{Name: "Queen Maeve", IsNPC: true}, nil))
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, owner.Token, source.ID.Bytes, domain.CharacterInput{Name: "Elara"}, nil))
+	isPublic, isSecret := true, false
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, owner.Token, source.ID.Bytes, domain.TimelineEventInput{Title: "The coronation", IsPublic: &isPublic}, nil))
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, owner.Token, source.ID.Bytes, domain.TimelineEventInput{Title: "The queen's betrayal", IsPublic: &isSecret}, nil))
+
+	// When another user clones it
+	user := CreateTestUser(t)
+	var clone sqlc.Campaign
+	statusCode := CloneCampaign(t, user.Token, source.ID.Bytes, &clone)
+
+	// Then they should own a private copy of it, pointing back at the source
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, source.Title, clone.Title)
+	assert.Equal(t, source.SettingSummary, clone.SettingSummary)
+	assert.Equal(t, source.Tags, clone.Tags)
+	assert.Equal(t, source.GameSystem, clone.GameSystem)
+	assert.False(t, clone.IsPublic)
+	assert.Equal(t, user.User.ID, clone.CreatedBy)
+	assert.Equal(t, source.ID, clone.ClonedFrom)
+
+	// And they should be its game master
+	var members []sqlc.ListCampaignMembersRow
+	require.Equal(t, http.StatusOK, ListCampaignMembers(t, user.Token, clone.ID.Bytes, &members))
+	require.Len(t, members, 1)
+	assert.Equal(t, user.User.ID, members[0].UserID)
+	assert.Equal(t, sqlc.MemberRoleGm, members[0].Role)
+
+	// And only the NPC and the public timeline event should be copied
+	var characters []sqlc.Character
+	require.Equal(t, http.StatusOK, ListCharacters(t, user.Token, clone.ID.Bytes, &characters))
+	require.Len(t, characters, 1)
+	assert.Equal(t, "Queen Maeve", characters[0].Name)
+	assert.Equal(t, user.User.ID, characters[0].UserID)
+	var events []sqlc.TimelineEvent
+	require.Equal(t, http.StatusOK, ListTimelineEvents(t, user.Token, clone.ID.Bytes, "", &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "The coronation", events[0].Title)
+
+	// And the source should count the clone
+	require.Equal(t, http.StatusOK, GetCampaign(t, owner.Token, source.ID.Bytes, &source))
+	assert.Equal(t, int32(1), source.CloneCount)
+}
+
+func TestCloneCampaign_Failure_PrivateCampaign(t *testing.T) {
+	// Given a private campaign with a player
+	owner := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, owner.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, owner.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When a stranger or even its player clones it
+	strangerStatusCode := CloneCampaign(t, CreateTestUser(t).Token, campaign.ID.Bytes, nil)
+	playerStatusCode := CloneCampaign(t, player.Token, campaign.ID.Bytes, nil)
+
+	// Then both should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, strangerStatusCode)
+	assert.Equal(t, http.StatusNotFound, playerStatusCode)
+}
+
+func TestCloneCampaign_Failure_NotFound(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When cloning a campaign that doesn't exist
+	statusCode := CloneCampaign(t, user.Token, uuid.New(), nil)
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
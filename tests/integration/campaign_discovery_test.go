@@ -0,0 +1,209 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uniqueTag returns a tag no other test uses, so listings only hold the campaigns of the test
+func uniqueTag() string {
+	return "tag-" + uuid.New().String()[:8]
+}
+
+func campaignTitles(page domain.PublicCampaignPage) []string {
+	titles := make([]string, 0, len(page.Campaigns))
+	for _, campaign := range page.Campaigns {
+		titles = append(titles, campaign.Title)
+	}
+	return titles
+}
+
+func TestCreateCampaign_Success_TagsAndGameSystem(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When creating a campaign with tags and a game system
+	input := domain.CampaignCreationInput{
+		Title:      "Tagged campaign",
+		Tags:       []string{" Horror ", "homebrew", "HORROR", ""},
+		GameSystem: "Call of Cthulhu",
+	}
+	var campaign sqlc.Campaign
+	statusCode := CreateCampaign(t, user.Token, input, &campaign)
+
+	// Then the tags should be normalized
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, []string{"horror", "homebrew"}, campaign.Tags)
+	assert.Equal(t, "Call of Cthulhu", campaign.GameSystem.String)
+
+	// When creating a campaign with too many tags
+	input.Tags = []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+	statusCode = CreateCampaign(t, user.Token, input, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestListPublicCampaigns_Success_Search(t *testing.T) {
+	// Given public campaigns and a private one sharing a tag
+	user := CreateTestUser(t)
+	tag := uniqueTag()
+	CreateTaggedCampaign(t, user.Token, "Wings over Greyhold", "A war against ancient dragons", tag)
+	CreateTaggedCampaign(t, user.Token, "Night in Ravenloft", "Vampires rule the mists", tag)
+	privateInput := domain.CampaignCreationInput{Title: "Secret dragons", Tags: []string{tag}}
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, user.Token, privateInput, nil))
+
+	// When searching them for dragons
+	var page domain.PublicCampaignPage
+	statusCode := ListPublicCampaigns(t, user.Token, url.Values{"q": {"dragon"}, "tags": {tag}}, &page)
+
+	// Then only the public campaign mentioning them should be found
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"Wings over Greyhold"}, campaignTitles(page))
+	assert.Equal(t, user.Username, page.Campaigns[0].CreatorUsername)
+	assert.Equal(t, int64(1), page.Campaigns[0].MemberCount)
+	assert.Empty(t, page.NextCursor)
+
+	// When listing them without a search
+	statusCode = ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag}}, &page)
+
+	// Then both public campaigns should be listed, newest first
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"Night in Ravenloft", "Wings over Greyhold"}, campaignTitles(page))
+}
+
+func TestListPublicCampaigns_Success_Filters(t *testing.T) {
+	// Given public campaigns of two users with different tags and game systems
+	user := CreateTestUser(t)
+	otherUser := CreateTestUser(t)
+	tag := uniqueTag()
+	input := domain.CampaignCreationInput{Title: "Horror 5e", IsPublic: true, Tags: []string{tag, "horror"}, GameSystem: "D&D 5e"}
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, user.Token, input, nil))
+	input = domain.CampaignCreationInput{Title: "Horror Cthulhu", IsPublic: true, Tags: []string{tag, "horror"}, GameSystem: "Call of Cthulhu"}
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, otherUser.Token, input, nil))
+	input = domain.CampaignCreationInput{Title: "Heist 5e", IsPublic: true, Tags: []string{tag, "heist"}, GameSystem: "D&D 5e"}
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, user.Token, input, nil))
+
+	// When filtering by tags, regardless of casing
+	var page domain.PublicCampaignPage
+	statusCode := ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag + ",HORROR"}}, &page)
+
+	// Then only the campaigns with every tag should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.ElementsMatch(t, []string{"Horror 5e", "Horror Cthulhu"}, campaignTitles(page))
+
+	// When filtering by game system
+	statusCode = ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag}, "system": {"d&d 5E"}}, &page)
+
+	// Then only the campaigns of that system should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.ElementsMatch(t, []string{"Horror 5e", "Heist 5e"}, campaignTitles(page))
+
+	// When filtering by creator
+	statusCode = ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag}, "creator": {otherUser.Username}}, &page)
+
+	// Then only the campaigns of that user should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"Horror Cthulhu"}, campaignTitles(page))
+}
+
+func TestListPublicCampaigns_Success_Pagination(t *testing.T) {
+	// Given five public campaigns
+	user := CreateTestUser(t)
+	tag := uniqueTag()
+	for _, title := range []string{"First", "Second", "Third", "Fourth", "Fifth"} {
+		CreateTaggedCampaign(t, user.Token, title, "", tag)
+	}
+
+	// When listing them two at a time
+	var titles []string
+	cursor := ""
+	pages := 0
+	for {
+		query := url.Values{"tags": {tag}, "limit": {"2"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var page domain.PublicCampaignPage
+		require.Equal(t, http.StatusOK, ListPublicCampaigns(t, user.Token, query, &page))
+		titles = append(titles, campaignTitles(page)...)
+		pages++
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// Then every campaign should be listed once, newest first
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"Fifth", "Fourth", "Third", "Second", "First"}, titles)
+}
+
+func TestListPublicCampaigns_Success_Sort(t *testing.T) {
+	// Given public campaigns with different numbers of members and clones
+	user := CreateTestUser(t)
+	player := CreateTestUser(t)
+	tag := uniqueTag()
+	crowded := CreateTaggedCampaign(t, user.Token, "Crowded", "", tag)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, user.Token, crowded.ID.Bytes, player.User.ID.Bytes, "player"))
+	popular := CreateTaggedCampaign(t, user.Token, "Popular", "", tag)
+	_, err := TestDB.Exec(context.Background(), "UPDATE campaigns SET clone_count = 3 WHERE id = $1", popular.ID)
+	require.NoError(t, err)
+	CreateTaggedCampaign(t, user.Token, "Newest", "", tag)
+
+	// When sorting them by members
+	var page domain.PublicCampaignPage
+	statusCode := ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag}, "sort": {domain.CampaignSortMostMembers}}, &page)
+
+	// Then the campaign with the most members should come first
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, page.Campaigns, 3)
+	assert.Equal(t, "Crowded", page.Campaigns[0].Title)
+	assert.Equal(t, int64(2), page.Campaigns[0].MemberCount)
+
+	// When sorting them by clones
+	statusCode = ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag}, "sort": {domain.CampaignSortMostCloned}}, &page)
+
+	// Then the most cloned campaign should come first
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, page.Campaigns, 3)
+	assert.Equal(t, "Popular", page.Campaigns[0].Title)
+	assert.Equal(t, int32(3), page.Campaigns[0].CloneCount)
+}
+
+func TestListPublicCampaigns_Failure_InvalidParameters(t *testing.T) {
+	// Given a page of public campaigns sorted by newest
+	user := CreateTestUser(t)
+	tag := uniqueTag()
+	CreateTaggedCampaign(t, user.Token, "One", "", tag)
+	CreateTaggedCampaign(t, user.Token, "Two", "", tag)
+	var page domain.PublicCampaignPage
+	require.Equal(t, http.StatusOK, ListPublicCampaigns(t, user.Token, url.Values{"tags": {tag}, "limit": {"1"}}, &page))
+	require.NotEmpty(t, page.NextCursor)
+
+	testCases := map[string]url.Values{
+		"unknown sort":               {"sort": {"oldest"}},
+		"limit too large":            {"limit": {"1000"}},
+		"limit not a number":         {"limit": {"ten"}},
+		"malformed cursor":           {"cursor": {"not-a-cursor"}},
+		"cursor of a different sort": {"cursor": {page.NextCursor}, "sort": {domain.CampaignSortMostMembers}},
+	}
+	for name, query := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// When listing with the invalid parameter
+			statusCode := ListPublicCampaigns(t, user.Token, query, nil)
+
+			// Then it should fail with a bad request status
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+	}
+}
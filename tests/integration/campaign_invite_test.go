@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinCampaign_Success(t *testing.T) {
+	// Given a GM with a private campaign and an invite code
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	var inviteCode domain.InviteCodeOutput
+	statusCode := GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{}, &inviteCode)
+	require.Equal(t, http.StatusCreated, statusCode)
+	require.NotEmpty(t, inviteCode.Code)
+
+	// When another user joins with the code
+	player := CreateTestUser(t)
+	var joinedCampaign sqlc.Campaign
+	statusCode = JoinCampaign(t, player.Token, inviteCode.Code, &joinedCampaign)
+
+	// Then the user should join the campaign as a player
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, campaign.ID.Bytes, joinedCampaign.ID.Bytes)
+	assert.False(t, joinedCampaign.InviteCode.Valid)
+
+	var members []sqlc.ListCampaignMembersRow
+	statusCode = ListCampaignMembers(t, player.Token, campaign.ID.Bytes, &members)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	roles := make(map[string]sqlc.MemberRole)
+	for _, member := range members {
+		roles[member.Username] = member.Role
+	}
+	assert.Equal(t, sqlc.MemberRolePlayer, roles[player.Username])
+
+	// And joining again should fail with a conflict status
+	statusCode = JoinCampaign(t, player.Token, inviteCode.Code, nil)
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestJoinCampaign_Failure_MaxUsesReached(t *testing.T) {
+	// Given a campaign with a single-use invite code
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	var inviteCode domain.InviteCodeOutput
+	statusCode := GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{MaxUses: 1}, &inviteCode)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	// And a first user who used it
+	firstPlayer := CreateTestUser(t)
+	require.Equal(t, http.StatusCreated, JoinCampaign(t, firstPlayer.Token, inviteCode.Code, nil))
+
+	// When a second user tries to join with the same code
+	secondPlayer := CreateTestUser(t)
+	statusCode = JoinCampaign(t, secondPlayer.Token, inviteCode.Code, nil)
+
+	// Then it should fail with a gone status
+	assert.Equal(t, http.StatusGone, statusCode)
+}
+
+func TestJoinCampaign_Failure_ConcurrentJoins(t *testing.T) {
+	// Given a campaign with an invite code that can be used twice
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	var inviteCode domain.InviteCodeOutput
+	statusCode := GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{MaxUses: 2}, &inviteCode)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	// When the same user joins with it twice at the same time
+	player := CreateTestUser(t)
+	statusCodes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range statusCodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statusCodes[i] = JoinCampaign(t, player.Token, inviteCode.Code, nil)
+		}()
+	}
+	wg.Wait()
+
+	// Then only one join should succeed
+	assert.ElementsMatch(t, []int{http.StatusCreated, http.StatusConflict}, statusCodes)
+
+	// And only that one should have used the code
+	var uses int32
+	err := TestDB.QueryRow(context.Background(), "SELECT invite_code_uses FROM campaigns WHERE id = $1", campaign.ID).Scan(&uses)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), uses)
+
+	// And another user should still be able to join
+	assert.Equal(t, http.StatusCreated, JoinCampaign(t, CreateTestUser(t).Token, inviteCode.Code, nil))
+}
+
+func TestJoinCampaign_Failure_Expired(t *testing.T) {
+	// Given a campaign with an invite code that has expired
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	var inviteCode domain.InviteCodeOutput
+	statusCode := GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{ExpiresInHours: 1}, &inviteCode)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	_, err := TestDB.Exec(context.Background(),
+		"UPDATE campaigns SET invite_code_expires_at = CURRENT_TIMESTAMP - INTERVAL '1 minute' WHERE invite_code = $1",
+		inviteCode.Code)
+	require.NoError(t, err)
+
+	// When a user tries to join with it
+	player := CreateTestUser(t)
+	statusCode = JoinCampaign(t, player.Token, inviteCode.Code, nil)
+
+	// Then it should fail with a gone status
+	assert.Equal(t, http.StatusGone, statusCode)
+}
+
+func TestJoinCampaign_Failure_RotatedAndRevoked(t *testing.T) {
+	// Given a campaign whose invite code was rotated
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	var oldCode, newCode domain.InviteCodeOutput
+	require.Equal(t, http.StatusCreated, GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{}, &oldCode))
+	require.Equal(t, http.StatusCreated, GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{}, &newCode))
+	require.NotEqual(t, oldCode.Code, newCode.Code)
+
+	// When a user joins with the old code
+	player := CreateTestUser(t)
+	statusCode := JoinCampaign(t, player.Token, oldCode.Code, nil)
+
+	// Then it should not be found
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// And once the GM revokes the current code it should stop working too
+	require.Equal(t, http.StatusNoContent, RevokeInviteCode(t, gm.Token, campaign.ID.Bytes))
+	statusCode = JoinCampaign(t, player.Token, newCode.Code, nil)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestGenerateInviteCode_Failure_NotGM(t *testing.T) {
+	// Given a public campaign and a player in it
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, true)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player tries to generate an invite code
+	statusCode := GenerateInviteCode(t, player.Token, campaign.ID.Bytes, domain.InviteCodeInput{}, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// And the player should not see the GM's invite code on the campaign
+	var inviteCode domain.InviteCodeOutput
+	require.Equal(t, http.StatusCreated, GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{}, &inviteCode))
+
+	var retrievedCampaign sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, player.Token, campaign.ID.Bytes, &retrievedCampaign))
+	assert.False(t, retrievedCampaign.InviteCode.Valid)
+}
+
+func TestGenerateInviteCode_Failure_InvalidInput(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When generating a code with negative limits
+	statusCode := GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{MaxUses: -1}, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userCampaignTitles(page domain.UserCampaignPage) []string {
+	titles := make([]string, 0, len(page.Campaigns))
+	for _, campaign := range page.Campaigns {
+		titles = append(titles, campaign.Title)
+	}
+	return titles
+}
+
+func createTitledCampaign(t *testing.T, token, title string) sqlc.Campaign {
+	var campaign sqlc.Campaign
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, token, domain.CampaignCreationInput{Title: title}, &campaign))
+	return campaign
+}
+
+func TestListUserCampaigns_Success_RoleFilter(t *testing.T) {
+	// Given a user running a campaign and playing in another one
+	user := CreateTestUser(t)
+	createTitledCampaign(t, user.Token, "My table")
+	owner := CreateTestUser(t)
+	otherCampaign := createTitledCampaign(t, owner.Token, "Friday night game")
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, owner.Token, otherCampaign.ID.Bytes, user.User.ID.Bytes, "player"))
+
+	// When listing the campaigns they run
+	var page domain.UserCampaignPage
+	statusCode := ListUserCampaigns(t, user.Token, url.Values{"role": {"gm"}}, &page)
+
+	// Then only the campaign they are GM of should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, page.Campaigns, 1)
+	assert.Equal(t, "My table", page.Campaigns[0].Title)
+	assert.Equal(t, sqlc.MemberRoleGm, page.Campaigns[0].Role)
+
+	// When listing the campaigns they play in
+	statusCode = ListUserCampaigns(t, user.Token, url.Values{"role": {"player"}}, &page)
+
+	// Then only the other campaign should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, page.Campaigns, 1)
+	assert.Equal(t, "Friday night game", page.Campaigns[0].Title)
+	assert.Equal(t, sqlc.MemberRolePlayer, page.Campaigns[0].Role)
+}
+
+func TestListUserCampaigns_Success_TextFilter(t *testing.T) {
+	// Given a user with two campaigns
+	user := CreateTestUser(t)
+	createTitledCampaign(t, user.Token, "Curse of Strahd")
+	createTitledCampaign(t, user.Token, "Lost Mine of Phandelver")
+
+	// When searching their campaigns, regardless of casing
+	var page domain.UserCampaignPage
+	statusCode := ListUserCampaigns(t, user.Token, url.Values{"q": {"STRAHD"}}, &page)
+
+	// Then only the matching campaign should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"Curse of Strahd"}, userCampaignTitles(page))
+}
+
+func TestListUserCampaigns_Success_Archived(t *testing.T) {
+	// Given a game master with two campaigns and a player in one of them
+	user := CreateTestUser(t)
+	oldCampaign := createTitledCampaign(t, user.Token, "Finished campaign")
+	createTitledCampaign(t, user.Token, "Ongoing campaign")
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, user.Token, oldCampaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player archives the campaign
+	statusCode := ArchiveCampaign(t, player.Token, oldCampaign.ID.Bytes, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// When the game master archives it
+	var archived sqlc.Campaign
+	statusCode = ArchiveCampaign(t, user.Token, oldCampaign.ID.Bytes, &archived)
+
+	// Then it should be archived
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, archived.ArchivedAt.Valid)
+
+	// And it should only be listed with the archived campaigns
+	var page domain.UserCampaignPage
+	require.Equal(t, http.StatusOK, ListUserCampaigns(t, user.Token, nil, &page))
+	assert.Equal(t, []string{"Ongoing campaign"}, userCampaignTitles(page))
+	require.Equal(t, http.StatusOK, ListUserCampaigns(t, user.Token, url.Values{"archived": {"true"}}, &page))
+	assert.Equal(t, []string{"Finished campaign"}, userCampaignTitles(page))
+
+	// When the game master brings it back
+	statusCode = UnarchiveCampaign(t, user.Token, oldCampaign.ID.Bytes, &archived)
+
+	// Then it should be listed with the active campaigns again
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, archived.ArchivedAt.Valid)
+	require.Equal(t, http.StatusOK, ListUserCampaigns(t, user.Token, nil, &page))
+	assert.ElementsMatch(t, []string{"Finished campaign", "Ongoing campaign"}, userCampaignTitles(page))
+}
+
+func TestListUserCampaigns_Success_Sort(t *testing.T) {
+	// Given a user with two campaigns, the first one opened after the second one was created
+	user := CreateTestUser(t)
+	first := createTitledCampaign(t, user.Token, "First")
+	createTitledCampaign(t, user.Token, "Second")
+	require.Equal(t, http.StatusOK, GetCampaign(t, user.Token, first.ID.Bytes, nil))
+
+	// When listing them by last update
+	var page domain.UserCampaignPage
+	statusCode := ListUserCampaigns(t, user.Token, nil, &page)
+
+	// Then the most recently updated campaign should come first
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"Second", "First"}, userCampaignTitles(page))
+
+	// When listing them by last access
+	statusCode = ListUserCampaigns(t, user.Token, url.Values{"sort": {domain.CampaignSortLastAccessed}}, &page)
+
+	// Then the most recently opened campaign should come first
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"First", "Second"}, userCampaignTitles(page))
+	assert.True(t, page.Campaigns[0].LastAccessed.Valid)
+}
+
+func TestListUserCampaigns_Success_Pagination(t *testing.T) {
+	// Given a user with three campaigns
+	user := CreateTestUser(t)
+	for _, title := range []string{"First", "Second", "Third"} {
+		createTitledCampaign(t, user.Token, title)
+	}
+
+	// When listing them one at a time
+	var titles []string
+	cursor := ""
+	for {
+		query := url.Values{"limit": {"1"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var page domain.UserCampaignPage
+		require.Equal(t, http.StatusOK, ListUserCampaigns(t, user.Token, query, &page))
+		titles = append(titles, userCampaignTitles(page)...)
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// Then every campaign should be listed once, most recently updated first
+	assert.Equal(t, []string{"Third", "Second", "First"}, titles)
+}
+
+func TestListUserCampaigns_Failure_InvalidParameters(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	testCases := map[string]url.Values{
+		"unknown role":        {"role": {"owner"}},
+		"unknown sort":        {"sort": {"newest"}},
+		"archived not a bool": {"archived": {"maybe"}},
+		"limit too large":     {"limit": {"1000"}},
+		"malformed cursor":    {"cursor": {"not-a-cursor"}},
+	}
+	for name, query := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// When listing their campaigns with the invalid parameter
+			statusCode := ListUserCampaigns(t, user.Token, query, nil)
+
+			// Then it should fail with a bad request status
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCampaignMember_Success(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// And another registered user
+	player := CreateTestUser(t)
+
+	// When the GM adds the user as a player
+	statusCode := AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player")
+
+	// Then the member should be added successfully
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the member list should include the player with their username
+	var members []sqlc.ListCampaignMembersRow
+	statusCode = ListCampaignMembers(t, player.Token, campaign.ID.Bytes, &members)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, members, 2)
+
+	roles := make(map[string]sqlc.MemberRole)
+	for _, member := range members {
+		roles[member.Username] = member.Role
+	}
+	assert.Equal(t, sqlc.MemberRoleGm, roles[gm.Username])
+	assert.Equal(t, sqlc.MemberRolePlayer, roles[player.Username])
+}
+
+func TestAddCampaignMember_Failure_NotGM(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// And a user outside the campaign
+	outsider := CreateTestUser(t)
+
+	// When the player tries to add the outsider
+	statusCode := AddCampaignMember(t, player.Token, campaign.ID.Bytes, outsider.User.ID.Bytes, "player")
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestAddCampaignMember_Failure_AlreadyMember(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the GM adds the same player again
+	statusCode := AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player")
+
+	// Then it should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestAddCampaignMember_Failure_InvalidRole(t *testing.T) {
+	// Given a GM with a campaign and another user
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+
+	// When the GM adds the user with an unknown role
+	statusCode := AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "dragon")
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestRemoveCampaignMember_Success(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the GM removes the player
+	statusCode := RemoveCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes)
+
+	// Then the member should be removed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the player should no longer see the private campaign
+	statusCode = GetCampaign(t, player.Token, campaign.ID.Bytes, nil)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestRemoveCampaignMember_Failure_NotMember(t *testing.T) {
+	// Given a GM with a campaign and a user outside it
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	outsider := CreateTestUser(t)
+
+	// When the GM removes the outsider
+	statusCode := RemoveCampaignMember(t, gm.Token, campaign.ID.Bytes, outsider.User.ID.Bytes)
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestLeaveCampaign_Success(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player leaves the campaign
+	statusCode := LeaveCampaign(t, player.Token, campaign.ID.Bytes)
+
+	// Then the player should have left successfully
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the GM should be the only member left
+	var members []sqlc.ListCampaignMembersRow
+	statusCode = ListCampaignMembers(t, gm.Token, campaign.ID.Bytes, &members)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, members, 1)
+}
+
+func TestLeaveCampaign_Failure_LastGM(t *testing.T) {
+	// Given a campaign with a single GM
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When the GM tries to leave
+	statusCode := LeaveCampaign(t, gm.Token, campaign.ID.Bytes)
+
+	// Then it should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	// But once a second GM is added
+	coGM := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, coGM.User.ID.Bytes, "gm"))
+
+	// Then the original GM can leave
+	statusCode = LeaveCampaign(t, gm.Token, campaign.ID.Bytes)
+	assert.Equal(t, http.StatusNoContent, statusCode)
+}
+
+func TestLeaveCampaign_Failure_ConcurrentGMs(t *testing.T) {
+	// Given a campaign with two GMs
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	coGM := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, coGM.User.ID.Bytes, "gm"))
+
+	// When both GMs leave at the same time
+	statusCodes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i, token := range []string{gm.Token, coGM.Token} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statusCodes[i] = LeaveCampaign(t, token, campaign.ID.Bytes)
+		}()
+	}
+	wg.Wait()
+
+	// Then only one of them should leave, the campaign keeps a GM
+	assert.ElementsMatch(t, []int{http.StatusNoContent, http.StatusConflict}, statusCodes)
+}
+
+func TestGetCampaignMembers_Failure_NonMember(t *testing.T) {
+	// Given a private and a public campaign
+	gm := CreateTestUser(t)
+	privateCampaign := CreateTestCampaign(t, gm.Token, false)
+	publicCampaign := CreateTestCampaign(t, gm.Token, true)
+
+	// And a user outside both campaigns
+	outsider := CreateTestUser(t)
+
+	// When the outsider lists the private campaign members
+	statusCode := ListCampaignMembers(t, outsider.Token, privateCampaign.ID.Bytes, nil)
+
+	// Then the campaign should not be found
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// When the outsider lists the public campaign members
+	statusCode = ListCampaignMembers(t, outsider.Token, publicCampaign.ID.Bytes, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
@@ -0,0 +1,122 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchCampaign_Success(t *testing.T) {
+	// Given a campaign with a setting and a game system
+	user := CreateTestUser(t)
+	input := domain.CampaignCreationInput{
+		Title:          "The Shattered Crown",
+		SettingSummary: "A kingdom torn apart",
+		Setting:        "Long description of the kingdom",
+		GameSystem:     "D&D 5e",
+	}
+	var campaign sqlc.Campaign
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, user.Token, input, &campaign))
+
+	// When patching its title and clearing its game system
+	var patched sqlc.Campaign
+	statusCode, header := PatchCampaign(t, user.Token, campaign.ID.Bytes, map[string]any{
+		"title":       "The Mended Crown",
+		"game_system": nil,
+	}, "", &patched)
+
+	// Then only those fields should change
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "The Mended Crown", patched.Title)
+	assert.False(t, patched.GameSystem.Valid)
+	assert.Equal(t, campaign.SettingSummary, patched.SettingSummary)
+	assert.Equal(t, campaign.Setting, patched.Setting)
+
+	// And the version should be bumped
+	assert.Equal(t, campaign.Version+1, patched.Version)
+	assert.Equal(t, header.Get("ETag"), GetCampaignETag(t, user.Token, campaign.ID.Bytes))
+}
+
+func TestPatchCampaign_Failure_StaleETag(t *testing.T) {
+	// Given a campaign fetched by two game masters
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, false)
+	otherGM := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, user.Token, campaign.ID.Bytes, otherGM.User.ID.Bytes, "gm"))
+	etag := GetCampaignETag(t, user.Token, campaign.ID.Bytes)
+	require.Equal(t, etag, GetCampaignETag(t, otherGM.Token, campaign.ID.Bytes))
+
+	// When the first one patches it
+	statusCode, _ := PatchCampaign(t, user.Token, campaign.ID.Bytes, map[string]any{"title": "First edit"}, etag, nil)
+
+	// Then it should succeed
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// When the second one patches it with the ETag they fetched
+	statusCode, _ = PatchCampaign(t, otherGM.Token, campaign.ID.Bytes, map[string]any{"title": "Second edit"}, etag, nil)
+
+	// Then it should fail with a precondition failed status, leaving the first edit in place
+	assert.Equal(t, http.StatusPreconditionFailed, statusCode)
+	var current sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, otherGM.Token, campaign.ID.Bytes, &current))
+	assert.Equal(t, "First edit", current.Title)
+
+	// When they patch it again with the new ETag
+	statusCode, _ = PatchCampaign(t, otherGM.Token, campaign.ID.Bytes, map[string]any{"title": "Second edit"}, GetCampaignETag(t, otherGM.Token, campaign.ID.Bytes), nil)
+
+	// Then it should succeed
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestPatchCampaign_Failure_NotGM(t *testing.T) {
+	// Given a campaign with a player
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, user.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player and a stranger patch it
+	patch := map[string]any{"title": "Hijacked"}
+	playerStatusCode, _ := PatchCampaign(t, player.Token, campaign.ID.Bytes, patch, "", nil)
+	strangerStatusCode, _ := PatchCampaign(t, CreateTestUser(t).Token, campaign.ID.Bytes, patch, "", nil)
+
+	// Then the player should be forbidden and the private campaign not found for the stranger
+	assert.Equal(t, http.StatusForbidden, playerStatusCode)
+	assert.Equal(t, http.StatusNotFound, strangerStatusCode)
+
+	// And the campaign should be left untouched
+	var current sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, user.Token, campaign.ID.Bytes, &current))
+	assert.Equal(t, campaign.Title, current.Title)
+	assert.Equal(t, campaign.Version, current.Version)
+}
+
+func TestPatchCampaign_Failure_InvalidPatch(t *testing.T) {
+	// Given a campaign
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, false)
+
+	// When patching it with a cleared title, a wrong type, a read-only field or too many tags
+	patches := []map[string]any{
+		{"title": nil},
+		{"is_public": "yes"},
+		{"created_by": user.User.ID},
+		{"tags": []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}},
+	}
+	for _, patch := range patches {
+		statusCode, _ := PatchCampaign(t, user.Token, campaign.ID.Bytes, patch, "", nil)
+
+		// Then it should fail with a bad request status
+		assert.Equal(t, http.StatusBadRequest, statusCode, "patch %v", patch)
+	}
+
+	// And the campaign should be left untouched
+	var current sqlc.Campaign
+	require.Equal(t, http.StatusOK, GetCampaign(t, user.Token, campaign.ID.Bytes, &current))
+	assert.Equal(t, campaign.Title, current.Title)
+	assert.Equal(t, campaign.Version, current.Version)
+}
@@ -278,7 +278,7 @@ func TestDeleteCampaign_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, statusCode)
 }
 
-func TestDeleteCampaign_Success_NotFound(t *testing.T) {
+func TestDeleteCampaign_Failure_NotFound(t *testing.T) {
 	// Given a registered and authenticated user
 	user := CreateTestUser(t)
 
@@ -289,7 +289,7 @@ func TestDeleteCampaign_Success_NotFound(t *testing.T) {
 	statusCode := DeleteCampaign(t, user.Token, nonExistentID)
 
 	// Then it should fail with a not found status
-	assert.Equal(t, http.StatusNoContent, statusCode)
+	assert.Equal(t, http.StatusNotFound, statusCode)
 }
 
 func TestDeleteCampaign_Failure_Unauthorized(t *testing.T) {
@@ -338,17 +338,18 @@ func TestListUserCampaigns_Success(t *testing.T) {
 	}
 
 	// When listing the user's campaigns
-	var campaigns []sqlc.Campaign
-	statusCode := ListUserCampaigns(t, user.Token, &campaigns)
+	var page domain.UserCampaignPage
+	statusCode := ListUserCampaigns(t, user.Token, nil, &page)
 
 	// Then the campaigns should be listed successfully
 	assert.Equal(t, http.StatusOK, statusCode)
-	assert.GreaterOrEqual(t, len(campaigns), len(campaignTitles))
+	assert.GreaterOrEqual(t, len(page.Campaigns), len(campaignTitles))
 
-	// And the list should contain the created campaigns
+	// And the list should contain the created campaigns, with the user as their GM
 	titles := make(map[string]bool)
-	for _, campaign := range campaigns {
+	for _, campaign := range page.Campaigns {
 		titles[campaign.Title] = true
+		assert.Equal(t, sqlc.MemberRoleGm, campaign.Role)
 	}
 
 	for _, title := range campaignTitles {
@@ -359,7 +360,7 @@ func TestListUserCampaigns_Success(t *testing.T) {
 func TestListUserCampaigns_Failure_Unauthorized(t *testing.T) {
 	// Given no authentication token
 	// When listing campaigns without a token
-	statusCode := ListUserCampaigns(t, "", nil)
+	statusCode := ListUserCampaigns(t, "", nil, nil)
 
 	// Then it should fail with an unauthorized status
 	assert.Equal(t, http.StatusUnauthorized, statusCode)
@@ -0,0 +1,209 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCharacter_Success(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player creates a character
+	input := domain.CharacterInput{
+		Name:     "Elara Moonwhisper",
+		Race:     "Elf",
+		Class:    "Ranger",
+		Level:    3,
+		Metadata: json.RawMessage(`{"alignment":"chaotic good"}`),
+	}
+	var character sqlc.Character
+	statusCode := CreateCharacter(t, player.Token, campaign.ID.Bytes, input, &character)
+
+	// Then the character should be created and owned by the player
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, input.Name, character.Name)
+	assert.Equal(t, input.Race, character.Race.String)
+	assert.Equal(t, input.Level, character.Level)
+	assert.Equal(t, player.User.ID, character.UserID)
+	assert.False(t, character.IsNpc)
+	assert.JSONEq(t, `{"alignment":"chaotic good"}`, string(character.Metadata))
+
+	// And the GM should see it in the campaign characters
+	var characters []sqlc.Character
+	statusCode = ListCharacters(t, gm.Token, campaign.ID.Bytes, &characters)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, characters, 1)
+	assert.Equal(t, character.ID, characters[0].ID)
+}
+
+func TestCreateCharacter_Success_GMCreatesNPC(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When the GM creates an NPC without a level
+	input := domain.CharacterInput{
+		Name:  "Old Tom",
+		IsNPC: true,
+	}
+	var character sqlc.Character
+	statusCode := CreateCharacter(t, gm.Token, campaign.ID.Bytes, input, &character)
+
+	// Then the NPC should be created with the default level and empty metadata
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.True(t, character.IsNpc)
+	assert.Equal(t, int32(domain.MinCharacterLevel), character.Level)
+	assert.JSONEq(t, `{}`, string(character.Metadata))
+
+	// And it should be retrievable by id
+	var fetched sqlc.Character
+	statusCode = GetCharacter(t, gm.Token, campaign.ID.Bytes, character.ID.Bytes, &fetched)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, character.Name, fetched.Name)
+}
+
+func TestCreateCharacter_Failure_PlayerCreatesNPC(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player tries to create an NPC
+	input := domain.CharacterInput{Name: "Villain", IsNPC: true}
+	statusCode := CreateCharacter(t, player.Token, campaign.ID.Bytes, input, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestCreateCharacter_Failure_InvalidInput(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	testCases := []struct {
+		name  string
+		input domain.CharacterInput
+	}{
+		{name: "missing name", input: domain.CharacterInput{}},
+		{name: "level out of range", input: domain.CharacterInput{Name: "Hero", Level: 101}},
+		{name: "metadata not an object", input: domain.CharacterInput{Name: "Hero", Metadata: json.RawMessage(`[1, 2]`)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// When the GM creates a character with invalid input
+			statusCode := CreateCharacter(t, gm.Token, campaign.ID.Bytes, tc.input, nil)
+
+			// Then it should fail with a bad request status
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+	}
+}
+
+func TestCreateCharacter_Failure_NotMember(t *testing.T) {
+	// Given a private campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When a user outside the campaign tries to create a character
+	outsider := CreateTestUser(t)
+	statusCode := CreateCharacter(t, outsider.Token, campaign.ID.Bytes, domain.CharacterInput{Name: "Intruder"}, nil)
+
+	// Then the campaign should not be found
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestUpdateCharacter_Success(t *testing.T) {
+	// Given a player with a character
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+	var character sqlc.Character
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, player.Token, campaign.ID.Bytes, domain.CharacterInput{Name: "Borin"}, &character))
+
+	// When the player levels up the character
+	input := domain.CharacterInput{Name: "Borin Ironfist", Class: "Fighter", Level: 2}
+	var updated sqlc.Character
+	statusCode := UpdateCharacter(t, player.Token, campaign.ID.Bytes, character.ID.Bytes, input, &updated)
+
+	// Then the character should be updated
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, input.Name, updated.Name)
+	assert.Equal(t, input.Class, updated.Class.String)
+	assert.Equal(t, input.Level, updated.Level)
+
+	// And the GM should be able to edit it too
+	input.Level = 3
+	statusCode = UpdateCharacter(t, gm.Token, campaign.ID.Bytes, character.ID.Bytes, input, &updated)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, int32(3), updated.Level)
+}
+
+func TestUpdateCharacter_Failure_NotOwner(t *testing.T) {
+	// Given two players, one of them with a character
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	owner := CreateTestUser(t)
+	other := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, owner.User.ID.Bytes, "player"))
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, other.User.ID.Bytes, "player"))
+	var character sqlc.Character
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, owner.Token, campaign.ID.Bytes, domain.CharacterInput{Name: "Borin"}, &character))
+
+	// When the other player tries to edit or delete it
+	updateStatusCode := UpdateCharacter(t, other.Token, campaign.ID.Bytes, character.ID.Bytes, domain.CharacterInput{Name: "Hijacked"}, nil)
+	deleteStatusCode := DeleteCharacter(t, other.Token, campaign.ID.Bytes, character.ID.Bytes)
+
+	// Then both should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, updateStatusCode)
+	assert.Equal(t, http.StatusForbidden, deleteStatusCode)
+}
+
+func TestUpdateCharacter_Failure_PlayerEditsNPC(t *testing.T) {
+	// Given a campaign with a player and an NPC
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+	var npc sqlc.Character
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, gm.Token, campaign.ID.Bytes, domain.CharacterInput{Name: "Old Tom", IsNPC: true}, &npc))
+
+	// When the player tries to edit the NPC
+	statusCode := UpdateCharacter(t, player.Token, campaign.ID.Bytes, npc.ID.Bytes, domain.CharacterInput{Name: "Young Tom"}, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestDeleteCharacter_Success(t *testing.T) {
+	// Given a player with a character
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+	var character sqlc.Character
+	require.Equal(t, http.StatusCreated, CreateCharacter(t, player.Token, campaign.ID.Bytes, domain.CharacterInput{Name: "Borin"}, &character))
+
+	// When the player deletes the character
+	statusCode := DeleteCharacter(t, player.Token, campaign.ID.Bytes, character.ID.Bytes)
+
+	// Then the character should be deleted
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And it should no longer be found
+	statusCode = GetCharacter(t, gm.Token, campaign.ID.Bytes, character.ID.Bytes, nil)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
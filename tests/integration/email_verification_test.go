@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmail_Success(t *testing.T) {
+	// Given a newly registered user
+	user := CreateUnverifiedTestUser(t)
+	assert.False(t, user.User.IsActive)
+
+	// And the verification email sent on registration
+	token := ExtractLinkToken(t, ReadLatestEmail(t, user.Email), "/verify-email")
+
+	// When the user verifies their email
+	statusCode := VerifyEmail(t, token)
+
+	// Then the verification should succeed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the account should be active
+	var isActive bool
+	err := TestDB.QueryRow(context.Background(), "SELECT is_active FROM users WHERE id = $1", user.User.ID).Scan(&isActive)
+	require.NoError(t, err)
+	assert.True(t, isActive)
+
+	// And verifying again should still succeed
+	assert.Equal(t, http.StatusNoContent, VerifyEmail(t, token))
+}
+
+func TestVerifyEmail_Failure_InvalidToken(t *testing.T) {
+	// Given a logged user
+	user := CreateUnverifiedTestUser(t)
+
+	// When verifying with a garbage token or with an access token
+	garbageStatusCode := VerifyEmail(t, "v2.public.not-a-token")
+	accessTokenStatusCode := VerifyEmail(t, user.Token)
+
+	// Then both should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, garbageStatusCode)
+	assert.Equal(t, http.StatusBadRequest, accessTokenStatusCode)
+}
+
+func TestVerifyEmail_Failure_TokenUsedAsAccessToken(t *testing.T) {
+	// Given the verification token of a user
+	user := CreateUnverifiedTestUser(t)
+	token := ExtractLinkToken(t, ReadLatestEmail(t, user.Email), "/verify-email")
+
+	// When using it to authenticate
+	statusCode := GetMe(t, token)
+
+	// Then it should be rejected
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestResendVerification_Failure_Throttled(t *testing.T) {
+	// Given a user who just registered and received a verification email
+	user := CreateUnverifiedTestUser(t)
+
+	// When asking for another email right away
+	statusCode := ResendVerification(t, user.Token)
+
+	// Then it should be throttled
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+
+	// When the resend interval has passed
+	_, err := TestDB.Exec(context.Background(),
+		"UPDATE users SET verification_sent_at = CURRENT_TIMESTAMP - INTERVAL '1 day' WHERE id = $1",
+		user.User.ID)
+	require.NoError(t, err)
+	statusCode = ResendVerification(t, user.Token)
+
+	// Then a new email should be sent with a working link
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	token := ExtractLinkToken(t, ReadLatestEmail(t, user.Email), "/verify-email")
+	assert.Equal(t, http.StatusNoContent, VerifyEmail(t, token))
+}
+
+func TestResendVerification_Failure_AlreadyVerified(t *testing.T) {
+	// Given a verified user
+	user := CreateTestUser(t)
+
+	// When asking for a verification email
+	statusCode := ResendVerification(t, user.Token)
+
+	// Then it should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestUnverifiedUser_Failure_CreateOrJoinCampaigns(t *testing.T) {
+	// Given an unverified user who can still log in
+	user := CreateUnverifiedTestUser(t)
+	require.Equal(t, http.StatusOK, GetMe(t, user.Token))
+
+	// When creating a campaign
+	input := domain.CampaignCreationInput{Title: "Unverified campaign"}
+	statusCode := CreateCampaign(t, user.Token, input, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// When joining a campaign with an invite code
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	var inviteCode domain.InviteCodeOutput
+	require.Equal(t, http.StatusCreated, GenerateInviteCode(t, gm.Token, campaign.ID.Bytes, domain.InviteCodeInput{}, &inviteCode))
+	statusCode = JoinCampaign(t, user.Token, inviteCode.Code, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// When the user verifies their email and tries again
+	token := ExtractLinkToken(t, ReadLatestEmail(t, user.Email), "/verify-email")
+	require.Equal(t, http.StatusNoContent, VerifyEmail(t, token))
+
+	// Then both actions should succeed
+	var created sqlc.Campaign
+	assert.Equal(t, http.StatusCreated, CreateCampaign(t, user.Token, input, &created))
+	assert.Equal(t, http.StatusCreated, JoinCampaign(t, user.Token, inviteCode.Code, nil))
+}
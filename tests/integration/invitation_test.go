@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteByEmail_Success(t *testing.T) {
+	// Given a GM with a private campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// And a registered user who is not a member
+	player := CreateTestUser(t)
+
+	// When the GM invites the user by email
+	var invitation sqlc.Invitation
+	statusCode := InviteByEmail(t, gm.Token, campaign.ID.Bytes, player.Email, &invitation)
+
+	// Then the invitation should be created as pending
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, sqlc.InvitationStatusPending, invitation.Status)
+
+	// And the invitee should receive an email with the invite link
+	email := ReadLatestEmail(t, player.Email)
+	assert.Contains(t, email, campaign.Title)
+	invitationToken := ExtractLinkToken(t, email, "/invitations")
+
+	// And the invitation should be listed for the invitee
+	var pending []sqlc.ListPendingInvitationsByEmailRow
+	statusCode = ListUserInvitations(t, player.Token, &pending)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, pending, 1)
+	assert.Equal(t, campaign.Title, pending[0].CampaignTitle)
+	assert.Equal(t, gm.Username, pending[0].InvitedByUsername)
+
+	// And the listing should not expose the invitation token
+	var rawPending []map[string]interface{}
+	require.Equal(t, http.StatusOK, ListUserInvitations(t, player.Token, &rawPending))
+	require.Len(t, rawPending, 1)
+	assert.NotContains(t, rawPending[0], "token")
+
+	// When the invitee accepts the invitation
+	var accepted sqlc.Invitation
+	statusCode = AcceptInvitation(t, player.Token, invitationToken, &accepted)
+
+	// Then the invitation should be accepted
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, sqlc.InvitationStatusAccepted, accepted.Status)
+
+	// And the invitee should now be able to see the private campaign
+	statusCode = GetCampaign(t, player.Token, campaign.ID.Bytes, nil)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// And accepting again should fail with a conflict status
+	statusCode = AcceptInvitation(t, player.Token, invitationToken, nil)
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestRejectInvitation_Success(t *testing.T) {
+	// Given a pending invitation
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, gm.Token, campaign.ID.Bytes, player.Email, nil))
+	invitationToken := ExtractLinkToken(t, ReadLatestEmail(t, player.Email), "/invitations")
+
+	// When the invitee rejects it
+	var rejected sqlc.Invitation
+	statusCode := RejectInvitation(t, player.Token, invitationToken, &rejected)
+
+	// Then the invitation should be rejected
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, sqlc.InvitationStatusRejected, rejected.Status)
+
+	// And the invitee should not have joined the campaign
+	statusCode = GetCampaign(t, player.Token, campaign.ID.Bytes, nil)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestAcceptInvitation_Failure_OtherUser(t *testing.T) {
+	// Given a pending invitation for one user
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, gm.Token, campaign.ID.Bytes, player.Email, nil))
+	invitationToken := ExtractLinkToken(t, ReadLatestEmail(t, player.Email), "/invitations")
+
+	// When a different user tries to accept it
+	intruder := CreateTestUser(t)
+	statusCode := AcceptInvitation(t, intruder.Token, invitationToken, nil)
+
+	// Then the invitation should not be found
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestAcceptInvitation_Failure_UnverifiedEmail(t *testing.T) {
+	// Given a pending invitation for a user who hasn't verified their email
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateUnverifiedTestUser(t)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, gm.Token, campaign.ID.Bytes, player.Email, nil))
+	invitationToken := ExtractLinkToken(t, ReadLatestEmail(t, player.Email), "/invitations")
+
+	// When the user lists, accepts or rejects their invitations
+	listStatusCode := ListUserInvitations(t, player.Token, nil)
+	acceptStatusCode := AcceptInvitation(t, player.Token, invitationToken, nil)
+	rejectStatusCode := RejectInvitation(t, player.Token, invitationToken, nil)
+
+	// Then all of them should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, listStatusCode)
+	assert.Equal(t, http.StatusForbidden, acceptStatusCode)
+	assert.Equal(t, http.StatusForbidden, rejectStatusCode)
+
+	// And the player should not have joined the campaign
+	assert.Equal(t, http.StatusNotFound, GetCampaign(t, player.Token, campaign.ID.Bytes, nil))
+}
+
+func TestAcceptInvitation_Failure_Expired(t *testing.T) {
+	// Given an invitation whose deadline has passed
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, gm.Token, campaign.ID.Bytes, player.Email, nil))
+	invitationToken := ExtractLinkToken(t, ReadLatestEmail(t, player.Email), "/invitations")
+
+	_, err := TestDB.Exec(context.Background(),
+		"UPDATE invitations SET expires_at = CURRENT_TIMESTAMP - INTERVAL '1 minute' WHERE token = $1",
+		invitationToken)
+	require.NoError(t, err)
+
+	// When the invitee tries to accept it
+	statusCode := AcceptInvitation(t, player.Token, invitationToken, nil)
+
+	// Then it should fail with a gone status
+	assert.Equal(t, http.StatusGone, statusCode)
+
+	// And the GM should see it as expired
+	var invitations []sqlc.ListInvitationsByCampaignRow
+	statusCode = ListCampaignInvitations(t, gm.Token, campaign.ID.Bytes, &invitations)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, invitations, 1)
+	assert.Equal(t, sqlc.InvitationStatusExpired, invitations[0].Status)
+}
+
+func TestInviteByEmail_Failure_NotGM(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player tries to invite someone
+	statusCode := InviteByEmail(t, player.Token, campaign.ID.Bytes, "friend@example.com", nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestInviteByEmail_Failure_AlreadyInvited(t *testing.T) {
+	// Given a pending invitation for an email
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, gm.Token, campaign.ID.Bytes, "friend@example.com", nil))
+
+	// When the GM invites the same email again
+	statusCode := InviteByEmail(t, gm.Token, campaign.ID.Bytes, "Friend@Example.com", nil)
+
+	// Then it should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestInviteByEmail_Failure_InvalidEmail(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When the GM invites an invalid email
+	statusCode := InviteByEmail(t, gm.Token, campaign.ID.Bytes, "not-an-email", nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestCancelInvitation_Success(t *testing.T) {
+	// Given a pending invitation
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	var invitation sqlc.Invitation
+	require.Equal(t, http.StatusCreated, InviteByEmail(t, gm.Token, campaign.ID.Bytes, "friend@example.com", &invitation))
+
+	// When the GM cancels it
+	statusCode := CancelInvitation(t, gm.Token, campaign.ID.Bytes, invitation.ID.Bytes)
+
+	// Then it should be deleted
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And cancelling it again should fail with a not found status
+	statusCode = CancelInvitation(t, gm.Token, campaign.ID.Bytes, invitation.ID.Bytes)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
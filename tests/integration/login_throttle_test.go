@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogin_Failure_AccountLockout(t *testing.T) {
+	// Given a user
+	user := CreateTestUser(t)
+	accountKey := "account:" + strings.ToLower(user.Username)
+	t.Cleanup(func() { DeleteLoginThrottle(t, accountKey) })
+	wrongInput := domain.LoginInput{Username: user.Username, Password: "WrongPassword!"}
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+
+	// When failing to log in 5 times from different addresses
+	for i := 0; i < 5; i++ {
+		statusCode, _ := LoginUserFromIP(t, wrongInput, RandomTestIP(t), nil)
+		require.Equal(t, http.StatusUnauthorized, statusCode)
+	}
+
+	// Then the account should be locked, even with the right password
+	statusCode, headers := LoginUserFromIP(t, input, RandomTestIP(t), nil)
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+	retryAfter, err := strconv.Atoi(headers.Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, retryAfter, 2)
+
+	// When the lockout is over and the password is wrong again
+	expireLoginLockout(t, accountKey)
+	statusCode, _ = LoginUserFromIP(t, wrongInput, RandomTestIP(t), nil)
+	require.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// Then the account should be locked for twice as long
+	var lockedUntil pgtype.Timestamptz
+	err = TestDB.QueryRow(context.Background(), "SELECT locked_until FROM login_throttles WHERE key = $1", accountKey).Scan(&lockedUntil)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), lockedUntil.Time, 10*time.Second)
+
+	// When the lockout is over and the password is right
+	expireLoginLockout(t, accountKey)
+	var authOutput domain.AuthOutput
+	statusCode, _ = LoginUserFromIP(t, input, RandomTestIP(t), &authOutput)
+
+	// Then the login should succeed and the failures be forgotten
+	assert.Equal(t, http.StatusOK, statusCode)
+	var throttles int
+	err = TestDB.QueryRow(context.Background(), "SELECT COUNT(*) FROM login_throttles WHERE key = $1", accountKey).Scan(&throttles)
+	require.NoError(t, err)
+	assert.Zero(t, throttles)
+}
+
+func TestLogin_Failure_IPLockout(t *testing.T) {
+	// Given a user and an address that failed to log into 20 unknown accounts
+	user := CreateTestUser(t)
+	ipAddress := RandomTestIP(t)
+	for i := 0; i < 20; i++ {
+		username := "unknown_" + strconv.Itoa(i) + "_" + user.Username
+		t.Cleanup(func() { DeleteLoginThrottle(t, "account:"+strings.ToLower(username)) })
+
+		statusCode, _ := LoginUserFromIP(t, domain.LoginInput{Username: username, Password: "WrongPassword!"}, ipAddress, nil)
+		require.Equal(t, http.StatusUnauthorized, statusCode)
+	}
+
+	// When the user logs in from that address
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	statusCode, _ := LoginUserFromIP(t, input, ipAddress, nil)
+
+	// Then it should be locked out
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+
+	// And logging in from another address should still work
+	statusCode, _ = LoginUserFromIP(t, input, RandomTestIP(t), nil)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestLogin_Failure_UnknownUser(t *testing.T) {
+	// Given a username that doesn't belong to any account
+	username := "nobody_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	t.Cleanup(func() { DeleteLoginThrottle(t, "account:"+username) })
+
+	// When logging in with it
+	statusCode, _ := LoginUserFromIP(t, domain.LoginInput{Username: username, Password: "Password123!"}, RandomTestIP(t), nil)
+
+	// Then it should fail the same way as a wrong password
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestLogin_Success_UpdatesLastLogin(t *testing.T) {
+	// Given a user who registered but never logged in
+	user := CreateTestUser(t)
+	assert.False(t, user.User.LastLoginAt.Valid)
+
+	// When logging in
+	statusCode, _ := LoginUserFromIP(t, domain.LoginInput{Username: user.Username, Password: user.Password}, RandomTestIP(t), nil)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// Then the last login should be recorded
+	var profile domain.UserProfile
+	require.Equal(t, http.StatusOK, SendAuthenticatedRequest(t, "GET", "/api/me", user.Token, nil, &profile))
+	require.True(t, profile.LastLoginAt.Valid)
+	assert.WithinDuration(t, time.Now(), profile.LastLoginAt.Time, time.Minute)
+}
+
+// expireLoginLockout ends the lockout of an account or IP address key right away
+func expireLoginLockout(t *testing.T, key string) {
+	_, err := TestDB.Exec(context.Background(),
+		"UPDATE login_throttles SET locked_until = CURRENT_TIMESTAMP - INTERVAL '1 second' WHERE key = $1", key)
+	require.NoError(t, err)
+}
+
+func TestChangePassword_Failure_AccountLockout(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+	accountKey := "account:" + strings.ToLower(user.Username)
+	t.Cleanup(func() { DeleteLoginThrottle(t, accountKey) })
+
+	// When confirming a password change with a wrong password 5 times
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusForbidden, ChangePassword(t, user.Token, "WrongPassword!", "NewPassword456!"))
+	}
+
+	// Then the account should be locked, even with the right password
+	statusCode := ChangePassword(t, user.Token, user.Password, "NewPassword456!")
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+
+	// And deleting the account should be locked as well
+	assert.Equal(t, http.StatusTooManyRequests, DeleteMe(t, user.Token, user.Password, nil))
+
+	// And the login too, since the failures count for the whole account
+	statusCode, _ = LoginUserFromIP(t, domain.LoginInput{Username: user.Username, Password: user.Password}, RandomTestIP(t), nil)
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+}
@@ -0,0 +1,177 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/knands42/lorecrafter/app/api"
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableMFA_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When starting the enrollment
+	var enrollment domain.MFAEnrollment
+	statusCode := EnrollMFA(t, user.Token, &enrollment)
+
+	// Then a secret and a provisioning URI should be returned
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/"))
+	assert.Contains(t, enrollment.ProvisioningURI, user.Username)
+	assert.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+
+	// And two-factor authentication should not be enabled yet
+	var profile domain.UserProfile
+	require.Equal(t, http.StatusOK, SendAuthenticatedRequest(t, "GET", "/api/me", user.Token, nil, &profile))
+	assert.False(t, profile.MFAEnabled)
+
+	// When confirming with a wrong code
+	statusCode = ConfirmMFA(t, user.Token, "000000", nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// When confirming with a code from the authenticator app
+	var recoveryCodes domain.MFARecoveryCodes
+	statusCode = ConfirmMFA(t, user.Token, GenerateTOTPCode(t, enrollment.Secret, time.Now()), &recoveryCodes)
+
+	// Then two-factor authentication should be enabled and the recovery codes returned
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, recoveryCodes.RecoveryCodes, 10)
+	require.Equal(t, http.StatusOK, SendAuthenticatedRequest(t, "GET", "/api/me", user.Token, nil, &profile))
+	assert.True(t, profile.MFAEnabled)
+
+	// And enrolling again should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, EnrollMFA(t, user.Token, nil))
+}
+
+func TestLoginMFA_Success(t *testing.T) {
+	// Given a user with two-factor authentication enabled
+	user := CreateTestUser(t)
+	secret, _ := EnableTestMFA(t, user)
+
+	// When logging in with the password
+	var challenge domain.MFAChallenge
+	statusCode := LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, &challenge)
+
+	// Then a code should be required
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.True(t, challenge.MFARequired)
+	require.NotEmpty(t, challenge.MFAToken)
+
+	// And the MFA token should not be usable as an access token
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, challenge.MFAToken))
+
+	// When completing the login with the next code of the authenticator app
+	var authOutput domain.AuthOutput
+	statusCode = LoginMFA(t, challenge.MFAToken, GenerateTOTPCode(t, secret, time.Now().Add(30*time.Second)), &authOutput)
+
+	// Then the tokens should be issued
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, http.StatusOK, GetMe(t, authOutput.Token))
+
+	// And the MFA token should not be usable again
+	statusCode = LoginMFA(t, challenge.MFAToken, GenerateTOTPCode(t, secret, time.Now().Add(60*time.Second)), nil)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestLoginMFA_Failure_InvalidCode(t *testing.T) {
+	// Given a user with two-factor authentication enabled who entered their password
+	user := CreateTestUser(t)
+	secret, _ := EnableTestMFA(t, user)
+	var challenge domain.MFAChallenge
+	status := LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, &challenge)
+	require.Equal(t, http.StatusAccepted, status)
+
+	// When completing the login with a wrong code
+	wrongCodeStatusCode := LoginMFA(t, challenge.MFAToken, "000000", nil)
+
+	// Or with the code that was already used to enable two-factor authentication
+	replayedCodeStatusCode := LoginMFA(t, challenge.MFAToken, GenerateTOTPCode(t, secret, time.Now()), nil)
+
+	// Then both should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, wrongCodeStatusCode)
+	assert.Equal(t, http.StatusUnauthorized, replayedCodeStatusCode)
+}
+
+func TestLoginMFA_Success_RecoveryCode(t *testing.T) {
+	// Given a user with two-factor authentication enabled who lost their authenticator app
+	user := CreateTestUser(t)
+	_, recoveryCodes := EnableTestMFA(t, user)
+	loginInput := domain.LoginInput{Username: user.Username, Password: user.Password}
+
+	var challenge domain.MFAChallenge
+	require.Equal(t, http.StatusAccepted, LoginUser(t, loginInput, &challenge))
+
+	// When completing the login with a recovery code
+	statusCode := LoginMFA(t, challenge.MFAToken, strings.ToLower(recoveryCodes[0]), nil)
+
+	// Then the login should succeed
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// And the same recovery code should not work twice
+	require.Equal(t, http.StatusAccepted, LoginUser(t, loginInput, &challenge))
+	assert.Equal(t, http.StatusUnauthorized, LoginMFA(t, challenge.MFAToken, recoveryCodes[0], nil))
+	assert.Equal(t, http.StatusOK, LoginMFA(t, challenge.MFAToken, recoveryCodes[1], nil))
+}
+
+func TestDisableMFA_Success(t *testing.T) {
+	// Given a user with two-factor authentication enabled
+	user := CreateTestUser(t)
+	_, recoveryCodes := EnableTestMFA(t, user)
+
+	// When disabling it with a wrong password
+	statusCode := DisableMFA(t, user.Token, "WrongPassword!", recoveryCodes[0])
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// When disabling it with the password and a recovery code
+	statusCode = DisableMFA(t, user.Token, user.Password, recoveryCodes[0])
+
+	// Then it should be disabled
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the login should not ask for a code anymore
+	var authOutput domain.AuthOutput
+	statusCode = LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, &authOutput)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, authOutput.Token)
+}
+
+func TestEnableMFA_Failure_NotConfigured(t *testing.T) {
+	// Given a server started without an MFA encryption key
+	cfg := TestConfig
+	cfg.MFAEncryptionKey = ""
+	server := httptest.NewServer(api.NewServer(cfg, sqlc.New(TestDB)).Router)
+	t.Cleanup(server.Close)
+
+	// When a user logs in on it
+	user := CreateTestUser(t)
+	var output domain.AuthOutput
+	statusCode := SendRequestToServer(t, server, "POST", "/api/auth/login", "",
+		domain.LoginInput{Username: user.Username, Password: user.Password}, &output)
+
+	// Then the login should work as usual
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, output.Token)
+
+	// When they start enabling two-factor authentication
+	statusCode = SendRequestToServer(t, server, "POST", "/api/me/mfa", output.Token, nil, nil)
+
+	// Then it should fail with a service unavailable status
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+
+	// And confirming a code should fail the same way
+	statusCode = SendRequestToServer(t, server, "POST", "/api/me/mfa/confirm", output.Token, domain.MFACodeInput{Code: "123456"}, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+}
@@ -0,0 +1,221 @@
+package integration
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	MockOIDCClientID     = "lorecrafter-test"
+	MockOIDCClientSecret = "lorecrafter-test-secret"
+	mockOIDCKeyID        = "mock-key"
+)
+
+// MockOIDCUser is the account the mock provider logs in as, the tests pick it in the query of the authorization URL
+type MockOIDCUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// MockOIDCServer is an OpenID Connect provider issuing ID tokens for whoever the authorization request asks for
+type MockOIDCServer struct {
+	*httptest.Server
+
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+}
+
+// mockAuthorization is an authorization code waiting to be exchanged
+type mockAuthorization struct {
+	user          MockOIDCUser
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+}
+
+// NewMockOIDCServer starts a mock provider, its URL is the issuer
+func NewMockOIDCServer() (*MockOIDCServer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	mock := &MockOIDCServer{
+		key:   key,
+		codes: make(map[string]mockAuthorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", mock.discovery)
+	mux.HandleFunc("GET /authorize", mock.authorize)
+	mux.HandleFunc("POST /token", mock.token)
+	mux.HandleFunc("GET /jwks", mock.jwks)
+	mock.Server = httptest.NewServer(mux)
+
+	return mock, nil
+}
+
+func (mock *MockOIDCServer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                mock.URL,
+		"authorization_endpoint":                mock.URL + "/authorize",
+		"token_endpoint":                        mock.URL + "/token",
+		"jwks_uri":                              mock.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize logs in right away as the user in the sub, email, email_verified and name query parameters
+func (mock *MockOIDCServer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != MockOIDCClientID || query.Get("response_type") != "code" ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || query.Get("redirect_uri") == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := randomMockValue()
+	mock.mu.Lock()
+	mock.codes[code] = mockAuthorization{
+		user: MockOIDCUser{
+			Subject:       query.Get("sub"),
+			Email:         query.Get("email"),
+			EmailVerified: query.Get("email_verified") == "true",
+			Name:          query.Get("name"),
+		},
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	mock.mu.Unlock()
+
+	callback := redirectURI.Query()
+	callback.Set("code", code)
+	callback.Set("state", query.Get("state"))
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// token exchanges a code once, checking the client credentials and the PKCE code verifier
+func (mock *MockOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != MockOIDCClientID || clientSecret != MockOIDCClientSecret {
+		writeMockJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	mock.mu.Lock()
+	authorization, ok := mock.codes[r.PostForm.Get("code")]
+	delete(mock.codes, r.PostForm.Get("code"))
+	mock.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != authorization.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.codeChallenge {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := mock.idToken(authorization)
+	if err != nil {
+		writeMockJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeMockJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomMockValue(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (mock *MockOIDCServer) jwks(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": mockOIDCKeyID,
+			"n":   base64.RawURLEncoding.EncodeToString(mock.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(mock.key.E)).Bytes()),
+		}},
+	})
+}
+
+// idToken signs an RS256 JWT for the user of the authorization
+func (mock *MockOIDCServer) idToken(authorization mockAuthorization) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": mockOIDCKeyID, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims, err := json.Marshal(map[string]any{
+		"iss":            mock.URL,
+		"sub":            authorization.user.Subject,
+		"aud":            MockOIDCClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          authorization.user.Email,
+		"email_verified": authorization.user.EmailVerified,
+		"name":           authorization.user.Name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, mock.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func writeMockJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomMockValue() string {
+	value := make([]byte, 16)
+	_, _ = rand.Read(value)
+	return hex.EncodeToString(value)
+}
@@ -0,0 +1,241 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOIDCProviders_Success(t *testing.T) {
+	// When listing the login providers
+	var providers domain.OIDCProviders
+	statusCode := SendRequest(t, "GET", "/api/auth/oidc", nil, &providers)
+
+	// Then the mock provider should be listed
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Contains(t, providers.Providers, "mock")
+}
+
+func TestOIDCLogin_Success_ProvisionsUser(t *testing.T) {
+	// Given a provider account that never logged in
+	providerUser := NewMockOIDCUser()
+
+	// When logging in with the provider
+	var authOutput domain.AuthOutput
+	statusCode := LoginWithOIDC(t, providerUser, &authOutput)
+
+	// Then a verified user should be created and logged in
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, authOutput.Token)
+	assert.NotEmpty(t, authOutput.RefreshToken)
+	assert.Equal(t, providerUser.Email, authOutput.User.Email)
+	assert.True(t, authOutput.User.IsActive)
+	assert.NotEmpty(t, authOutput.User.Username)
+	assert.Equal(t, http.StatusOK, GetMe(t, authOutput.Token))
+
+	// And the provider account should be linked to it
+	var identities []domain.UserIdentity
+	require.Equal(t, http.StatusOK, ListIdentities(t, authOutput.Token, &identities))
+	require.Len(t, identities, 1)
+	assert.Equal(t, "mock", identities[0].Provider)
+	assert.Equal(t, providerUser.Email, identities[0].Email.String)
+}
+
+func TestOIDCLogin_Success_ReturningUser(t *testing.T) {
+	// Given a provider account that already logged in once
+	providerUser := NewMockOIDCUser()
+	var firstLogin domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginWithOIDC(t, providerUser, &firstLogin))
+
+	// When logging in with it again, even with another email address at the provider
+	providerUser.Email = "changed_" + providerUser.Email
+	var secondLogin domain.AuthOutput
+	statusCode := LoginWithOIDC(t, providerUser, &secondLogin)
+
+	// Then the same user should be logged in
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, firstLogin.User.ID, secondLogin.User.ID)
+	assert.Equal(t, firstLogin.User.Username, secondLogin.User.Username)
+}
+
+func TestOIDCLogin_Success_UnverifiedEmail(t *testing.T) {
+	// Given a provider account whose email address the provider didn't verify
+	providerUser := NewMockOIDCUser()
+	providerUser.EmailVerified = false
+
+	// When logging in with the provider
+	var authOutput domain.AuthOutput
+	statusCode := LoginWithOIDC(t, providerUser, &authOutput)
+
+	// Then the user should be created but have to verify their email address
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, authOutput.User.IsActive)
+	WaitForEmail(t, providerUser.Email, "/verify-email/")
+}
+
+func TestOIDCLogin_Success_UsernameTaken(t *testing.T) {
+	// Given a provider account whose name gives the username of an existing user
+	user := CreateTestUser(t)
+	providerUser := NewMockOIDCUser()
+	providerUser.Name = user.Username
+
+	// When logging in with the provider
+	var authOutput domain.AuthOutput
+	statusCode := LoginWithOIDC(t, providerUser, &authOutput)
+
+	// Then the new user should get another username
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEqual(t, user.Username, authOutput.User.Username)
+	assert.Contains(t, authOutput.User.Username, user.Username)
+}
+
+func TestOIDCLogin_Failure_EmailTaken(t *testing.T) {
+	// Given a provider account with the email address of an existing user
+	user := CreateTestUser(t)
+	providerUser := NewMockOIDCUser()
+	providerUser.Email = user.Email
+
+	// When logging in with the provider
+	statusCode := LoginWithOIDC(t, providerUser, nil)
+
+	// Then it should fail with a conflict status instead of taking over the account
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestOIDCLogin_Success_MFAEnabled(t *testing.T) {
+	// Given a provider account linked to a user with two-factor authentication enabled
+	providerUser := NewMockOIDCUser()
+	var authOutput domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginWithOIDC(t, providerUser, &authOutput))
+	secret, _ := EnableTestMFA(t, TestUser{Token: authOutput.Token, Username: authOutput.User.Username})
+
+	// When logging in with the provider
+	var challenge domain.MFAChallenge
+	statusCode := LoginWithOIDC(t, providerUser, &challenge)
+
+	// Then an MFA challenge should be returned instead of tokens
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.True(t, challenge.MFARequired)
+
+	// And completing it should log the user in
+	var mfaOutput domain.AuthOutput
+	code := GenerateTOTPCode(t, secret, time.Now().Add(30*time.Second))
+	assert.Equal(t, http.StatusOK, LoginMFA(t, challenge.MFAToken, code, &mfaOutput))
+	assert.Equal(t, authOutput.User.ID, mfaOutput.User.ID)
+}
+
+func TestOIDCCallback_Failure_InvalidState(t *testing.T) {
+	// Given a login with the provider that was completed
+	callbackPath := AuthorizeOIDC(t, StartOIDCLogin(t), NewMockOIDCUser())
+	require.Equal(t, http.StatusOK, OIDCCallback(t, callbackPath, nil))
+
+	// When replaying the callback
+	statusCode := OIDCCallback(t, callbackPath, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// When calling the callback with a made up state
+	statusCode = OIDCCallback(t, "/api/auth/oidc/mock/callback?code=abc&state=forged", nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// When the provider reports an error
+	statusCode = OIDCCallback(t, "/api/auth/oidc/mock/callback?error=access_denied", nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestOIDCLogin_Failure_UnknownProvider(t *testing.T) {
+	// When starting a login with a provider that isn't configured
+	resp := getWithoutRedirect(t, TestServer.URL+"/api/auth/oidc/unknown")
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestOIDCLink_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When linking a provider account
+	var authorization domain.OIDCAuthorization
+	require.Equal(t, http.StatusOK, StartOIDCLink(t, user.Token, &authorization))
+	providerUser := NewMockOIDCUser()
+	var identity domain.UserIdentity
+	statusCode := OIDCCallback(t, AuthorizeOIDC(t, authorization.AuthorizationURL, providerUser), &identity)
+
+	// Then the provider account should be linked
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "mock", identity.Provider)
+
+	// And logging in with it should log the user in
+	var authOutput domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginWithOIDC(t, providerUser, &authOutput))
+	assert.Equal(t, user.User.ID, authOutput.User.ID)
+}
+
+func TestOIDCLink_Failure_AlreadyLinkedToAnotherUser(t *testing.T) {
+	// Given a provider account linked to a user
+	providerUser := NewMockOIDCUser()
+	require.Equal(t, http.StatusOK, LoginWithOIDC(t, providerUser, nil))
+
+	// When another user links it
+	otherUser := CreateTestUser(t)
+	var authorization domain.OIDCAuthorization
+	require.Equal(t, http.StatusOK, StartOIDCLink(t, otherUser.Token, &authorization))
+	statusCode := OIDCCallback(t, AuthorizeOIDC(t, authorization.AuthorizationURL, providerUser), nil)
+
+	// Then it should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestOIDCLink_Failure_MissingAccountScope(t *testing.T) {
+	// Given a user logged in with the read scope only
+	user := CreateTestUser(t)
+	var authOutput domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{domain.ScopeRead}}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &authOutput))
+
+	// When linking a provider account
+	statusCode := StartOIDCLink(t, authOutput.Token, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestUnlinkIdentity_Success(t *testing.T) {
+	// Given a user created by logging in with a provider
+	providerUser := NewMockOIDCUser()
+	var authOutput domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginWithOIDC(t, providerUser, &authOutput))
+	var identities []domain.UserIdentity
+	require.Equal(t, http.StatusOK, ListIdentities(t, authOutput.Token, &identities))
+	require.Len(t, identities, 1)
+	identityID := uuid.UUID(identities[0].ID.Bytes)
+
+	// When another user unlinks the provider account
+	otherUser := CreateTestUser(t)
+	statusCode := UnlinkIdentity(t, otherUser.Token, identityID)
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// When the user unlinks it
+	statusCode = UnlinkIdentity(t, authOutput.Token, identityID)
+
+	// Then it should be unlinked
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	require.Equal(t, http.StatusOK, ListIdentities(t, authOutput.Token, &identities))
+	assert.Empty(t, identities)
+
+	// And logging in with it again should not log the user in, their email address is taken
+	assert.Equal(t, http.StatusConflict, LoginWithOIDC(t, providerUser, nil))
+}
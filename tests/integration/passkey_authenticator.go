@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+)
+
+// Authenticator data flags, user present and user verified are always set, attested credential data on registrations
+const (
+	authenticatorFlagsAssertion   = 0x05
+	authenticatorFlagsAttestation = 0x45
+)
+
+// SoftwareAuthenticator is a passkey authenticator holding a single P-256 credential in memory.
+// It answers the options returned by the server the way navigator.credentials.create() and get() would.
+type SoftwareAuthenticator struct {
+	RPID   string
+	Origin string
+
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+// NewSoftwareAuthenticator creates an authenticator for the relying party of the test server
+func NewSoftwareAuthenticator() (*SoftwareAuthenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID := make([]byte, 32)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+
+	return &SoftwareAuthenticator{
+		RPID:         TestWebAuthnRPID,
+		Origin:       TestWebAuthnOrigin,
+		CredentialID: credentialID,
+		key:          key,
+	}, nil
+}
+
+// ceremonyOptions holds the part of the options of a ceremony the authenticator needs
+type ceremonyOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		User      struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+// Create answers registration options with a new credential and a "none" attestation
+func (authenticator *SoftwareAuthenticator) Create(options json.RawMessage) (json.RawMessage, error) {
+	var creation ceremonyOptions
+	if err := json.Unmarshal(options, &creation); err != nil {
+		return nil, err
+	}
+	userHandle, err := base64.RawURLEncoding.DecodeString(creation.PublicKey.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	authenticator.UserHandle = userHandle
+
+	clientData, err := authenticator.clientData("webauthn.create", creation.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // EC2 key type
+		3:  -7, // ES256
+		-1: 1,  // P-256
+		-2: authenticator.key.X.FillBytes(make([]byte, 32)),
+		-3: authenticator.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authData := authenticator.authData(authenticatorFlagsAttestation, authenticator.SignCount)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(authenticator.CredentialID)))
+	authData = append(authData, authenticator.CredentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticator.credential(map[string]any{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		"transports":        []string{"internal"},
+	})
+}
+
+// Get answers login options with an assertion signed by the credential, the signature counter goes up by one
+func (authenticator *SoftwareAuthenticator) Get(options json.RawMessage) (json.RawMessage, error) {
+	authenticator.SignCount++
+	return authenticator.GetWithCount(options, authenticator.SignCount)
+}
+
+// GetWithCount answers login options with an assertion reporting the given signature counter
+func (authenticator *SoftwareAuthenticator) GetWithCount(options json.RawMessage, signCount uint32) (json.RawMessage, error) {
+	if authenticator.UserHandle == nil {
+		return nil, errors.New("the credential was never created")
+	}
+
+	var assertion ceremonyOptions
+	if err := json.Unmarshal(options, &assertion); err != nil {
+		return nil, err
+	}
+
+	clientData, err := authenticator.clientData("webauthn.get", assertion.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	authData := authenticator.authData(authenticatorFlagsAssertion, signCount)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, authenticator.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticator.credential(map[string]any{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		"userHandle":        base64.RawURLEncoding.EncodeToString(authenticator.UserHandle),
+	})
+}
+
+// authData starts the authenticator data with the hash of the relying party ID, the flags and the signature counter
+func (authenticator *SoftwareAuthenticator) authData(flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(authenticator.RPID))
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, signCount)
+}
+
+func (authenticator *SoftwareAuthenticator) clientData(ceremony, challenge string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      authenticator.Origin,
+		"crossOrigin": false,
+	})
+}
+
+func (authenticator *SoftwareAuthenticator) credential(response map[string]any) (json.RawMessage, error) {
+	credentialID := base64.RawURLEncoding.EncodeToString(authenticator.CredentialID)
+	return json.Marshal(map[string]any{
+		"id":       credentialID,
+		"rawId":    credentialID,
+		"type":     "public-key",
+		"response": response,
+	})
+}
@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasskeyRegistration_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When registering a passkey
+	_, passkey := RegisterTestPasskey(t, user.Token)
+
+	// Then it should be listed with the user's passkeys
+	assert.Equal(t, "Test passkey", passkey.Name)
+	var passkeys []domain.Passkey
+	require.Equal(t, http.StatusOK, ListPasskeys(t, user.Token, &passkeys))
+	require.Len(t, passkeys, 1)
+	assert.Equal(t, passkey.ID, passkeys[0].ID)
+	assert.Equal(t, []string{"internal"}, passkeys[0].Transports)
+	assert.False(t, passkeys[0].LastUsedAt.Valid)
+}
+
+func TestPasskeyRegistration_Success_MultipleAuthenticators(t *testing.T) {
+	// Given a user with two passkeys on different authenticators
+	user := CreateTestUser(t)
+	laptop, _ := RegisterTestPasskey(t, user.Token)
+	phone, _ := RegisterTestPasskey(t, user.Token)
+
+	var passkeys []domain.Passkey
+	require.Equal(t, http.StatusOK, ListPasskeys(t, user.Token, &passkeys))
+	assert.Len(t, passkeys, 2)
+
+	// When logging in with each of them
+	var laptopLogin, phoneLogin domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginWithPasskey(t, laptop, &laptopLogin))
+	require.Equal(t, http.StatusOK, LoginWithPasskey(t, phone, &phoneLogin))
+
+	// Then both should log the same user in
+	assert.Equal(t, user.User.ID, laptopLogin.User.ID)
+	assert.Equal(t, user.User.ID, phoneLogin.User.ID)
+}
+
+func TestPasskeyRegistration_Failure_AlreadyRegistered(t *testing.T) {
+	// Given an authenticator whose passkey is registered
+	user := CreateTestUser(t)
+	authenticator, _ := RegisterTestPasskey(t, user.Token)
+
+	// When registering the same credential again
+	var options domain.PasskeyOptions
+	require.Equal(t, http.StatusOK, StartPasskeyRegistration(t, user.Token, &options))
+	credential, err := authenticator.Create(options.Options)
+	require.NoError(t, err)
+	input := domain.PasskeyRegistrationInput{SessionID: options.SessionID, Name: "Again", Credential: credential}
+	statusCode := FinishPasskeyRegistration(t, user.Token, input, nil)
+
+	// Then it should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestPasskeyRegistration_Failure_InvalidSession(t *testing.T) {
+	// Given registration options started by a user
+	user := CreateTestUser(t)
+	var options domain.PasskeyOptions
+	require.Equal(t, http.StatusOK, StartPasskeyRegistration(t, user.Token, &options))
+	authenticator, err := NewSoftwareAuthenticator()
+	require.NoError(t, err)
+	credential, err := authenticator.Create(options.Options)
+	require.NoError(t, err)
+
+	// When another user completes the registration with their session
+	otherUser := CreateTestUser(t)
+	input := domain.PasskeyRegistrationInput{SessionID: options.SessionID, Name: "Stolen", Credential: credential}
+	statusCode := FinishPasskeyRegistration(t, otherUser.Token, input, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// When completing it without a name
+	require.Equal(t, http.StatusOK, StartPasskeyRegistration(t, user.Token, &options))
+	input = domain.PasskeyRegistrationInput{SessionID: options.SessionID, Credential: credential}
+	statusCode = FinishPasskeyRegistration(t, user.Token, input, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestPasskeyLogin_Success(t *testing.T) {
+	// Given a user with a passkey
+	user := CreateTestUser(t)
+	authenticator, _ := RegisterTestPasskey(t, user.Token)
+
+	// When logging in with it, without a username or password
+	var authOutput domain.AuthOutput
+	statusCode := LoginWithPasskey(t, authenticator, &authOutput)
+
+	// Then the user should be logged in as with a password
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, authOutput.Token)
+	assert.NotEmpty(t, authOutput.RefreshToken)
+	assert.Equal(t, user.User.ID, authOutput.User.ID)
+	assert.Equal(t, http.StatusOK, GetMe(t, authOutput.Token))
+
+	// And the passkey should be marked as used
+	var passkeys []domain.Passkey
+	require.Equal(t, http.StatusOK, ListPasskeys(t, user.Token, &passkeys))
+	require.Len(t, passkeys, 1)
+	assert.True(t, passkeys[0].LastUsedAt.Valid)
+}
+
+func TestPasskeyLogin_Success_MFAEnabled(t *testing.T) {
+	// Given a user with two-factor authentication enabled and a passkey
+	user := CreateTestUser(t)
+	EnableTestMFA(t, user)
+	authenticator, _ := RegisterTestPasskey(t, user.Token)
+
+	// When logging in with the passkey
+	var authOutput domain.AuthOutput
+	statusCode := LoginWithPasskey(t, authenticator, &authOutput)
+
+	// Then no MFA challenge should be asked for, the authenticator already verified the user
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEmpty(t, authOutput.Token)
+}
+
+func TestPasskeyLogin_Failure_ReplayedSignCount(t *testing.T) {
+	// Given a passkey that was used to log in
+	user := CreateTestUser(t)
+	authenticator, _ := RegisterTestPasskey(t, user.Token)
+	require.Equal(t, http.StatusOK, LoginWithPasskey(t, authenticator, nil))
+
+	// When logging in with an assertion that doesn't move the signature counter forward, as a cloned authenticator would
+	options := StartPasskeyLogin(t)
+	credential, err := authenticator.GetWithCount(options.Options, authenticator.SignCount)
+	require.NoError(t, err)
+	statusCode := FinishPasskeyLogin(t, domain.PasskeyLoginInput{SessionID: options.SessionID, Credential: credential}, nil)
+
+	// Then it should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// And the authenticator should keep working once its counter moves forward
+	assert.Equal(t, http.StatusOK, LoginWithPasskey(t, authenticator, nil))
+}
+
+func TestPasskeyLogin_Failure_InvalidSession(t *testing.T) {
+	// Given a passkey login that was completed
+	user := CreateTestUser(t)
+	authenticator, _ := RegisterTestPasskey(t, user.Token)
+	options := StartPasskeyLogin(t)
+	credential, err := authenticator.Get(options.Options)
+	require.NoError(t, err)
+	input := domain.PasskeyLoginInput{SessionID: options.SessionID, Credential: credential}
+	require.Equal(t, http.StatusOK, FinishPasskeyLogin(t, input, nil))
+
+	// When replaying it
+	statusCode := FinishPasskeyLogin(t, input, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// When answering the challenge of one session with another session
+	options = StartPasskeyLogin(t)
+	credential, err = authenticator.Get(options.Options)
+	require.NoError(t, err)
+	otherOptions := StartPasskeyLogin(t)
+	statusCode = FinishPasskeyLogin(t, domain.PasskeyLoginInput{SessionID: otherOptions.SessionID, Credential: credential}, nil)
+
+	// Then it should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestPasskeyLogin_Failure_UnknownPasskey(t *testing.T) {
+	// Given an authenticator whose passkey was never registered
+	user := CreateTestUser(t)
+	authenticator, err := NewSoftwareAuthenticator()
+	require.NoError(t, err)
+	authenticator.UserHandle = user.User.ID.Bytes[:]
+
+	// When logging in with it
+	statusCode := LoginWithPasskey(t, authenticator, nil)
+
+	// Then it should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestDeletePasskey_Success(t *testing.T) {
+	// Given a user with a passkey
+	user := CreateTestUser(t)
+	authenticator, passkey := RegisterTestPasskey(t, user.Token)
+	passkeyID := uuid.UUID(passkey.ID.Bytes)
+
+	// When another user removes it
+	otherUser := CreateTestUser(t)
+	statusCode := DeletePasskey(t, otherUser.Token, passkeyID)
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// When the user removes it
+	statusCode = DeletePasskey(t, user.Token, passkeyID)
+
+	// Then it should be removed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	var passkeys []domain.Passkey
+	require.Equal(t, http.StatusOK, ListPasskeys(t, user.Token, &passkeys))
+	assert.Empty(t, passkeys)
+
+	// And it should no longer log the user in
+	assert.Equal(t, http.StatusUnauthorized, LoginWithPasskey(t, authenticator, nil))
+}
+
+func TestPasskeys_Failure_MissingAccountScope(t *testing.T) {
+	// Given a user logged in with the read scope only
+	user := CreateTestUser(t)
+	var authOutput domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{domain.ScopeRead}}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &authOutput))
+
+	// When registering a passkey
+	statusCode := StartPasskeyRegistration(t, authOutput.Token, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
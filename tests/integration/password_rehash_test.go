@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/adapter/security"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getPasswordHash reads the stored password hash of a user
+func getPasswordHash(t *testing.T, user TestUser) string {
+	var hashedPassword string
+	err := TestDB.QueryRow(context.Background(), "SELECT hashed_password FROM users WHERE id = $1", user.User.ID).Scan(&hashedPassword)
+	require.NoError(t, err)
+
+	return hashedPassword
+}
+
+func TestLogin_Success_RehashesWeakPassword(t *testing.T) {
+	// Given a user whose password was hashed with weaker Argon2 parameters
+	user := CreateTestUser(t)
+	weakHash, err := security.NewArgon2Adapter(8*1024, 1, 1).HashPassword(user.Password)
+	require.NoError(t, err)
+	_, err = TestDB.Exec(context.Background(), "UPDATE users SET hashed_password = $2 WHERE id = $1", user.User.ID, weakHash)
+	require.NoError(t, err)
+
+	// When logging in
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	statusCode := LoginUser(t, input, nil)
+
+	// Then the login should succeed and the hash be upgraded to the current parameters
+	assert.Equal(t, http.StatusOK, statusCode)
+	upgradedHash := getPasswordHash(t, user)
+	assert.NotEqual(t, weakHash, upgradedHash)
+	assert.False(t, strings.Contains(upgradedHash, "$m=8192,t=1,p=1$"))
+
+	// And the password should still work with the new hash
+	assert.Equal(t, http.StatusOK, LoginUser(t, input, nil))
+	assert.Equal(t, upgradedHash, getPasswordHash(t, user))
+}
+
+func TestLogin_Failure_WrongPasswordKeepsWeakHash(t *testing.T) {
+	// Given a user whose password was hashed with weaker Argon2 parameters
+	user := CreateTestUser(t)
+	weakHash, err := security.NewArgon2Adapter(8*1024, 1, 1).HashPassword(user.Password)
+	require.NoError(t, err)
+	_, err = TestDB.Exec(context.Background(), "UPDATE users SET hashed_password = $2 WHERE id = $1", user.User.ID, weakHash)
+	require.NoError(t, err)
+
+	// When logging in with a wrong password
+	input := domain.LoginInput{Username: user.Username, Password: "wrong-password"}
+	statusCode, _ := LoginUserFromIP(t, input, RandomTestIP(t), nil)
+
+	// Then the login should fail and the hash be left as it is
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	assert.Equal(t, weakHash, getPasswordHash(t, user))
+}
@@ -0,0 +1,180 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPassword_Success(t *testing.T) {
+	// Given a user with an active session and an API key who forgot their password
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+
+	// When asking for a reset link
+	statusCode := ForgotPassword(t, user.Email)
+
+	// Then the request should be accepted and a reset email sent
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	token := ExtractLinkToken(t, WaitForEmail(t, user.Email, "/reset-password/"), "/reset-password")
+
+	// When resetting the password with the link
+	newPassword := "NewPassword456!"
+	statusCode = ResetPassword(t, token, newPassword)
+
+	// Then the reset should succeed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the existing session and API key should stop working
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, user.Token))
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, user.RefreshToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, apiKey.Key))
+
+	// And only the new password should work
+	var output domain.AuthOutput
+	assert.Equal(t, http.StatusUnauthorized, LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, nil))
+	assert.Equal(t, http.StatusOK, LoginUser(t, domain.LoginInput{Username: user.Username, Password: newPassword}, &output))
+
+	// And the link should not be usable again
+	assert.Equal(t, http.StatusBadRequest, ResetPassword(t, token, "AnotherPassword789!"))
+}
+
+func TestForgotPassword_Success_UnknownEmail(t *testing.T) {
+	// Given an email that doesn't belong to any account
+	email := "nobody_" + time.Now().Format("150405.000000") + "@example.com"
+
+	// When asking for a reset link
+	statusCode := ForgotPassword(t, email)
+
+	// Then the response should be the same as for an existing account
+	assert.Equal(t, http.StatusAccepted, statusCode)
+
+	// And no email should be sent
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(t, findLatestEmail(t, email, ""))
+}
+
+func TestForgotPassword_Failure_Throttled(t *testing.T) {
+	// Given a user who just asked for a reset link
+	user := CreateTestUser(t)
+	require.Equal(t, http.StatusAccepted, ForgotPassword(t, user.Email))
+	token := ExtractLinkToken(t, WaitForEmail(t, user.Email, "/reset-password/"), "/reset-password")
+
+	// When asking for another one right away
+	statusCode := ForgotPassword(t, user.Email)
+
+	// Then the response should be the same
+	assert.Equal(t, http.StatusAccepted, statusCode)
+
+	// And no new email should be sent
+	time.Sleep(200 * time.Millisecond)
+	latestToken := ExtractLinkToken(t, findLatestEmail(t, user.Email, "/reset-password/"), "/reset-password")
+	assert.Equal(t, token, latestToken)
+
+	// And the first link should still work
+	assert.Equal(t, http.StatusNoContent, ResetPassword(t, token, "NewPassword456!"))
+}
+
+func TestResetPassword_Failure_InvalidToken(t *testing.T) {
+	// When resetting a password with an unknown token
+	statusCode := ResetPassword(t, "not-a-reset-token", "NewPassword456!")
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestResetPassword_Failure_WeakPassword(t *testing.T) {
+	// Given a reset link
+	user := CreateTestUser(t)
+	require.Equal(t, http.StatusAccepted, ForgotPassword(t, user.Email))
+	token := ExtractLinkToken(t, WaitForEmail(t, user.Email, "/reset-password/"), "/reset-password")
+
+	// When resetting with a password that is too short
+	statusCode := ResetPassword(t, token, "short")
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// And the link should still work
+	assert.Equal(t, http.StatusNoContent, ResetPassword(t, token, "NewPassword456!"))
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	// Given a user with an API key logged in on two devices
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+	var otherSession domain.AuthOutput
+	status := LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, &otherSession)
+	require.Equal(t, http.StatusOK, status)
+
+	// When changing the password from the first one
+	newPassword := "NewPassword456!"
+	statusCode := ChangePassword(t, user.Token, user.Password, newPassword)
+
+	// Then the change should succeed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the current session should keep working
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+	assert.Equal(t, http.StatusOK, RefreshTokens(t, user.RefreshToken, nil))
+
+	// And the other session should be ended
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, otherSession.Token))
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, otherSession.RefreshToken, nil))
+
+	// And the API key should keep working, it wasn't asked to be revoked
+	assert.Equal(t, http.StatusOK, GetMe(t, apiKey.Key))
+
+	// And only the new password should work
+	assert.Equal(t, http.StatusUnauthorized, LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, nil))
+	assert.Equal(t, http.StatusOK, LoginUser(t, domain.LoginInput{Username: user.Username, Password: newPassword}, nil))
+}
+
+func TestChangePassword_Success_RevokeAPIKeys(t *testing.T) {
+	// Given a logged user with an API key
+	user := CreateTestUser(t)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+
+	// When changing the password and asking to revoke the API keys
+	statusCode := SendAuthenticatedRequest(t, "PUT", "/api/me/password", user.Token, domain.ChangePasswordInput{
+		CurrentPassword: user.Password,
+		NewPassword:     "NewPassword456!",
+		RevokeAPIKeys:   true,
+	}, nil)
+
+	// Then the change should succeed
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And the API key should stop working while the current session keeps going
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, apiKey.Key))
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+}
+
+func TestChangePassword_Failure_WrongCurrentPassword(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When changing the password with a wrong current password
+	statusCode := ChangePassword(t, user.Token, "WrongPassword!", "NewPassword456!")
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+
+	// And the old password should still work
+	assert.Equal(t, http.StatusOK, LoginUser(t, domain.LoginInput{Username: user.Username, Password: user.Password}, nil))
+}
+
+func TestChangePassword_Failure_Unauthorized(t *testing.T) {
+	// When changing a password without being logged
+	statusCode := SendRequest(t, "PUT", "/api/me/password", domain.ChangePasswordInput{
+		CurrentPassword: "Password123!",
+		NewPassword:     "NewPassword456!",
+	}, nil)
+
+	// Then it should fail with an unauthorized status
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/knands42/lorecrafter/app/api/utils"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginScopes_Success_ReadOnly(t *testing.T) {
+	// Given a user logged in with a read-only token
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, true)
+	var output domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{domain.ScopeRead}}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &output))
+
+	// When reading a campaign
+	statusCode := GetCampaign(t, output.Token, campaign.ID.Bytes, nil)
+
+	// Then it should succeed
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// When updating the campaign
+	var errorResponse utils.ErrorResponse
+	updateInput := domain.UpdateCampaignInput{Title: "Renamed Campaign", SettingSummary: "Read-only tokens can't do this"}
+	statusCode = UpdateCampaign(t, output.Token, campaign.ID.Bytes, updateInput, &errorResponse)
+
+	// Then it should fail with a forbidden status naming the missing scope
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Contains(t, errorResponse.Error, domain.ScopeCampaignsWrite)
+
+	// And the token should not be able to manage the account
+	statusCode = ChangePassword(t, output.Token, user.Password, "new-password-123")
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestLoginScopes_Success_DefaultsToAllScopes(t *testing.T) {
+	// Given a user
+	user := CreateTestUser(t)
+
+	// When logging in without asking for scopes
+	var output domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &output))
+
+	// Then the token should be able to read, write and manage the account
+	assert.Equal(t, http.StatusOK, GetMe(t, output.Token))
+	assert.Equal(t, http.StatusCreated, CreateCampaign(t, output.Token, domain.CampaignCreationInput{
+		Title:          "Full Access Campaign",
+		SettingSummary: "Created with a full access token",
+	}, nil))
+	assert.Equal(t, http.StatusOK, EnrollMFA(t, output.Token, nil))
+}
+
+func TestLoginScopes_Success_KeptOnRefresh(t *testing.T) {
+	// Given a user logged in with a read-only token
+	user := CreateTestUser(t)
+	var output domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{domain.ScopeRead}}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &output))
+
+	// When refreshing the session
+	var refreshed domain.AuthOutput
+	require.Equal(t, http.StatusOK, RefreshTokens(t, output.RefreshToken, &refreshed))
+
+	// Then the new token should still be read-only
+	assert.Equal(t, http.StatusOK, GetMe(t, refreshed.Token))
+	assert.Equal(t, http.StatusForbidden, CreateCampaign(t, refreshed.Token, domain.CampaignCreationInput{
+		Title:          "Refreshed Campaign",
+		SettingSummary: "Read-only tokens can't do this",
+	}, nil))
+}
+
+func TestLoginScopes_Failure_UnknownScope(t *testing.T) {
+	// Given a user
+	user := CreateTestUser(t)
+
+	// When logging in asking for an unknown scope
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{"admin"}}
+	statusCode := LoginUser(t, input, nil)
+
+	// Then it should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestAPIKeyScopes_Failure_MissingScopeInBody(t *testing.T) {
+	// Given a user with a read API key
+	user := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, user.Token, true)
+	apiKey := CreateTestAPIKey(t, user.Token, domain.ScopeRead)
+
+	// When creating a character with the key
+	var errorResponse utils.ErrorResponse
+	input := domain.CharacterInput{Name: "Scripted Hero"}
+	statusCode := CreateCharacter(t, apiKey.Key, campaign.ID.Bytes, input, &errorResponse)
+
+	// Then it should fail with a forbidden status naming the missing scope
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Contains(t, errorResponse.Error, domain.ScopeCharactersWrite)
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSessions_Success(t *testing.T) {
+	// Given a user logged in on another device
+	user := CreateTestUser(t)
+	ipAddress := RandomTestIP(t)
+	var phone domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	require.Equal(t, http.StatusOK, LoginUserFromDevice(t, input, "LoreCrafter Phone/1.0", ipAddress, &phone))
+
+	// When listing the sessions from that device
+	var sessions []domain.Session
+	statusCode := ListSessions(t, phone.Token, &sessions)
+
+	// Then both sessions should be listed, with the device of the request flagged as current
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, sessions, 2)
+	current := CurrentSession(t, phone.Token)
+	assert.Equal(t, "LoreCrafter Phone/1.0", current.UserAgent)
+	assert.Equal(t, ipAddress, current.IPAddress)
+	assert.True(t, current.LastSeenAt.Valid)
+	assert.True(t, current.CreatedAt.Valid)
+	assert.NotEqual(t, current.ID, CurrentSession(t, user.Token).ID)
+}
+
+func TestListSessions_Success_RefreshKeepsSession(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+	session := CurrentSession(t, user.Token)
+
+	// When refreshing the tokens
+	var refreshed domain.AuthOutput
+	require.Equal(t, http.StatusOK, RefreshTokens(t, user.RefreshToken, &refreshed))
+
+	// Then the new tokens should belong to the same session
+	var sessions []domain.Session
+	require.Equal(t, http.StatusOK, ListSessions(t, refreshed.Token, &sessions))
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, session.ID, CurrentSession(t, refreshed.Token).ID)
+}
+
+func TestRevokeSession_Success(t *testing.T) {
+	// Given a user logged in on another device
+	user := CreateTestUser(t)
+	var laptop domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &laptop))
+	laptopSession := CurrentSession(t, laptop.Token)
+
+	// When ending the session of that device from the first one
+	statusCode := RevokeSession(t, user.Token, uuid.UUID(laptopSession.ID.Bytes))
+
+	// Then it should be ended
+	assert.Equal(t, http.StatusNoContent, statusCode)
+
+	// And its tokens should stop working right away
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, laptop.Token))
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, laptop.RefreshToken, nil))
+
+	// And the session of the request should keep working
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+	var sessions []domain.Session
+	require.Equal(t, http.StatusOK, ListSessions(t, user.Token, &sessions))
+	assert.Len(t, sessions, 1)
+}
+
+func TestRevokeSession_Failure_NotFound(t *testing.T) {
+	// Given a session of a user
+	user := CreateTestUser(t)
+	session := CurrentSession(t, user.Token)
+
+	// When another user ends it
+	otherUser := CreateTestUser(t)
+	statusCode := RevokeSession(t, otherUser.Token, uuid.UUID(session.ID.Bytes))
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+
+	// When ending a session that doesn't exist
+	statusCode = RevokeSession(t, user.Token, uuid.New())
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestRevokeOtherSessions_Success(t *testing.T) {
+	// Given a user logged in on three devices
+	user := CreateTestUser(t)
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	var laptop, phone domain.AuthOutput
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &laptop))
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &phone))
+
+	// When logging out everywhere else from the phone
+	statusCode := RevokeOtherSessions(t, phone.Token)
+
+	// Then the other sessions should stop working right away
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, user.Token))
+	assert.Equal(t, http.StatusUnauthorized, GetMe(t, laptop.Token))
+	assert.Equal(t, http.StatusUnauthorized, RefreshTokens(t, laptop.RefreshToken, nil))
+
+	// And only the phone should stay logged in
+	assert.Equal(t, http.StatusOK, GetMe(t, phone.Token))
+	var sessions []domain.Session
+	require.Equal(t, http.StatusOK, ListSessions(t, phone.Token, &sessions))
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Current)
+}
+
+func TestLogout_Success_EndsSession(t *testing.T) {
+	// Given a user logged in on another device
+	user := CreateTestUser(t)
+	var laptop domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &laptop))
+
+	// When logging out of it with its refresh token
+	require.Equal(t, http.StatusNoContent, Logout(t, laptop.Token, laptop.RefreshToken))
+
+	// Then it should no longer be listed
+	var sessions []domain.Session
+	require.Equal(t, http.StatusOK, ListSessions(t, user.Token, &sessions))
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Current)
+}
+
+func TestSessions_Failure_MissingAccountScope(t *testing.T) {
+	// Given a user logged in with the read scope only
+	user := CreateTestUser(t)
+	var authOutput domain.AuthOutput
+	input := domain.LoginInput{Username: user.Username, Password: user.Password, Scopes: []string{domain.ScopeRead}}
+	require.Equal(t, http.StatusOK, LoginUser(t, input, &authOutput))
+
+	// When logging out everywhere else
+	statusCode := RevokeOtherSessions(t, authOutput.Token)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+}
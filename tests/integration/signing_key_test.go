@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/adapter/security"
+	"github.com/knands42/lorecrafter/internal/config"
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/o1egl/paseto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signTestToken signs an access token of the user's session with the given key, PASETO version and audience
+func signTestToken(t *testing.T, user TestUser, key security.SigningKey, version, audience string) string {
+	tokenMaker, err := security.NewTokenMakerAdapter(key.PrivateKey, key.PublicKey, nil, version, audience)
+	require.NoError(t, err)
+
+	repo := sqlc.New(TestDB)
+	dbUser, err := repo.GetUserByUsername(context.Background(), user.Username)
+	require.NoError(t, err)
+	sessions, err := repo.ListActiveSessionsByUser(context.Background(), dbUser.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, sessions)
+
+	token, _, err := tokenMaker.CreateToken(dbUser, sessions[0].ID.Bytes, domain.AllScopes, time.Minute)
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestSigningKey_Success_KeyIDInFooter(t *testing.T) {
+	// Given the configured signing key
+	cfg, err := config.LoadConfig("../..")
+	require.NoError(t, err)
+	keyID, err := security.PublicKeyID(cfg.PublicKey)
+	require.NoError(t, err)
+
+	// When logging in
+	user := CreateTestUser(t)
+
+	// Then the token footer should name the signing key
+	var footer struct {
+		KeyID string `json:"kid"`
+	}
+	require.NoError(t, paseto.ParseFooter(user.Token, &footer))
+	assert.Equal(t, keyID, footer.KeyID)
+}
+
+func TestSigningKey_Success_PreviousKey(t *testing.T) {
+	// Given a token signed with a previous key that is still a verification key
+	user := CreateTestUser(t)
+	token := signTestToken(t, user, TestPreviousSigningKey, security.TokenVersionV2, TestTokenAudience)
+
+	// When using it
+	statusCode := GetMe(t, token)
+
+	// Then it should be accepted
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestSigningKey_Failure_UnknownKey(t *testing.T) {
+	// Given a token signed with a key the server doesn't know
+	user := CreateTestUser(t)
+	unknownKey, err := security.GenerateSigningKey()
+	require.NoError(t, err)
+	token := signTestToken(t, user, unknownKey, security.TokenVersionV2, TestTokenAudience)
+
+	// When using it
+	statusCode := GetMe(t, token)
+
+	// Then it should be unauthorized
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestTokenVersion_Success_V4(t *testing.T) {
+	// Given a v4.public token for the configured audience
+	user := CreateTestUser(t)
+	token := signTestToken(t, user, TestPreviousSigningKey, security.TokenVersionV4, TestTokenAudience)
+	require.True(t, strings.HasPrefix(token, "v4.public."))
+
+	// When using it
+	statusCode := GetMe(t, token)
+
+	// Then it should be accepted along with the v2 tokens
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, http.StatusOK, GetMe(t, user.Token))
+}
+
+func TestTokenVersion_Failure_V4OtherAudience(t *testing.T) {
+	// Given a v4.public token issued for another audience
+	user := CreateTestUser(t)
+	token := signTestToken(t, user, TestPreviousSigningKey, security.TokenVersionV4, "another-api")
+
+	// When using it
+	statusCode := GetMe(t, token)
+
+	// Then it should be unauthorized
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
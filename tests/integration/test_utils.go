@@ -1,14 +1,26 @@
 package integration
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	mathrand "math/rand"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/knands42/lorecrafter/internal/adapter/security"
 	"github.com/knands42/lorecrafter/internal/domain"
 	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
 	"github.com/stretchr/testify/require"
@@ -25,15 +37,28 @@ func DeleteUser(t *testing.T, username string) {
 
 // TestUser represents a test user
 type TestUser struct {
-	User     sqlc.User
-	Token    string
-	Username string
-	Email    string
-	Password string
+	User         domain.UserProfile
+	Token        string
+	RefreshToken string
+	Username     string
+	Email        string
+	Password     string
 }
 
-// CreateTestUser creates a test user with random credentials
+// CreateTestUser creates a test user with random credentials and a verified email address
 func CreateTestUser(t *testing.T) TestUser {
+	user := CreateUnverifiedTestUser(t)
+
+	// Skip the email round trip, the verification flow has its own tests
+	_, err := TestDB.Exec(context.Background(), "UPDATE users SET is_active = true WHERE id = $1", user.User.ID)
+	require.NoError(t, err)
+	user.User.IsActive = true
+
+	return user
+}
+
+// CreateUnverifiedTestUser creates a test user with random credentials who hasn't verified their email address
+func CreateUnverifiedTestUser(t *testing.T) TestUser {
 	// Generate random username and email
 	username := fmt.Sprintf("testuser_%s", uuid.New().String()[:8])
 	email := fmt.Sprintf("%s@example.com", username)
@@ -53,6 +78,7 @@ func CreateTestUser(t *testing.T) TestUser {
 	// Assert that the user was created successfully
 	require.Equal(t, http.StatusCreated, statusCode)
 	require.NotEmpty(t, authOutput.Token)
+	require.NotEmpty(t, authOutput.RefreshToken)
 	require.NotEmpty(t, authOutput.User.ID)
 	require.Equal(t, username, authOutput.User.Username)
 	require.Equal(t, email, authOutput.User.Email)
@@ -64,11 +90,12 @@ func CreateTestUser(t *testing.T) TestUser {
 
 	// Return the test user
 	return TestUser{
-		User:     authOutput.User,
-		Token:    authOutput.Token,
-		Username: username,
-		Email:    email,
-		Password: password,
+		User:         authOutput.User,
+		Token:        authOutput.Token,
+		RefreshToken: authOutput.RefreshToken,
+		Username:     username,
+		Email:        email,
+		Password:     password,
 	}
 }
 
@@ -82,6 +109,375 @@ func LoginUser(t *testing.T, input domain.LoginInput, output interface{}) int {
 	return SendRequest(t, "POST", "/api/auth/login", input, output)
 }
 
+// LoginUserFromIP logs in a user from the given client address
+func LoginUserFromIP(t *testing.T, input domain.LoginInput, ipAddress string, output interface{}) (int, http.Header) {
+	headers := map[string]string{"X-Forwarded-For": ipAddress}
+	return SendRequestWithHeaders(t, "POST", "/api/auth/login", headers, input, output)
+}
+
+// RandomTestIP returns a random private address so lockout tests don't affect each other
+func RandomTestIP(t *testing.T) string {
+	ipAddress := fmt.Sprintf("10.%d.%d.%d", mathrand.Intn(256), mathrand.Intn(256), mathrand.Intn(254)+1)
+	t.Cleanup(func() {
+		DeleteLoginThrottle(t, "ip:"+ipAddress)
+	})
+
+	return ipAddress
+}
+
+// DeleteLoginThrottle forgets the failed logins of an account or IP address key
+func DeleteLoginThrottle(t *testing.T, key string) {
+	_, err := TestDB.Exec(context.Background(), "DELETE FROM login_throttles WHERE key = $1", key)
+	if err != nil {
+		t.Logf("Error deleting login throttle %s: %v", key, err)
+	}
+}
+
+// RefreshTokens exchanges a refresh token for new tokens
+func RefreshTokens(t *testing.T, refreshToken string, output interface{}) int {
+	input := domain.RefreshTokenInput{RefreshToken: refreshToken}
+	return SendRequest(t, "POST", "/api/auth/refresh", input, output)
+}
+
+// Logout revokes the access token and the session of the refresh token
+func Logout(t *testing.T, token, refreshToken string) int {
+	input := domain.RefreshTokenInput{RefreshToken: refreshToken}
+	return SendAuthenticatedRequest(t, "POST", "/api/auth/logout", token, input, nil)
+}
+
+// LoginUserFromDevice logs in a user from the given user agent and client address
+func LoginUserFromDevice(t *testing.T, input domain.LoginInput, userAgent, ipAddress string, output interface{}) int {
+	headers := map[string]string{"User-Agent": userAgent, "X-Forwarded-For": ipAddress}
+	statusCode, _ := SendRequestWithHeaders(t, "POST", "/api/auth/login", headers, input, output)
+	return statusCode
+}
+
+// ListSessions lists the active sessions of the logged user
+func ListSessions(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/me/sessions", token, nil, output)
+}
+
+// RevokeSession ends a session of the logged user
+func RevokeSession(t *testing.T, token string, sessionID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/me/sessions/%s", sessionID), token, nil, nil)
+}
+
+// RevokeOtherSessions ends every session of the logged user except the current one
+func RevokeOtherSessions(t *testing.T, token string) int {
+	return SendAuthenticatedRequest(t, "DELETE", "/api/me/sessions", token, nil, nil)
+}
+
+// CurrentSession returns the session the token belongs to
+func CurrentSession(t *testing.T, token string) domain.Session {
+	var sessions []domain.Session
+	require.Equal(t, http.StatusOK, ListSessions(t, token, &sessions))
+	for _, session := range sessions {
+		if session.Current {
+			return session
+		}
+	}
+	require.Fail(t, "no current session")
+
+	return domain.Session{}
+}
+
+// VerifyEmail verifies an email address with the token of a verification link
+func VerifyEmail(t *testing.T, token string) int {
+	input := domain.VerifyEmailInput{Token: token}
+	return SendRequest(t, "POST", "/api/auth/verify", input, nil)
+}
+
+// ResendVerification asks for a new verification email
+func ResendVerification(t *testing.T, token string) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/auth/verify/resend", token, nil, nil)
+}
+
+// ForgotPassword requests a password reset link for an email
+func ForgotPassword(t *testing.T, email string) int {
+	return SendRequest(t, "POST", "/api/auth/forgot-password", domain.ForgotPasswordInput{Email: email}, nil)
+}
+
+// ResetPassword sets a new password with a reset token
+func ResetPassword(t *testing.T, token, newPassword string) int {
+	input := domain.ResetPasswordInput{Token: token, NewPassword: newPassword}
+	return SendRequest(t, "POST", "/api/auth/reset-password", input, nil)
+}
+
+// ChangePassword changes the password of the logged user
+func ChangePassword(t *testing.T, token, currentPassword, newPassword string) int {
+	input := domain.ChangePasswordInput{CurrentPassword: currentPassword, NewPassword: newPassword}
+	return SendAuthenticatedRequest(t, "PUT", "/api/me/password", token, input, nil)
+}
+
+// GetMe returns the status code of a request to the authenticated user endpoint
+func GetMe(t *testing.T, token string) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/me", token, nil, nil)
+}
+
+// CreateAPIKey creates an API key for the logged user
+func CreateAPIKey(t *testing.T, token string, input domain.APIKeyInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/me/api-keys", token, input, output)
+}
+
+// ListAPIKeys lists the API keys of the logged user
+func ListAPIKeys(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/me/api-keys", token, nil, output)
+}
+
+// RevokeAPIKey revokes an API key of the logged user
+func RevokeAPIKey(t *testing.T, token string, apiKeyID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/me/api-keys/%s", apiKeyID), token, nil, nil)
+}
+
+// CreateTestAPIKey creates an API key with the given scopes and returns it
+func CreateTestAPIKey(t *testing.T, token string, scopes ...string) domain.CreatedAPIKey {
+	var apiKey domain.CreatedAPIKey
+	input := domain.APIKeyInput{Name: "Test key", Scopes: scopes}
+	require.Equal(t, http.StatusCreated, CreateAPIKey(t, token, input, &apiKey))
+
+	return apiKey
+}
+
+// StartOIDCLogin starts a login with the mock provider and returns the URL of its login page
+func StartOIDCLogin(t *testing.T) string {
+	resp := getWithoutRedirect(t, TestServer.URL+"/api/auth/oidc/mock")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	return resp.Header.Get("Location")
+}
+
+// AuthorizeOIDC logs in at the mock provider as the user and returns the path of the callback it redirects to
+func AuthorizeOIDC(t *testing.T, authorizationURL string, user MockOIDCUser) string {
+	loginURL, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+
+	query := loginURL.Query()
+	query.Set("sub", user.Subject)
+	query.Set("email", user.Email)
+	query.Set("email_verified", fmt.Sprint(user.EmailVerified))
+	query.Set("name", user.Name)
+	loginURL.RawQuery = query.Encode()
+
+	resp := getWithoutRedirect(t, loginURL.String())
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callbackURL, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	return callbackURL.RequestURI()
+}
+
+// OIDCCallback sends the redirect back from the mock provider to the server
+func OIDCCallback(t *testing.T, callbackPath string, output interface{}) int {
+	return SendRequest(t, "GET", callbackPath, nil, output)
+}
+
+// LoginWithOIDC goes through the whole login with the mock provider as the user
+func LoginWithOIDC(t *testing.T, user MockOIDCUser, output interface{}) int {
+	return OIDCCallback(t, AuthorizeOIDC(t, StartOIDCLogin(t), user), output)
+}
+
+// NewMockOIDCUser returns a provider account with a random subject and a verified email address
+func NewMockOIDCUser() MockOIDCUser {
+	id := uuid.New().String()[:8]
+	return MockOIDCUser{
+		Subject:       "mock-" + id,
+		Email:         fmt.Sprintf("oidc_%s@example.com", id),
+		EmailVerified: true,
+		Name:          "Oidc " + id,
+	}
+}
+
+// StartOIDCLink starts linking a mock provider account to the logged user
+func StartOIDCLink(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/me/identities/mock", token, nil, output)
+}
+
+// ListIdentities lists the provider accounts linked to the logged user
+func ListIdentities(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/me/identities", token, nil, output)
+}
+
+// UnlinkIdentity unlinks a provider account from the logged user
+func UnlinkIdentity(t *testing.T, token string, identityID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/me/identities/%s", identityID), token, nil, nil)
+}
+
+// getWithoutRedirect sends a GET request and returns the response without following its redirect
+func getWithoutRedirect(t *testing.T, rawURL string) *http.Response {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(rawURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	return resp
+}
+
+// StartPasskeyRegistration gets the options to create a passkey for the logged user
+func StartPasskeyRegistration(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/me/passkeys/options", token, nil, output)
+}
+
+// FinishPasskeyRegistration saves the passkey created by an authenticator
+func FinishPasskeyRegistration(t *testing.T, token string, input domain.PasskeyRegistrationInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/me/passkeys", token, input, output)
+}
+
+// RegisterTestPasskey creates a passkey for the logged user with a new software authenticator
+func RegisterTestPasskey(t *testing.T, token string) (*SoftwareAuthenticator, domain.Passkey) {
+	authenticator, err := NewSoftwareAuthenticator()
+	require.NoError(t, err)
+
+	var options domain.PasskeyOptions
+	require.Equal(t, http.StatusOK, StartPasskeyRegistration(t, token, &options))
+	credential, err := authenticator.Create(options.Options)
+	require.NoError(t, err)
+
+	var passkey domain.Passkey
+	input := domain.PasskeyRegistrationInput{SessionID: options.SessionID, Name: "Test passkey", Credential: credential}
+	require.Equal(t, http.StatusCreated, FinishPasskeyRegistration(t, token, input, &passkey))
+
+	return authenticator, passkey
+}
+
+// ListPasskeys lists the passkeys of the logged user
+func ListPasskeys(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/me/passkeys", token, nil, output)
+}
+
+// DeletePasskey removes a passkey of the logged user
+func DeletePasskey(t *testing.T, token string, passkeyID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/me/passkeys/%s", passkeyID), token, nil, nil)
+}
+
+// StartPasskeyLogin gets the options to log in with a passkey
+func StartPasskeyLogin(t *testing.T) domain.PasskeyOptions {
+	var options domain.PasskeyOptions
+	require.Equal(t, http.StatusOK, SendRequest(t, "POST", "/api/auth/passkey/options", nil, &options))
+
+	return options
+}
+
+// FinishPasskeyLogin sends the assertion signed by an authenticator
+func FinishPasskeyLogin(t *testing.T, input domain.PasskeyLoginInput, output interface{}) int {
+	return SendRequest(t, "POST", "/api/auth/passkey", input, output)
+}
+
+// LoginWithPasskey goes through the whole passkey login with the authenticator
+func LoginWithPasskey(t *testing.T, authenticator *SoftwareAuthenticator, output interface{}) int {
+	options := StartPasskeyLogin(t)
+	credential, err := authenticator.Get(options.Options)
+	require.NoError(t, err)
+
+	return FinishPasskeyLogin(t, domain.PasskeyLoginInput{SessionID: options.SessionID, Credential: credential}, output)
+}
+
+// EnrollMFA starts enabling two-factor authentication for the logged user
+func EnrollMFA(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/me/mfa", token, nil, output)
+}
+
+// ConfirmMFA enables two-factor authentication with a code from the authenticator app
+func ConfirmMFA(t *testing.T, token, code string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", "/api/me/mfa/confirm", token, domain.MFACodeInput{Code: code}, output)
+}
+
+// DisableMFA turns two-factor authentication off for the logged user
+func DisableMFA(t *testing.T, token, password, code string) int {
+	input := domain.DisableMFAInput{Password: password, Code: code}
+	return SendAuthenticatedRequest(t, "DELETE", "/api/me/mfa", token, input, nil)
+}
+
+// LoginMFA completes a two-factor login
+func LoginMFA(t *testing.T, mfaToken, code string, output interface{}) int {
+	input := domain.MFALoginInput{MFAToken: mfaToken, Code: code}
+	return SendRequest(t, "POST", "/api/auth/mfa", input, output)
+}
+
+// EnableTestMFA enables two-factor authentication for a user and returns the TOTP secret and the recovery codes
+func EnableTestMFA(t *testing.T, user TestUser) (string, []string) {
+	var enrollment domain.MFAEnrollment
+	require.Equal(t, http.StatusOK, EnrollMFA(t, user.Token, &enrollment))
+
+	var recoveryCodes domain.MFARecoveryCodes
+	code := GenerateTOTPCode(t, enrollment.Secret, time.Now())
+	require.Equal(t, http.StatusOK, ConfirmMFA(t, user.Token, code, &recoveryCodes))
+
+	return enrollment.Secret, recoveryCodes.RecoveryCodes
+}
+
+// GenerateTOTPCode returns the code an authenticator app would show at the given time
+func GenerateTOTPCode(t *testing.T, secret string, at time.Time) string {
+	code, err := security.NewTOTPAdapter("LoreCrafter").GenerateCode(secret, at)
+	require.NoError(t, err)
+
+	return code
+}
+
+// UpdateMe updates the profile of the logged user
+func UpdateMe(t *testing.T, token string, input domain.UpdateProfileInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "PATCH", "/api/me", token, input, output)
+}
+
+// DeleteMe schedules the deletion of the account of the authenticated user
+func DeleteMe(t *testing.T, token, password string, output interface{}) int {
+	input := domain.DeleteAccountInput{Password: password}
+	return SendAuthenticatedRequest(t, "DELETE", "/api/me", token, input, output)
+}
+
+// ExportMe exports the data of the authenticated user as a JSON document
+func ExportMe(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/me/export", token, nil, output)
+}
+
+// ExportMeZip exports the data of the authenticated user as a ZIP archive
+func ExportMeZip(t *testing.T, token string) *zip.Reader {
+	req, err := http.NewRequest("GET", TestServer.URL+"/api/me/export?format=zip", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := TestClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+
+	return archive
+}
+
+// EndDeletionGracePeriod ends the grace period of a deleted account and runs the purge
+func EndDeletionGracePeriod(t *testing.T, userID pgtype.UUID) {
+	_, err := TestDB.Exec(context.Background(), "UPDATE users SET deletion_scheduled_at = CURRENT_TIMESTAMP WHERE id = $1 AND deletion_scheduled_at IS NOT NULL", userID)
+	require.NoError(t, err)
+
+	_, err = TestAPI.PurgeDeletedAccounts()
+	require.NoError(t, err)
+}
+
+// UserExists tells whether the account of a user is still in the database
+func UserExists(t *testing.T, userID pgtype.UUID) bool {
+	var exists bool
+	err := TestDB.QueryRow(context.Background(), "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists)
+	require.NoError(t, err)
+
+	return exists
+}
+
+// GetUserProfile gets the public profile of a user
+func GetUserProfile(t *testing.T, token, username string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/users/"+username, token, nil, output)
+}
+
 // CreateCampaign creates a new campaign
 func CreateCampaign(t *testing.T, token string, input domain.CampaignCreationInput, output interface{}) int {
 	return SendAuthenticatedRequest(t, "POST", "/api/campaigns", token, input, output)
@@ -97,18 +493,265 @@ func UpdateCampaign(t *testing.T, token string, campaignID uuid.UUID, input doma
 	return SendAuthenticatedRequest(t, "PUT", fmt.Sprintf("/api/campaigns/%s", campaignID), token, input, output)
 }
 
+// PatchCampaign applies a merge patch to a campaign, sending ifMatch as the If-Match header when set
+func PatchCampaign(t *testing.T, token string, campaignID uuid.UUID, patch map[string]any, ifMatch string, output interface{}) (int, http.Header) {
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/merge-patch+json",
+	}
+	if ifMatch != "" {
+		headers["If-Match"] = ifMatch
+	}
+
+	return SendRequestWithHeaders(t, "PATCH", fmt.Sprintf("/api/campaigns/%s", campaignID), headers, patch, output)
+}
+
+// GetCampaignETag returns the ETag of a campaign
+func GetCampaignETag(t *testing.T, token string, campaignID uuid.UUID) string {
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	statusCode, header := SendRequestWithHeaders(t, "GET", fmt.Sprintf("/api/campaigns/%s", campaignID), headers, nil, nil)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	return header.Get("ETag")
+}
+
 // DeleteCampaign deletes a campaign
 func DeleteCampaign(t *testing.T, token string, campaignID uuid.UUID) int {
 	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s", campaignID), token, nil, nil)
 }
 
-// ListUserCampaigns lists all campaigns a user is a member of
-func ListUserCampaigns(t *testing.T, token string, output interface{}) int {
-	return SendAuthenticatedRequest(t, "GET", "/api/campaigns", token, nil, output)
+// ListUserCampaigns lists the campaigns a user is a member of with the given query string parameters
+func ListUserCampaigns(t *testing.T, token string, query url.Values, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/campaigns?"+query.Encode(), token, nil, output)
+}
+
+// ArchiveCampaign archives a campaign
+func ArchiveCampaign(t *testing.T, token string, campaignID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/archive", campaignID), token, nil, output)
+}
+
+// UnarchiveCampaign brings an archived campaign back
+func UnarchiveCampaign(t *testing.T, token string, campaignID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s/archive", campaignID), token, nil, output)
+}
+
+// ListPublicCampaigns searches the public campaigns with the given query string parameters
+func ListPublicCampaigns(t *testing.T, token string, query url.Values, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/campaigns/public?"+query.Encode(), token, nil, output)
+}
+
+// CloneCampaign copies a public campaign into a new campaign of the user
+func CloneCampaign(t *testing.T, token string, campaignID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/clone", campaignID), token, nil, output)
+}
+
+// CreateTaggedCampaign creates a public campaign with the given title, setting summary and tags
+func CreateTaggedCampaign(t *testing.T, token, title, settingSummary string, tags ...string) sqlc.Campaign {
+	input := domain.CampaignCreationInput{
+		Title:          title,
+		SettingSummary: settingSummary,
+		IsPublic:       true,
+		Tags:           tags,
+	}
+
+	var campaign sqlc.Campaign
+	require.Equal(t, http.StatusCreated, CreateCampaign(t, token, input, &campaign))
+
+	return campaign
+}
+
+// CreateTestCampaign creates a campaign owned by the given user and returns it
+func CreateTestCampaign(t *testing.T, token string, isPublic bool) sqlc.Campaign {
+	input := domain.CampaignCreationInput{
+		Title:          fmt.Sprintf("Test Campaign %s", uuid.New().String()[:8]),
+		SettingSummary: "This is a test campaign",
+		IsPublic:       isPublic,
+	}
+
+	var campaign sqlc.Campaign
+	statusCode := CreateCampaign(t, token, input, &campaign)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	return campaign
+}
+
+// AddCampaignMember adds a user to a campaign with the given role
+func AddCampaignMember(t *testing.T, token string, campaignID, userID uuid.UUID, role string) int {
+	input := map[string]string{
+		"user_id": userID.String(),
+		"role":    role,
+	}
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/members", campaignID), token, input, nil)
+}
+
+// RemoveCampaignMember removes a user from a campaign
+func RemoveCampaignMember(t *testing.T, token string, campaignID, userID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s/members/%s", campaignID, userID), token, nil, nil)
+}
+
+// LeaveCampaign makes the authenticated user leave a campaign
+func LeaveCampaign(t *testing.T, token string, campaignID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/leave/%s", campaignID), token, nil, nil)
+}
+
+// ListCampaignMembers lists the members of a campaign
+func ListCampaignMembers(t *testing.T, token string, campaignID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", fmt.Sprintf("/api/campaigns/%s/members", campaignID), token, nil, output)
+}
+
+// GenerateInviteCode generates a new invite code for a campaign
+func GenerateInviteCode(t *testing.T, token string, campaignID uuid.UUID, input domain.InviteCodeInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/invite-code", campaignID), token, input, output)
+}
+
+// RevokeInviteCode revokes the invite code of a campaign
+func RevokeInviteCode(t *testing.T, token string, campaignID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s/invite-code", campaignID), token, nil, nil)
+}
+
+// JoinCampaign joins a campaign using an invite code
+func JoinCampaign(t *testing.T, token, inviteCode string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/join/%s", inviteCode), token, nil, output)
+}
+
+// InviteByEmail invites an email address to a campaign
+func InviteByEmail(t *testing.T, token string, campaignID uuid.UUID, email string, output interface{}) int {
+	input := domain.InvitationCreationInput{Email: email}
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/invitations", campaignID), token, input, output)
+}
+
+// ListCampaignInvitations lists the invitations of a campaign
+func ListCampaignInvitations(t *testing.T, token string, campaignID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", fmt.Sprintf("/api/campaigns/%s/invitations", campaignID), token, nil, output)
+}
+
+// ListUserInvitations lists the pending invitations of the authenticated user
+func ListUserInvitations(t *testing.T, token string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", "/api/invitations", token, nil, output)
+}
+
+// AcceptInvitation accepts an invitation by its token
+func AcceptInvitation(t *testing.T, token, invitationToken string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/invitations/%s/accept", invitationToken), token, nil, output)
+}
+
+// RejectInvitation rejects an invitation by its token
+func RejectInvitation(t *testing.T, token, invitationToken string, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/invitations/%s/reject", invitationToken), token, nil, output)
+}
+
+// CancelInvitation deletes an invitation of a campaign
+func CancelInvitation(t *testing.T, token string, campaignID, invitationID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s/invitations/%s", campaignID, invitationID), token, nil, nil)
+}
+
+// ReadLatestEmail returns the most recent email delivered to the recipient in the test maildir
+func ReadLatestEmail(t *testing.T, recipient string) string {
+	latest := findLatestEmail(t, recipient, "")
+	require.NotEmpty(t, latest, "no email found for %s", recipient)
+
+	return latest
+}
+
+// WaitForEmail waits for an email containing the given text to be delivered to the recipient, for emails sent in the background
+func WaitForEmail(t *testing.T, recipient, text string) string {
+	var email string
+	require.Eventually(t, func() bool {
+		email = findLatestEmail(t, recipient, text)
+		return email != ""
+	}, 5*time.Second, 50*time.Millisecond, "no email containing %s found for %s", text, recipient)
+
+	return email
+}
+
+// findLatestEmail returns the newest email delivered to the recipient that contains the given text, or an empty string
+func findLatestEmail(t *testing.T, recipient, text string) string {
+	entries, err := os.ReadDir(filepath.Join(TestMailDir, "new"))
+	require.NoError(t, err)
+
+	// Maildir file names start with the delivery timestamp, so the last match is the newest
+	var latest string
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(TestMailDir, "new", entry.Name()))
+		require.NoError(t, err)
+		if strings.Contains(string(content), "To: "+recipient+"\r\n") && strings.Contains(string(content), text) {
+			latest = string(content)
+		}
+	}
+
+	return latest
+}
+
+// ExtractLinkToken returns the token following the given path segment in an email body
+func ExtractLinkToken(t *testing.T, email, path string) string {
+	matches := regexp.MustCompile(regexp.QuoteMeta(path) + `/([A-Za-z0-9._\-]+)`).FindStringSubmatch(email)
+	require.Len(t, matches, 2, "no %s link found in email", path)
+
+	return matches[1]
+}
+
+// CreateCharacter creates a character in a campaign
+func CreateCharacter(t *testing.T, token string, campaignID uuid.UUID, input domain.CharacterInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/characters", campaignID), token, input, output)
+}
+
+// ListCharacters lists the characters of a campaign
+func ListCharacters(t *testing.T, token string, campaignID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", fmt.Sprintf("/api/campaigns/%s/characters", campaignID), token, nil, output)
+}
+
+// GetCharacter gets a character of a campaign
+func GetCharacter(t *testing.T, token string, campaignID, characterID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", fmt.Sprintf("/api/campaigns/%s/characters/%s", campaignID, characterID), token, nil, output)
+}
+
+// UpdateCharacter updates a character of a campaign
+func UpdateCharacter(t *testing.T, token string, campaignID, characterID uuid.UUID, input domain.CharacterInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "PUT", fmt.Sprintf("/api/campaigns/%s/characters/%s", campaignID, characterID), token, input, output)
+}
+
+// DeleteCharacter deletes a character of a campaign
+func DeleteCharacter(t *testing.T, token string, campaignID, characterID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s/characters/%s", campaignID, characterID), token, nil, nil)
+}
+
+// CreateTimelineEvent creates an event in a campaign timeline
+func CreateTimelineEvent(t *testing.T, token string, campaignID uuid.UUID, input domain.TimelineEventInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "POST", fmt.Sprintf("/api/campaigns/%s/timeline", campaignID), token, input, output)
+}
+
+// ListTimelineEvents lists the timeline of a campaign, optionally filtered by a query string
+func ListTimelineEvents(t *testing.T, token string, campaignID uuid.UUID, query string, output interface{}) int {
+	path := fmt.Sprintf("/api/campaigns/%s/timeline", campaignID)
+	if query != "" {
+		path += "?" + query
+	}
+	return SendAuthenticatedRequest(t, "GET", path, token, nil, output)
+}
+
+// GetTimelineEvent gets an event of a campaign timeline
+func GetTimelineEvent(t *testing.T, token string, campaignID, eventID uuid.UUID, output interface{}) int {
+	return SendAuthenticatedRequest(t, "GET", fmt.Sprintf("/api/campaigns/%s/timeline/%s", campaignID, eventID), token, nil, output)
+}
+
+// UpdateTimelineEvent updates an event of a campaign timeline
+func UpdateTimelineEvent(t *testing.T, token string, campaignID, eventID uuid.UUID, input domain.TimelineEventInput, output interface{}) int {
+	return SendAuthenticatedRequest(t, "PUT", fmt.Sprintf("/api/campaigns/%s/timeline/%s", campaignID, eventID), token, input, output)
+}
+
+// DeleteTimelineEvent deletes an event of a campaign timeline
+func DeleteTimelineEvent(t *testing.T, token string, campaignID, eventID uuid.UUID) int {
+	return SendAuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/campaigns/%s/timeline/%s", campaignID, eventID), token, nil, nil)
 }
 
 // SendRequest sends an HTTP request to the test server
 func SendRequest(t *testing.T, method, path string, body interface{}, output interface{}) int {
+	statusCode, _ := SendRequestWithHeaders(t, method, path, nil, body, output)
+	return statusCode
+}
+
+// SendRequestWithHeaders sends an HTTP request with extra headers to the test server and returns the response headers
+func SendRequestWithHeaders(t *testing.T, method, path string, headers map[string]string, body interface{}, output interface{}) (int, http.Header) {
 	// Create request body
 	var reqBody []byte
 	var err error
@@ -123,6 +766,9 @@ func SendRequest(t *testing.T, method, path string, body interface{}, output int
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	// Send request
 	resp, err := TestClient.Do(req)
@@ -135,6 +781,34 @@ func SendRequest(t *testing.T, method, path string, body interface{}, output int
 		require.NoError(t, err)
 	}
 
+	return resp.StatusCode, resp.Header
+}
+
+// SendRequestToServer sends an HTTP request to a server started by a test, authenticated when a token is given
+func SendRequestToServer(t *testing.T, server *httptest.Server, method, path, token string, body interface{}, output interface{}) int {
+	var reqBody []byte
+	var err error
+	if body != nil {
+		reqBody, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewBuffer(reqBody))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if output != nil && resp.StatusCode < 300 {
+		err = json.NewDecoder(resp.Body).Decode(output)
+		require.NoError(t, err)
+	}
+
 	return resp.StatusCode
 }
 
@@ -0,0 +1,164 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	sqlc "github.com/knands42/lorecrafter/pkg/sqlc/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTimelineEvent_Success(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When the GM adds an event without the visibility flag
+	eventDate := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	input := domain.TimelineEventInput{
+		Title:       "The fall of Greyhold",
+		Description: "The keep burns",
+		EventDate:   &eventDate,
+	}
+	var event sqlc.TimelineEvent
+	statusCode := CreateTimelineEvent(t, gm.Token, campaign.ID.Bytes, input, &event)
+
+	// Then the event should be created as public
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, input.Title, event.Title)
+	assert.True(t, event.IsPublic)
+	assert.True(t, eventDate.Equal(event.EventDate.Time))
+	assert.Equal(t, gm.User.ID, event.CreatedBy)
+}
+
+func TestCreateTimelineEvent_Failure_NotGM(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// When the player tries to add an event
+	statusCode := CreateTimelineEvent(t, player.Token, campaign.ID.Bytes, domain.TimelineEventInput{Title: "Rumours"}, nil)
+
+	// Then it should fail with a forbidden status
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func TestListTimelineEvents_Success_Visibility(t *testing.T) {
+	// Given a campaign with a player
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	player := CreateTestUser(t)
+	require.Equal(t, http.StatusNoContent, AddCampaignMember(t, gm.Token, campaign.ID.Bytes, player.User.ID.Bytes, "player"))
+
+	// And a public and a secret event
+	secret := false
+	var publicEvent, secretEvent sqlc.TimelineEvent
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, gm.Token, campaign.ID.Bytes, domain.TimelineEventInput{Title: "Festival"}, &publicEvent))
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, gm.Token, campaign.ID.Bytes, domain.TimelineEventInput{Title: "Assassination plot", IsPublic: &secret}, &secretEvent))
+
+	// When the GM lists the timeline
+	var gmEvents []sqlc.TimelineEvent
+	statusCode := ListTimelineEvents(t, gm.Token, campaign.ID.Bytes, "", &gmEvents)
+
+	// Then both events should be returned
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, gmEvents, 2)
+
+	// When the player lists the timeline
+	var playerEvents []sqlc.TimelineEvent
+	statusCode = ListTimelineEvents(t, player.Token, campaign.ID.Bytes, "", &playerEvents)
+
+	// Then only the public event should be returned
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, playerEvents, 1)
+	assert.Equal(t, publicEvent.ID, playerEvents[0].ID)
+
+	// And the secret event should not be found by the player
+	statusCode = GetTimelineEvent(t, player.Token, campaign.ID.Bytes, secretEvent.ID.Bytes, nil)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// But it should be found by the GM
+	statusCode = GetTimelineEvent(t, gm.Token, campaign.ID.Bytes, secretEvent.ID.Bytes, nil)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestListTimelineEvents_Success_ChronologicalWithRange(t *testing.T) {
+	// Given a campaign with events created out of order
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	dates := []time.Time{
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i := range dates {
+		input := domain.TimelineEventInput{Title: dates[i].Format(time.DateOnly), EventDate: &dates[i]}
+		require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, gm.Token, campaign.ID.Bytes, input, nil))
+	}
+
+	// When the GM lists the whole timeline
+	var events []sqlc.TimelineEvent
+	statusCode := ListTimelineEvents(t, gm.Token, campaign.ID.Bytes, "", &events)
+
+	// Then the events should be in chronological order
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, events, 3)
+	assert.Equal(t, "2024-01-01", events[0].Title)
+	assert.Equal(t, "2024-03-01", events[1].Title)
+	assert.Equal(t, "2024-05-01", events[2].Title)
+
+	// When the GM filters by a date range
+	statusCode = ListTimelineEvents(t, gm.Token, campaign.ID.Bytes, "from=2024-02-01&to=2024-05-01", &events)
+
+	// Then only the events within the range should be returned, including the last day
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, events, 2)
+	assert.Equal(t, "2024-03-01", events[0].Title)
+	assert.Equal(t, "2024-05-01", events[1].Title)
+}
+
+func TestListTimelineEvents_Failure_InvalidRange(t *testing.T) {
+	// Given a GM with a campaign
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+
+	// When the GM filters with an invalid date or an inverted range
+	invalidDateStatusCode := ListTimelineEvents(t, gm.Token, campaign.ID.Bytes, "from=yesterday", nil)
+	invertedRangeStatusCode := ListTimelineEvents(t, gm.Token, campaign.ID.Bytes, "from=2024-05-01&to=2024-01-01", nil)
+
+	// Then both should fail with a bad request status
+	assert.Equal(t, http.StatusBadRequest, invalidDateStatusCode)
+	assert.Equal(t, http.StatusBadRequest, invertedRangeStatusCode)
+}
+
+func TestUpdateAndDeleteTimelineEvent_Success(t *testing.T) {
+	// Given a GM with a public event
+	gm := CreateTestUser(t)
+	campaign := CreateTestCampaign(t, gm.Token, false)
+	var event sqlc.TimelineEvent
+	require.Equal(t, http.StatusCreated, CreateTimelineEvent(t, gm.Token, campaign.ID.Bytes, domain.TimelineEventInput{Title: "Festival"}, &event))
+
+	// When the GM turns it into a secret event
+	secret := false
+	var updated sqlc.TimelineEvent
+	statusCode := UpdateTimelineEvent(t, gm.Token, campaign.ID.Bytes, event.ID.Bytes, domain.TimelineEventInput{Title: "Festival ambush", IsPublic: &secret}, &updated)
+
+	// Then the event should be updated
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Festival ambush", updated.Title)
+	assert.False(t, updated.IsPublic)
+
+	// When the GM deletes it
+	statusCode = DeleteTimelineEvent(t, gm.Token, campaign.ID.Bytes, event.ID.Bytes)
+
+	// Then it should no longer be found
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	statusCode = GetTimelineEvent(t, gm.Token, campaign.ID.Bytes, event.ID.Bytes, nil)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
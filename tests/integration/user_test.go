@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/knands42/lorecrafter/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMe_Success(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When changing the username and avatar
+	newUsername := user.Username + "_new"
+	avatarURL := "https://example.com/avatar.png"
+	var profile domain.UserProfile
+	statusCode := UpdateMe(t, user.Token, domain.UpdateProfileInput{
+		Username:  &newUsername,
+		AvatarURL: &avatarURL,
+	}, &profile)
+	t.Cleanup(func() { DeleteUser(t, newUsername) })
+
+	// Then the profile should be updated
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, newUsername, profile.Username)
+	assert.Equal(t, avatarURL, profile.AvatarURL.String)
+
+	// And the fields left out should be kept
+	assert.Equal(t, user.Email, profile.Email)
+	assert.True(t, profile.IsActive)
+
+	// And the user should log in with the new username
+	assert.Equal(t, http.StatusOK, LoginUser(t, domain.LoginInput{Username: newUsername, Password: user.Password}, nil))
+
+	// When removing the avatar
+	emptyAvatarURL := ""
+	statusCode = UpdateMe(t, user.Token, domain.UpdateProfileInput{AvatarURL: &emptyAvatarURL}, &profile)
+
+	// Then the avatar should be cleared
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, profile.AvatarURL.Valid)
+}
+
+func TestUpdateMe_Success_EmailChangeRequiresVerification(t *testing.T) {
+	// Given a verified user
+	user := CreateTestUser(t)
+
+	// When changing the email address
+	newEmail := "new_" + user.Email
+	var profile domain.UserProfile
+	statusCode := UpdateMe(t, user.Token, domain.UpdateProfileInput{Email: &newEmail}, &profile)
+
+	// Then the email should be changed and marked as unverified
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, newEmail, profile.Email)
+	assert.False(t, profile.IsActive)
+
+	// And a verification email should be sent to the new address
+	token := ExtractLinkToken(t, ReadLatestEmail(t, newEmail), "/verify-email")
+	assert.Equal(t, http.StatusNoContent, VerifyEmail(t, token))
+
+	var isActive bool
+	err := TestDB.QueryRow(context.Background(), "SELECT is_active FROM users WHERE id = $1", user.User.ID).Scan(&isActive)
+	require.NoError(t, err)
+	assert.True(t, isActive)
+}
+
+func TestUpdateMe_Failure_Taken(t *testing.T) {
+	// Given two users
+	user := CreateTestUser(t)
+	other := CreateTestUser(t)
+
+	// When taking the username or email of the other user
+	usernameStatusCode := UpdateMe(t, user.Token, domain.UpdateProfileInput{Username: &other.Username}, nil)
+	emailStatusCode := UpdateMe(t, user.Token, domain.UpdateProfileInput{Email: &other.Email}, nil)
+
+	// Then both should fail with a conflict status
+	assert.Equal(t, http.StatusConflict, usernameStatusCode)
+	assert.Equal(t, http.StatusConflict, emailStatusCode)
+
+	// And keeping their own username should not be a conflict
+	assert.Equal(t, http.StatusOK, UpdateMe(t, user.Token, domain.UpdateProfileInput{Username: &user.Username}, nil))
+}
+
+func TestUpdateMe_Failure_InvalidInput(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+	emptyUsername := " "
+	invalidEmail := "not-an-email"
+	invalidAvatarURL := "javascript:alert(1)"
+
+	testCases := []struct {
+		name  string
+		input domain.UpdateProfileInput
+	}{
+		{name: "Empty username", input: domain.UpdateProfileInput{Username: &emptyUsername}},
+		{name: "Invalid email", input: domain.UpdateProfileInput{Email: &invalidEmail}},
+		{name: "Invalid avatar URL", input: domain.UpdateProfileInput{AvatarURL: &invalidAvatarURL}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// When updating the profile with invalid input
+			statusCode := UpdateMe(t, user.Token, tc.input, nil)
+
+			// Then it should fail with a bad request status
+			assert.Equal(t, http.StatusBadRequest, statusCode)
+		})
+	}
+}
+
+func TestGetUserProfile_Success(t *testing.T) {
+	// Given a user with a public and a private campaign
+	user := CreateTestUser(t)
+	publicCampaign := CreateTestCampaign(t, user.Token, true)
+	CreateTestCampaign(t, user.Token, false)
+
+	// And another logged user
+	viewer := CreateTestUser(t)
+
+	// When viewing the profile of the first user
+	var profile map[string]interface{}
+	statusCode := GetUserProfile(t, viewer.Token, user.Username, &profile)
+
+	// Then the public profile should be returned
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, user.Username, profile["username"])
+
+	// And it should not expose the email or the password hash
+	assert.NotContains(t, profile, "email")
+	assert.NotContains(t, profile, "hashed_password")
+
+	// And only the public campaign should be listed
+	var publicProfile domain.PublicUserProfile
+	require.Equal(t, http.StatusOK, GetUserProfile(t, viewer.Token, user.Username, &publicProfile))
+	require.Len(t, publicProfile.Campaigns, 1)
+	assert.Equal(t, publicCampaign.ID, publicProfile.Campaigns[0].ID)
+
+	// And it should not expose the setting or the invite code of the campaign
+	campaigns, ok := profile["campaigns"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, campaigns, 1)
+	campaign, ok := campaigns[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.NotContains(t, campaign, "setting")
+	assert.NotContains(t, campaign, "invite_code")
+	assert.NotContains(t, campaign, "invite_code_uses")
+}
+
+func TestGetUserProfile_Failure_NotFound(t *testing.T) {
+	// Given a logged user
+	user := CreateTestUser(t)
+
+	// When viewing the profile of a user that doesn't exist
+	statusCode := GetUserProfile(t, user.Token, "nobody_at_all", nil)
+
+	// Then it should fail with a not found status
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}